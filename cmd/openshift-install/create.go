@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -28,9 +30,12 @@ import (
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/installer/pkg/asset"
-	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/ignition"
 	targetassets "github.com/openshift/installer/pkg/asset/targets"
 	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/progress"
+	texec "github.com/openshift/installer/pkg/terraform/exec"
 	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 )
 
@@ -84,6 +89,10 @@ var (
 			// FIXME: add longer descriptions for our commands with examples for better UX.
 			// Long:  "",
 			PostRun: func(_ *cobra.Command, _ []string) {
+				if clusterOpts.dryRun {
+					return
+				}
+
 				ctx := context.Background()
 
 				cleanup := setupFileHook(rootOpts.dir)
@@ -96,11 +105,14 @@ var (
 
 				err = waitForBootstrapComplete(ctx, config, rootOpts.dir)
 				if err != nil {
+					gatherBootstrapOnFailure(rootOpts.dir)
 					logrus.Fatal(err)
 				}
 
 				logrus.Info("Destroying the bootstrap resources...")
+				progressReporter.PhaseStarted(progress.PhaseBootstrapDestroy, 0)
 				err = destroybootstrap.Destroy(rootOpts.dir)
+				progressReporter.PhaseFinished(progress.PhaseBootstrapDestroy, err)
 				if err != nil {
 					logrus.Fatal(err)
 				}
@@ -115,6 +127,11 @@ var (
 	}
 
 	targets = []target{installConfigTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget}
+
+	clusterOpts struct {
+		dryRun bool
+		resume bool
+	}
 )
 
 func newCreateCmd() *cobra.Command {
@@ -126,53 +143,195 @@ func newCreateCmd() *cobra.Command {
 		},
 	}
 
+	cmd.PersistentFlags().StringVar(&ignition.Spec, "ignition-spec", "", "Ignition spec of the generated configs (e.g. \"2 | 3\"), for the OS images whose Ignition only consumes spec 3 (also settable with OPENSHIFT_INSTALL_IGNITION_SPEC, default \"2\")")
+
 	for _, t := range targets {
 		t.command.Args = cobra.ExactArgs(0)
 		t.command.Run = runTargetCmd(t.assets...)
 		cmd.AddCommand(t.command)
 	}
 
+	runCluster := clusterTarget.command.Run
+	clusterTarget.command.Run = func(cmd *cobra.Command, args []string) {
+		if err := loadTimeoutsFromEnv(cmd.Flags(), clusterTimeouts...); err != nil {
+			logrus.Fatal(err)
+		}
+		switch {
+		case clusterOpts.dryRun && clusterOpts.resume:
+			logrus.Fatal("--dry-run and --resume cannot be used together")
+		case clusterOpts.dryRun:
+			runPlanCmd(cmd, args)
+		case clusterOpts.resume:
+			runResumeCmd(cmd, args)
+		default:
+			runCluster(cmd, args)
+		}
+	}
+	clusterTarget.command.Flags().BoolVar(&clusterOpts.dryRun, "dry-run", false, "render all assets and print the infrastructure resources that would be created, without creating them")
+	clusterTarget.command.Flags().BoolVar(&clusterOpts.resume, "resume", false, "resume the creation of a cluster from the Terraform state left by a failed 'create cluster'")
+	addTimeoutFlags(clusterTarget.command.Flags(), clusterTimeouts...)
+
 	return cmd
 }
 
 func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
 	runner := func(directory string) error {
-		assetStore, err := assetstore.NewStore(directory)
+		assetStore, err := newAssetStore(directory)
 		if err != nil {
 			return errors.Wrap(err, "failed to create asset store")
 		}
+		return fetchTargets(assetStore, directory, targets...)
+	}
 
-		for _, a := range targets {
-			err := assetStore.Fetch(a)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to fetch %s", a.Name())
-			}
+	return func(cmd *cobra.Command, args []string) {
+		cleanup := setupFileHook(rootOpts.dir)
+		defer cleanup()
 
-			if err2 := asset.PersistToFile(a, directory); err2 != nil {
-				err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", a.Name())
-				if err != nil {
-					logrus.Error(err2)
-					return err
-				}
-				return err2
-			}
+		err := runner(rootOpts.dir)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
+// fetchTargets fetches the given targets from the asset store and writes
+// them to disk.
+func fetchTargets(assetStore asset.Store, directory string, targets ...asset.WritableAsset) error {
+	for _, a := range targets {
+		err := assetStore.Fetch(a)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
 
+		if err2 := asset.PersistToFile(a, directory); err2 != nil {
+			err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", a.Name())
 			if err != nil {
+				logrus.Error(err2)
 				return err
 			}
+			return err2
+		}
+
+		if err != nil {
+			return err
 		}
-		return nil
 	}
+	return nil
+}
 
-	return func(cmd *cobra.Command, args []string) {
-		cleanup := setupFileHook(rootOpts.dir)
-		defer cleanup()
+// runPlanCmd renders all of the cluster assets, except for the cluster
+// itself, and prints the infrastructure resources that would be created.
+func runPlanCmd(_ *cobra.Command, _ []string) {
+	cleanup := setupFileHook(rootOpts.dir)
+	defer cleanup()
 
-		err := runner(rootOpts.dir)
+	changes, err := planCluster(rootOpts.dir)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	printPlan(os.Stdout, changes)
+}
+
+func planCluster(directory string) ([]texec.ResourceChange, error) {
+	parents, err := fetchClusterDependencies(directory)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Plan(parents)
+}
+
+// runResumeCmd re-runs the Terraform apply of a cluster whose creation
+// failed, before continuing with the rest of 'create cluster'.
+func runResumeCmd(_ *cobra.Command, _ []string) {
+	cleanup := setupFileHook(rootOpts.dir)
+	defer cleanup()
+
+	err := resumeCluster(rootOpts.dir)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+func resumeCluster(directory string) error {
+	parents, err := fetchClusterDependencies(directory)
+	if err != nil {
+		return err
+	}
+
+	c, err := cluster.Resume(directory, parents)
+	if c == nil {
+		return err
+	}
+	if err2 := asset.PersistToFile(c, directory); err2 != nil {
+		err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", c.Name())
 		if err != nil {
-			logrus.Fatal(err)
+			logrus.Error(err2)
+			return err
 		}
+		return err2
 	}
+	return err
+}
+
+// fetchClusterDependencies renders all of the cluster assets, except for
+// the cluster itself, and returns the dependencies of the cluster.
+func fetchClusterDependencies(directory string) (asset.Parents, error) {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create asset store")
+	}
+
+	targets := make([]asset.WritableAsset, 0, len(clusterTarget.assets))
+	for _, a := range clusterTarget.assets {
+		if _, ok := a.(*cluster.Cluster); ok {
+			continue
+		}
+		targets = append(targets, a)
+	}
+	if err := fetchTargets(assetStore, directory, targets...); err != nil {
+		return nil, err
+	}
+
+	parents := asset.Parents{}
+	for _, a := range (&cluster.Cluster{}).Dependencies() {
+		if err := assetStore.Fetch(a); err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+		parents.Add(a)
+	}
+	return parents, nil
+}
+
+// printPlan prints the planned changes, grouped by Terraform module.
+func printPlan(w io.Writer, changes []texec.ResourceChange) {
+	symbols := map[texec.Action]string{
+		texec.Create:  "+",
+		texec.Update:  "~",
+		texec.Replace: "-/+",
+		texec.Delete:  "-",
+	}
+	counts := map[texec.Action]int{}
+
+	module := ""
+	for i, change := range changes {
+		if i == 0 || change.Module != module {
+			module = change.Module
+			name := "root"
+			if module != "" {
+				name = fmt.Sprintf("module.%s", module)
+			}
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s:\n", name)
+		}
+		fmt.Fprintf(w, "  %s %s\n", symbols[change.Action], change.Address)
+		counts[change.Action]++
+	}
+	if len(changes) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to replace, %d to delete.\n", counts[texec.Create], counts[texec.Update], counts[texec.Replace], counts[texec.Delete])
 }
 
 // addRouterCAToClusterCA adds router CA to cluster CA in kubeconfig
@@ -235,9 +394,9 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config, director
 
 	discovery := client.Discovery()
 
-	apiTimeout := 30 * time.Minute
-	logrus.Infof("Waiting up to %v for the Kubernetes API at %s...", apiTimeout, config.Host)
-	apiContext, cancel := context.WithTimeout(ctx, apiTimeout)
+	logrus.Infof("Waiting up to %v for the Kubernetes API at %s...", apiTimeout.duration, config.Host)
+	progressReporter.PhaseStarted(progress.PhaseAPI, apiTimeout.duration)
+	apiContext, cancel := context.WithTimeout(ctx, apiTimeout.duration)
 	defer cancel()
 	// Poll quickly so we notice changes, but only log when the response
 	// changes (because that's interesting) or when we've seen 15 of the
@@ -245,12 +404,14 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config, director
 	logDownsample := 15
 	silenceRemaining := logDownsample
 	previousErrorSuffix := ""
+	var lastErr error
 	wait.Until(func() {
 		version, err := discovery.ServerVersion()
 		if err == nil {
 			logrus.Infof("API %s up", version)
 			cancel()
 		} else {
+			lastErr = err
 			silenceRemaining--
 			chunks := strings.Split(err.Error(), ":")
 			errorSuffix := chunks[len(chunks)-1]
@@ -266,24 +427,40 @@ func waitForBootstrapComplete(ctx context.Context, config *rest.Config, director
 	}, 2*time.Second, apiContext.Done())
 	err = apiContext.Err()
 	if err != nil && err != context.Canceled {
-		return errors.Wrap(err, "waiting for Kubernetes API")
+		if err == context.DeadlineExceeded {
+			lastStatus := ""
+			if lastErr != nil {
+				lastStatus = lastErr.Error()
+			}
+			err = apiTimeout.expired(lastStatus)
+		}
+		err = errors.Wrap(err, "waiting for Kubernetes API")
+		progressReporter.PhaseFinished(progress.PhaseAPI, err)
+		return err
 	}
+	progressReporter.PhaseFinished(progress.PhaseAPI, nil)
 
-	eventTimeout := 30 * time.Minute
-	logrus.Infof("Waiting up to %v for the bootstrap-complete event...", eventTimeout)
-	return waitForEvent(ctx, client.CoreV1().RESTClient(), "bootstrap-complete", eventTimeout)
+	logrus.Infof("Waiting up to %v for the bootstrap-complete event...", bootstrapTimeout.duration)
+	progressReporter.PhaseStarted(progress.PhaseBootstrap, bootstrapTimeout.duration)
+	err = waitForEvent(ctx, client.CoreV1().RESTClient(), "bootstrap-complete", bootstrapTimeout)
+	if err == nil {
+		progressReporter.Report(progress.Event{Type: progress.BootstrapComplete})
+	}
+	progressReporter.PhaseFinished(progress.PhaseBootstrap, err)
+	return err
 }
 
 // waitForEvent watches the events in the kube-system namespace, waits
 // for the event of the given name, and prints out all other events on
 // the way.
-func waitForEvent(ctx context.Context, client cache.Getter, name string, timeout time.Duration) error {
-	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+func waitForEvent(ctx context.Context, client cache.Getter, name string, timeout *phaseTimeout) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout.duration)
 	defer cancel()
 
 	resource := "events"
 	namespace := "kube-system"
 
+	lastStatus := ""
 	_, err := clientwatch.UntilWithSync(
 		waitCtx,
 		cache.NewListWatchFromClient(client, resource, namespace, fields.Everything()),
@@ -297,19 +474,27 @@ func waitForEvent(ctx context.Context, client cache.Getter, name string, timeout
 			}
 
 			logrus.Debugf("%s %s: %s", strings.ToLower(string(event.Type)), ev.Name, ev.Message)
+			lastStatus = fmt.Sprintf("event %s: %s", ev.Name, ev.Message)
 			found := ev.Name == name && (event.Type == watch.Added || event.Type == watch.Modified)
 			return found, nil
 		},
 	)
 
+	if err == wait.ErrWaitTimeout && waitCtx.Err() == context.DeadlineExceeded {
+		err = timeout.expired(lastStatus)
+	}
 	return errors.Wrapf(err, "failed to wait for %s event", name)
 }
 
 // waitForInitializedCluster watches the ClusterVersion waiting for confirmation
 // that the cluster has been initialized.
-func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
-	timeout := 30 * time.Minute
+func waitForInitializedCluster(ctx context.Context, config *rest.Config) (err error) {
+	timeout := initializationTimeout.duration
 	logrus.Infof("Waiting up to %v for the cluster at %s to initialize...", timeout, config.Host)
+	progressReporter.PhaseStarted(progress.PhaseInitialization, timeout)
+	defer func() {
+		progressReporter.PhaseFinished(progress.PhaseInitialization, err)
+	}()
 	cc, err := configclient.NewForConfig(config)
 	if err != nil {
 		return errors.Wrap(err, "failed to create a config client")
@@ -317,6 +502,10 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	clusterVersionContext, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if progressReporter != nil {
+		go watchClusterOperators(clusterVersionContext, cc)
+	}
+
 	var lastError string
 	_, err = clientwatch.UntilWithSync(
 		clusterVersionContext,
@@ -352,6 +541,10 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 		return nil
 	}
 
+	if err == wait.ErrWaitTimeout && clusterVersionContext.Err() == context.DeadlineExceeded {
+		return errors.Wrap(initializationTimeout.expired(lastError), "failed to initialize the cluster")
+	}
+
 	if lastError != "" {
 		return errors.Wrapf(err, "failed to initialize the cluster: %s", lastError)
 	}
@@ -360,8 +553,7 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 }
 
 // waitForConsole returns the console URL from the route 'console' in namespace openshift-console
-func waitForConsole(ctx context.Context, config *rest.Config, directory string) (string, error) {
-	url := ""
+func waitForConsole(ctx context.Context, config *rest.Config, directory string) (url string, err error) {
 	// Need to keep these updated if they change
 	consoleNamespace := "openshift-console"
 	consoleRouteName := "console"
@@ -370,8 +562,15 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 		return "", errors.Wrap(err, "creating a route client")
 	}
 
-	consoleRouteTimeout := 10 * time.Minute
+	consoleRouteTimeout := consoleTimeout.duration
 	logrus.Infof("Waiting up to %v for the openshift-console route to be created...", consoleRouteTimeout)
+	progressReporter.PhaseStarted(progress.PhaseConsole, consoleRouteTimeout)
+	defer func() {
+		if err == nil {
+			progressReporter.Report(progress.Event{Type: progress.ConsoleAvailable, ConsoleURL: url})
+		}
+		progressReporter.PhaseFinished(progress.PhaseConsole, err)
+	}()
 	consoleRouteContext, cancel := context.WithTimeout(ctx, consoleRouteTimeout)
 	defer cancel()
 	// Poll quickly but only log when the response
@@ -379,6 +578,7 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 	// no route in a row (to show we're still alive).
 	logDownsample := 15
 	silenceRemaining := logDownsample
+	lastStatus := ""
 	wait.Until(func() {
 		consoleRoutes, err := rc.RouteV1().Routes(consoleNamespace).List(metav1.ListOptions{})
 		if err == nil && len(consoleRoutes.Items) > 0 {
@@ -391,12 +591,14 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 			logrus.Debug("OpenShift console route is created")
 			cancel()
 		} else if err != nil {
+			lastStatus = err.Error()
 			silenceRemaining--
 			if silenceRemaining == 0 {
 				logrus.Debugf("Still waiting for the console route: %v", err)
 				silenceRemaining = logDownsample
 			}
 		} else if len(consoleRoutes.Items) == 0 {
+			lastStatus = fmt.Sprintf("no routes in the %s namespace", consoleNamespace)
 			silenceRemaining--
 			if silenceRemaining == 0 {
 				logrus.Debug("Still waiting for the console route...")
@@ -406,6 +608,9 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 	}, 2*time.Second, consoleRouteContext.Done())
 	err = consoleRouteContext.Err()
 	if err != nil && err != context.Canceled {
+		if err == context.DeadlineExceeded {
+			err = consoleTimeout.expired(lastStatus)
+		}
 		return url, errors.Wrap(err, "waiting for openshift-console URL")
 	}
 	if url == "" {
@@ -426,10 +631,18 @@ func logComplete(directory, consoleURL string) error {
 	if err != nil {
 		return err
 	}
+	// The password is only shown on the console, not in the log file.
+	secrets.Add(string(pw))
 	logrus.Info("Install complete!")
 	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
 	logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
 	logrus.Infof("Login to the console with user: kubeadmin, password: %s", pw)
+	progressReporter.Report(progress.Event{
+		Type:                  progress.InstallComplete,
+		ConsoleURL:            consoleURL,
+		Kubeconfig:            kubeconfig,
+		KubeadminPasswordFile: pwFile,
+	})
 	return nil
 }
 
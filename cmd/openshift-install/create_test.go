@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	texec "github.com/openshift/installer/pkg/terraform/exec"
+)
+
+func TestPrintPlan(t *testing.T) {
+	cases := []struct {
+		name     string
+		changes  []texec.ResourceChange
+		expected string
+	}{
+		{
+			name:     "no changes",
+			expected: "Plan: 0 to create, 0 to update, 0 to replace, 0 to delete.\n",
+		},
+		{
+			name: "root module",
+			changes: []texec.ResourceChange{
+				{Address: "aws_instance.bootstrap", Action: texec.Create},
+				{Address: "aws_vpc.new_vpc", Action: texec.Update},
+			},
+			expected: `root:
+  + aws_instance.bootstrap
+  ~ aws_vpc.new_vpc
+
+Plan: 1 to create, 1 to update, 0 to replace, 0 to delete.
+`,
+		},
+		{
+			name: "modules",
+			changes: []texec.ResourceChange{
+				{Address: "aws_route53_zone.int", Action: texec.Delete},
+				{Module: "masters", Address: "aws_instance.master.0", Action: texec.Replace},
+				{Module: "masters", Address: "aws_instance.master.1", Action: texec.Create},
+				{Module: "vpc", Address: "aws_vpc.new_vpc", Action: texec.Create},
+			},
+			expected: `root:
+  - aws_route53_zone.int
+
+module.masters:
+  -/+ aws_instance.master.0
+  + aws_instance.master.1
+
+module.vpc:
+  + aws_vpc.new_vpc
+
+Plan: 2 to create, 0 to update, 1 to replace, 1 to delete.
+`,
+		},
+		{
+			name: "no root module",
+			changes: []texec.ResourceChange{
+				{Module: "vpc", Address: "aws_vpc.new_vpc", Action: texec.Create},
+			},
+			expected: `module.vpc:
+  + aws_vpc.new_vpc
+
+Plan: 1 to create, 0 to update, 0 to replace, 0 to delete.
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			printPlan(buf, tc.changes)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
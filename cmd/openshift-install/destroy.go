@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/destroy"
+	_ "github.com/openshift/installer/pkg/destroy/azure"
 	"github.com/openshift/installer/pkg/destroy/bootstrap"
 	_ "github.com/openshift/installer/pkg/destroy/libvirt"
 	_ "github.com/openshift/installer/pkg/destroy/openstack"
+	"github.com/openshift/installer/pkg/destroy/stuck"
+	_ "github.com/openshift/installer/pkg/destroy/vsphere"
 )
 
 func newDestroyCmd() *cobra.Command {
@@ -23,36 +29,104 @@ func newDestroyCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newDestroyBootstrapCmd())
 	cmd.AddCommand(newDestroyClusterCmd())
+	cmd.AddCommand(newDestroyLeakedCmd())
 	return cmd
 }
 
+var (
+	destroyClusterOpts struct {
+		dryRun   bool
+		identity destroy.Identity
+	}
+)
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
 		Args:  cobra.ExactArgs(0),
-		Run: func(_ *cobra.Command, _ []string) {
+		Run: func(cmd *cobra.Command, _ []string) {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			err := runDestroyCmd(rootOpts.dir)
+			if err := loadTimeoutsFromEnv(cmd.Flags(), destroyTimeout); err != nil {
+				logrus.Fatal(err)
+			}
+
+			var err error
+			if destroyClusterOpts.dryRun {
+				err = runDestroyDryRunCmd(rootOpts.dir)
+			} else {
+				cleanupAudit := setupAuditHook(rootOpts.dir)
+				defer cleanupAudit()
+				err = runDestroyCmd(rootOpts.dir)
+			}
 			if err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&destroyClusterOpts.dryRun, "dry-run", false, "print the resources that would be deleted, grouped by type, without deleting them")
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.Platform, "platform", "", "platform of the cluster to destroy by tag instead of with metadata.json (e.g. \"aws | openstack | libvirt\")")
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.InfraID, "infra-id", "", "infrastructure ID of the cluster to destroy by tag, prefixing the names of its resources")
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.ClusterID, "cluster-id", "", "UUID of the cluster to destroy by tag; on AWS, the resources tagged with it are destroyed as well")
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.Region, "region", "", "region of the cluster to destroy by tag on AWS and OpenStack")
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.Cloud, "cloud", "", "name of the clouds.yaml entry of the cluster to destroy by tag on OpenStack")
+	addTimeoutFlags(cmd.Flags(), destroyTimeout)
+	cmd.Flags().StringVar(&destroyClusterOpts.identity.LibvirtURI, "libvirt-uri", "", "URI of the libvirt daemon of the cluster to destroy by tag (default \"qemu+tcp://192.168.122.1/system\")")
+	return cmd
+}
+
+// newDestroyer returns the destroyer of the cluster identified by the
+// flags, if --platform is set, or by metadata.json in the asset directory.
+func newDestroyer(directory string) (destroy.Destroyer, error) {
+	if destroyClusterOpts.identity.Platform == "" {
+		return destroy.New(logrus.StandardLogger(), directory)
+	}
+	metadata, err := destroyClusterOpts.identity.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	return destroy.NewFromMetadata(logrus.StandardLogger(), metadata)
+}
+
+// runDestroyDryRunCmd prints the resources runDestroyCmd would delete.
+// It leaves the assets directory alone.
+func runDestroyDryRunCmd(directory string) error {
+	destroyer, err := newDestroyer(directory)
+	if err != nil {
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+	}
+	lister, ok := destroyer.(destroy.Lister)
+	if !ok {
+		return errors.New("the destroyer for this platform does not support --dry-run")
+	}
+	resources, err := lister.List()
+	if err != nil {
+		return errors.Wrap(err, "Failed to list cluster resources")
+	}
+	if resources.Len() == 0 {
+		logrus.Info("No resources would be deleted")
+		return nil
+	}
+	return resources.Write(os.Stdout)
 }
 
 func runDestroyCmd(directory string) error {
-	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	destroyer, err := newDestroyer(directory)
 	if err != nil {
 		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
-	if err := destroyer.Run(); err != nil {
+	if err := runDestroyer(destroyer); err != nil {
 		return errors.Wrap(err, "Failed to destroy cluster")
 	}
 
-	store, err := assetstore.NewStore(directory)
+	// a cluster destroyed by tag was not installed from this directory
+	if destroyClusterOpts.identity.Platform != "" {
+		return nil
+	}
+
+	store, err := newAssetStore(directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create asset store")
 	}
@@ -70,6 +144,35 @@ func runDestroyCmd(directory string) error {
 	return nil
 }
 
+// runDestroyer runs the destroyer until it completes or destroyTimeout
+// expires.  In the latter case, it logs the resources which failed to be
+// deleted, with their last error.
+func runDestroyer(destroyer destroy.Destroyer) error {
+	contextDestroyer, ok := destroyer.(destroy.ContextDestroyer)
+	if !ok {
+		logrus.Debugf("The destroyer for this platform does not support --%s", destroyTimeout.flag)
+		return destroyer.Run()
+	}
+
+	logrus.Infof("Waiting up to %v for the cluster resources to be deleted...", destroyTimeout.duration)
+	ctx, cancel := context.WithTimeout(context.Background(), destroyTimeout.duration)
+	defer cancel()
+	err := contextDestroyer.RunWithContext(ctx)
+	if stuckErr, ok := err.(*stuck.Error); ok {
+		for _, resource := range stuckErr.Resources {
+			logrus.Errorf("Failed to delete %s %s after %d attempts: %v", resource.Type, resource.ID, resource.Attempts, resource.Err)
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			lastStatus := ""
+			if len(stuckErr.Resources) > 0 {
+				lastStatus = fmt.Sprintf("%d resources failed to be deleted", len(stuckErr.Resources))
+			}
+			return destroyTimeout.expired(lastStatus)
+		}
+	}
+	return err
+}
+
 func newDestroyBootstrapCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "bootstrap",
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/explain"
+)
+
+func newExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain RESOURCE",
+		Short: "List the fields for supported InstallConfig versions",
+		Long: `Describe the fields of the install-config.
+
+The resource is given as a dot-separated path, starting with the kind
+of the resource followed by the JSON names of its fields, for example:
+
+  openshift-install explain installconfig
+  openshift-install explain installconfig.platform.aws.defaultMachinePlatform.rootVolume
+
+The supported resources are 'installconfig' and 'machinepool'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return explain.Explain(os.Stdout, args[0])
+		},
+	}
+}
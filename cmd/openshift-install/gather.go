@@ -0,0 +1,155 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/tls"
+	gatherbootstrap "github.com/openshift/installer/pkg/gather/bootstrap"
+)
+
+func newGatherCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gather",
+		Short: "Gather debugging data for a given installation failure",
+		Long: `Gather debugging data for a given installation failure.
+
+When installation for OpenShift cluster fails, gathering all the data useful for debugging can
+become a difficult task. This command helps users to collect the most relevant information that can be used
+to debug the installation failures`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newGatherBootstrapCmd())
+	cmd.AddCommand(newGatherStateCmd())
+	return cmd
+}
+
+func newGatherBootstrapCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Gather debugging data for a failing-to-bootstrap control plane",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			tarball, err := gatherBootstrap(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			logrus.Infof("Bootstrap gather logs captured here %q", tarball)
+		},
+	}
+}
+
+func newGatherStateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "state",
+		Short: "Write copies of the state and log files with the secrets redacted",
+		Long: `Write copies of the state and log files with the secrets redacted.
+
+The state and log files of the installer hold secrets, such as private keys
+and the kubeadmin password. This command writes copies of them, with the
+secrets redacted, that can be attached to bug reports.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			err := gatherState(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+}
+
+// gatherState writes copies of the state and log files in the given
+// directory with the secrets redacted.
+func gatherState(directory string) error {
+	options, err := storeOptions()
+	if err != nil {
+		return err
+	}
+
+	var roots []asset.Asset
+	for _, t := range targets {
+		for _, a := range t.assets {
+			roots = append(roots, a)
+		}
+	}
+	state, err := assetstore.RedactedState(directory, roots, options...)
+	if err != nil {
+		return errors.Wrap(err, "failed to redact the state file")
+	}
+	statePath := filepath.Join(directory, "openshift_install_state-redacted.json")
+	if err := ioutil.WriteFile(statePath, state, 0644); err != nil {
+		return err
+	}
+	logrus.Infof("Redacted state file written to %q", statePath)
+
+	// The secrets were collected from the state file by RedactedState.
+	log, err := ioutil.ReadFile(filepath.Join(directory, ".openshift_install.log"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	logPath := filepath.Join(directory, "openshift_install-redacted.log")
+	if err := ioutil.WriteFile(logPath, []byte(secrets.Redact(string(log))), 0644); err != nil {
+		return err
+	}
+	logrus.Infof("Redacted log file written to %q", logPath)
+	return nil
+}
+
+// gatherBootstrapNode collects the diagnostics of the bootstrap node.  It
+// is replaced in tests.
+var gatherBootstrapNode = gatherbootstrap.Gather
+
+// gatherBootstrap pulls the diagnostics of the bootstrap node into a
+// tarball in the given directory, and returns its path. The assets are
+// only loaded, not fetched, so that the assets in the directory, like the
+// admin kubeconfig, are not consumed.
+func gatherBootstrap(directory string) (string, error) {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create asset store")
+	}
+
+	journalCertKey := &tls.JournalCertKey{}
+	rootCA := &tls.RootCA{}
+	bootstrapIgnition := &bootstrap.Bootstrap{}
+	for _, a := range []asset.Asset{journalCertKey, rootCA, bootstrapIgnition} {
+		found, err := assetStore.Load(a)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to load %s", a.Name())
+		}
+		if !found {
+			return "", errors.Errorf("%s has not been generated", a.Name())
+		}
+	}
+
+	logrus.Info("Gathering diagnostics from the bootstrap node...")
+	return gatherBootstrapNode(directory, bootstrapIgnition.Config, journalCertKey, rootCA)
+}
+
+// gatherBootstrapOnFailure gathers the diagnostics of the bootstrap node
+// after the bootstrap failed, logging instead of returning failures so the
+// original error is still reported.
+func gatherBootstrapOnFailure(directory string) {
+	tarball, err := gatherBootstrap(directory)
+	if err != nil {
+		logrus.Warnf("Failed to gather diagnostics from the bootstrap node: %v", err)
+		return
+	}
+	logrus.Infof("Bootstrap gather logs captured here %q", tarball)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+// readDir returns the contents of the files in the given directory, keyed
+// by their paths relative to the directory.
+func readDir(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestGatherBootstrapKeepsAssets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-gather-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	state := map[string]interface{}{}
+	for _, a := range []asset.Asset{
+		&tls.RootCA{},
+		&tls.JournalCertKey{},
+		&bootstrap.Bootstrap{
+			Config: &igntypes.Config{Ignition: igntypes.Ignition{Version: igntypes.MaxVersion.String()}},
+			File:   &asset.File{Filename: "bootstrap.ign", Data: []byte("{}")},
+		},
+		&kubeconfig.AdminClient{},
+	} {
+		state[reflect.TypeOf(a).String()] = a
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		".openshift_install_state.json": data,
+		"auth/kubeconfig":               []byte("apiVersion: v1\nkind: Config\n"),
+		"auth/kubeadmin-password":       []byte("password"),
+		"metadata.json":                 []byte("{}"),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := readDir(t, dir)
+
+	defer func(gather func(string, *igntypes.Config, *tls.JournalCertKey, *tls.RootCA) (string, error)) {
+		gatherBootstrapNode = gather
+	}(gatherBootstrapNode)
+	gatherBootstrapNode = func(directory string, ignitionConfig *igntypes.Config, journalCertKey *tls.JournalCertKey, rootCA *tls.RootCA) (string, error) {
+		assert.Equal(t, dir, directory)
+		assert.Equal(t, igntypes.MaxVersion.String(), ignitionConfig.Ignition.Version)
+		return filepath.Join(directory, "log-bundle.tar.gz"), nil
+	}
+
+	tarball, err := gatherBootstrap(dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(dir, "log-bundle.tar.gz"), tarball)
+	}
+	assert.Equal(t, expected, readDir(t, dir))
+}
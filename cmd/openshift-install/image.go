@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/rhcos/cache"
+)
+
+var (
+	imageOpts struct {
+		uri    string
+		sha256 string
+		verify bool
+		maxAge time.Duration
+		all    bool
+	}
+)
+
+func newImageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image",
+		Short: "Manage the cache of libvirt OS images",
+		Long: `Manage the cache of libvirt OS images.
+
+The libvirt platform downloads its RHCOS image into a local cache, where it is
+verified against the checksum in the RHCOS metadata of the release.  These
+subcommands manage that cache, and let hosts without network access install
+from a cache populated with 'fetch' or 'import'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newImageFetchCmd())
+	cmd.AddCommand(newImageListCmd())
+	cmd.AddCommand(newImagePruneCmd())
+	cmd.AddCommand(newImageImportCmd())
+	return cmd
+}
+
+func newImageFetchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fetch",
+		Short: "Download an OS image into the cache",
+		Long: `Download an OS image into the cache.
+
+By default, this downloads the RHCOS QEMU image of the release.  Images given
+with --uri are verified if --sha256 is set, and otherwise cached by the ETag
+of the URI.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			uri, sum := imageOpts.uri, imageOpts.sha256
+			if uri == "" {
+				var err error
+				uri, sum, err = releaseImage()
+				if err != nil {
+					logrus.Fatal(err)
+				}
+			}
+
+			logrus.Infof("Fetching OS image: %s", uri)
+			imagePath, err := cache.Default().Fetch(uri, sum)
+			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "failed to fetch the OS image"))
+			}
+			logrus.Infof("Cached OS image: %s", imagePath)
+		},
+	}
+	cmd.Flags().StringVar(&imageOpts.uri, "uri", "", "URI of the image, instead of the RHCOS image of the release")
+	cmd.Flags().StringVar(&imageOpts.sha256, "sha256", "", "SHA-256 checksum the image given with --uri must match")
+	return cmd
+}
+
+func newImageListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the cached OS images",
+		Args:  cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runImageListCmd(); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&imageOpts.verify, "verify", false, "check the images against the checksums they were verified against when cached")
+	return cmd
+}
+
+func runImageListCmd() error {
+	_, releaseSum, err := releaseImage()
+	if err != nil {
+		return err
+	}
+
+	entries, err := cache.Default().List()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the cached OS images")
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tLAST USED\tSTATUS")
+	for _, entry := range entries {
+		status := "verified"
+		if entry.SHA256 == "" {
+			status = "unverified"
+		}
+		if entry.SHA256 == releaseSum {
+			status += ", release"
+		}
+		if imageOpts.verify {
+			if err := cache.Verify(entry); err != nil {
+				status = fmt.Sprintf("failed: %v", err)
+				failed++
+			}
+		}
+		fmt.Fprintf(w, "%s\t%d MiB\t%s\t%s\n", entry.Name(), entry.Size>>20, entry.LastUsed.Format(time.RFC3339), status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d cached OS images failed verification, remove them with 'image prune'", failed)
+	}
+	return nil
+}
+
+func newImagePruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached OS images",
+		Long: `Remove cached OS images.
+
+This removes the images not used within --max-age, except the RHCOS image of
+the release unless --all is set.  It also removes the HTTP cache used to
+revalidate the images cached by ETag, and the files left by interrupted
+downloads.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := runImagePruneCmd(); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&imageOpts.maxAge, "max-age", 0, "only remove the images not used within this duration")
+	cmd.Flags().BoolVar(&imageOpts.all, "all", false, "also remove the RHCOS image of the release")
+	return cmd
+}
+
+func runImagePruneCmd() error {
+	var keep []string
+	if !imageOpts.all {
+		_, releaseSum, err := releaseImage()
+		if err != nil {
+			return err
+		}
+		keep = append(keep, releaseSum)
+	}
+
+	removed, err := cache.Default().Prune(time.Now().Add(-imageOpts.maxAge), keep...)
+	for _, entry := range removed {
+		logrus.Infof("Removed cached OS image %s", entry.Name())
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to prune the OS image cache")
+	}
+	if len(removed) == 0 {
+		logrus.Info("No cached OS image to remove")
+	}
+	return nil
+}
+
+func newImageImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Add an OS image file to the cache",
+		Long: `Add an OS image file to the cache.
+
+The file must match the checksum of the RHCOS QEMU image of the release, or
+the one given with --sha256.  Use this to populate the cache of a host without
+network access with an image downloaded elsewhere.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			sum := imageOpts.sha256
+			if sum == "" {
+				var err error
+				_, sum, err = releaseImage()
+				if err != nil {
+					logrus.Fatal(err)
+				}
+			}
+
+			imagePath, err := cache.Default().Import(args[0], sum)
+			if err != nil {
+				logrus.Fatal(errors.Wrapf(err, "failed to import %s", args[0]))
+			}
+			logrus.Infof("Cached OS image: %s", imagePath)
+		},
+	}
+	cmd.Flags().StringVar(&imageOpts.sha256, "sha256", "", "SHA-256 checksum the image must match, instead of the one of the RHCOS image of the release")
+	return cmd
+}
+
+// releaseImage returns the URI and the SHA-256 checksum of the RHCOS QEMU
+// image of the release.
+func releaseImage() (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	uri, err := rhcos.QEMU(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	sum, err := rhcos.QEMUSHA256(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return uri, sum, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/destroy/aws"
+)
+
+var (
+	destroyLeakedOpts struct {
+		region      string
+		maxAge      time.Duration
+		parallelism int
+		dryRun      bool
+	}
+)
+
+func newDestroyLeakedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "leaked",
+		Short: "Destroy the leaked AWS clusters of a region",
+		Long: `Destroy the leaked AWS clusters of a region.
+
+The clusters are found by the kubernetes.io/cluster/<infra-id> tags of their
+resources, in the region and in us-east-1.  A cluster is leaked once the time
+in the ` + aws.ExpirationTag + ` tag of its resources passed or, without such a
+tag, once its oldest instance, NAT gateway or load balancer is older than
+--max-age.  The clusters without any of them or expiration are kept.  The
+destruction of each leaked cluster is bounded by --destroy-timeout.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := loadTimeoutsFromEnv(cmd.Flags(), destroyTimeout); err != nil {
+				logrus.Fatal(err)
+			}
+			if !destroyLeakedOpts.dryRun {
+				cleanupAudit := setupAuditHook(rootOpts.dir)
+				defer cleanupAudit()
+			}
+
+			err := runDestroyLeakedCmd()
+			if err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&destroyLeakedOpts.region, "region", "", "AWS region to search for leaked clusters")
+	cmd.Flags().DurationVar(&destroyLeakedOpts.maxAge, "max-age", 72*time.Hour, "age after which the clusters without expiration are leaked")
+	cmd.Flags().IntVar(&destroyLeakedOpts.parallelism, "parallelism", 5, "number of clusters destroyed at once")
+	cmd.Flags().BoolVar(&destroyLeakedOpts.dryRun, "dry-run", false, "only report the leaked clusters, without destroying them")
+	addTimeoutFlags(cmd.Flags(), destroyTimeout)
+	return cmd
+}
+
+func runDestroyLeakedCmd() error {
+	if destroyLeakedOpts.region == "" {
+		return errors.New("--region is required")
+	}
+
+	clusters, err := (&aws.Janitor{
+		Region:      destroyLeakedOpts.region,
+		Logger:      logrus.StandardLogger(),
+		MaxAge:      destroyLeakedOpts.maxAge,
+		Parallelism: destroyLeakedOpts.parallelism,
+		DryRun:      destroyLeakedOpts.dryRun,
+		Timeout:     destroyTimeout.duration,
+	}).Run()
+	if err != nil {
+		return errors.Wrap(err, "Failed to destroy leaked clusters")
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "INFRA ID\tRESOURCES\tRESULT\tREASON")
+	for _, cluster := range clusters {
+		result := "kept"
+		switch {
+		case !cluster.Leaked:
+		case destroyLeakedOpts.dryRun:
+			result = "would destroy"
+		case cluster.Err != nil:
+			result = fmt.Sprintf("failed: %v", cluster.Err)
+			failed++
+		default:
+			result = "destroyed"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", cluster.InfraID, len(cluster.Resources), result, cluster.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("failed to destroy %d leaked clusters", failed)
+	}
+	return nil
+}
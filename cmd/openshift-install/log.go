@@ -8,6 +8,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/destroy/audit"
 	"github.com/openshift/installer/pkg/version"
 )
 
@@ -15,6 +17,9 @@ type fileHook struct {
 	file      io.Writer
 	formatter logrus.Formatter
 	level     logrus.Level
+	// redactor, if set, redacts the secret values from the lines written
+	// to the file.
+	redactor *asset.Redactor
 }
 
 func newFileHook(file io.Writer, level logrus.Level, formatter logrus.Formatter) *fileHook {
@@ -41,6 +46,9 @@ func (h *fileHook) Fire(entry *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
+	if h.redactor != nil {
+		line = []byte(h.redactor.Redact(string(line)))
+	}
 
 	_, err = h.file.Write(line)
 	return err
@@ -60,12 +68,14 @@ func setupFileHook(baseDir string) func() {
 	for k, v := range logrus.StandardLogger().Hooks {
 		originalHooks[k] = v
 	}
-	logrus.AddHook(newFileHook(logfile, logrus.TraceLevel, &logrus.TextFormatter{
+	hook := newFileHook(logfile, logrus.TraceLevel, &logrus.TextFormatter{
 		DisableColors:          true,
 		DisableTimestamp:       false,
 		FullTimestamp:          true,
 		DisableLevelTruncation: false,
-	}))
+	})
+	hook.redactor = secrets
+	logrus.AddHook(hook)
 
 	logrus.Debugf(version.String)
 	if version.Commit != "" {
@@ -77,3 +87,32 @@ func setupFileHook(baseDir string) func() {
 		logrus.StandardLogger().ReplaceHooks(originalHooks)
 	}
 }
+
+// setupAuditHook records the resources deleted by the destroyers as JSON
+// lines in the audit log of the base directory.  The audit hook is added
+// before the other hooks, so the logs do not show the audit context.
+func setupAuditHook(baseDir string) func() {
+	auditFile, err := os.OpenFile(filepath.Join(baseDir, ".openshift_install_destroy_audit.log"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		logrus.Fatal(errors.Wrap(err, "failed to open audit log file"))
+	}
+
+	originalHooks := logrus.LevelHooks{}
+	for k, v := range logrus.StandardLogger().Hooks {
+		originalHooks[k] = v
+	}
+	hook := audit.NewHook(auditFile)
+	hooks := logrus.LevelHooks{}
+	for k, v := range originalHooks {
+		hooks[k] = v
+	}
+	for _, level := range hook.Levels() {
+		hooks[level] = append([]logrus.Hook{hook}, originalHooks[level]...)
+	}
+	logrus.StandardLogger().ReplaceHooks(hooks)
+
+	return func() {
+		auditFile.Close()
+		logrus.StandardLogger().ReplaceHooks(originalHooks)
+	}
+}
@@ -17,8 +17,12 @@ import (
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir            string
+		logLevel       string
+		progressFormat string
+		progressFile   string
+		stateKeyFile   string
+		storage        string
 	}
 )
 
@@ -47,8 +51,11 @@ func installerMain() {
 		newCreateCmd(),
 		newDestroyCmd(),
 		newWaitForCmd(),
+		newGatherCmd(),
 		newVersionCmd(),
 		newGraphCmd(),
+		newImageCmd(),
+		newExplainCmd(),
 		newCompletionCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
@@ -65,11 +72,19 @@ func newRootCmd() *cobra.Command {
 		Short:            "Creates OpenShift clusters",
 		Long:             "",
 		PersistentPreRun: runRootCmd,
-		SilenceErrors:    true,
-		SilenceUsage:     true,
+		PersistentPostRun: func(_ *cobra.Command, _ []string) {
+			closeProgressFile()
+			pushStorage()
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
 	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.progressFormat, "progress-format", "text", "progress format (e.g. \"text | json\"); json additionally reports progress events as JSON lines")
+	cmd.PersistentFlags().StringVar(&rootOpts.progressFile, "progress-file", "", "file where JSON progress events are written, if empty they are written to Stdout")
+	cmd.PersistentFlags().StringVar(&rootOpts.storage, "storage", "", "URL of the storage the assets directory is pulled from and pushed to (e.g. \"s3://bucket/prefix?region=us-east-1 | memory://name | file:///path\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.stateKeyFile, "state-key-file", "", "file holding the key used to encrypt the secrets in the state file (the passphrase can also be set with "+statePassphraseEnv+")")
 	return cmd
 }
 
@@ -96,4 +111,17 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		logrus.Fatal(errors.Wrap(err, "invalid log-level"))
 	}
+
+	cleanup, err := setupProgressReporter(rootOpts.progressFormat, rootOpts.progressFile)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	closeProgressFile = cleanup
+	// Fatal errors exit without returning to the root command, and the
+	// events leading to them are the most useful ones.
+	logrus.RegisterExitHandler(cleanup)
+
+	if err := pullStorage(rootOpts.storage, rootOpts.dir); err != nil {
+		logrus.Fatal(err)
+	}
 }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	clientwatch "k8s.io/client-go/tools/watch"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/installer/pkg/progress"
+)
+
+// progressReporter reports machine-readable progress events. It is nil,
+// discarding all events, unless --progress-format=json.
+var progressReporter *progress.Reporter
+
+// closeProgressFile syncs and closes the --progress-file, if any.
+var closeProgressFile = func() {}
+
+// setupProgressReporter sets up progressReporter for the given format,
+// writing to the given file or to Stdout. It returns a function syncing and
+// closing the file, which is safe to call more than once.
+func setupProgressReporter(format string, file string) (func(), error) {
+	switch format {
+	case "text":
+		return func() {}, nil
+	case "json":
+	default:
+		return nil, errors.Errorf("invalid progress-format %q", format)
+	}
+
+	if file == "" {
+		progressReporter = progress.NewJSONReporter(os.Stdout)
+		return func() {}, nil
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open progress file")
+	}
+	progressReporter = progress.NewJSONReporter(f)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			progressReporter = nil
+			if err := f.Sync(); err != nil {
+				logrus.Debugf("Failed to sync the progress file: %v", err)
+			}
+			f.Close()
+		})
+	}, nil
+}
+
+// watchClusterOperators reports the changes to the conditions of the
+// ClusterOperators until the context is done.
+func watchClusterOperators(ctx context.Context, cc configclient.Interface) {
+	seen := map[string][]progress.Condition{}
+	_, err := clientwatch.UntilWithSync(
+		ctx,
+		cache.NewListWatchFromClient(cc.ConfigV1().RESTClient(), "clusteroperators", "", fields.Everything()),
+		&configv1.ClusterOperator{},
+		nil,
+		func(event watch.Event) (bool, error) {
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				return false, nil
+			}
+			co, ok := event.Object.(*configv1.ClusterOperator)
+			if !ok {
+				return false, nil
+			}
+
+			conditions := make([]progress.Condition, 0, len(co.Status.Conditions))
+			for _, c := range co.Status.Conditions {
+				conditions = append(conditions, progress.Condition{
+					Type:    string(c.Type),
+					Status:  string(c.Status),
+					Reason:  c.Reason,
+					Message: c.Message,
+				})
+			}
+			if reflect.DeepEqual(seen[co.Name], conditions) {
+				return false, nil
+			}
+			seen[co.Name] = conditions
+
+			progressReporter.Report(progress.Event{
+				Type: progress.ClusterOperatorStatus,
+				ClusterOperator: &progress.ClusterOperator{
+					Name:       co.Name,
+					Conditions: conditions,
+				},
+			})
+			return false, nil
+		},
+	)
+	if err != nil && ctx.Err() == nil {
+		logrus.Debugf("Stopped watching ClusterOperators: %v", err)
+	}
+}
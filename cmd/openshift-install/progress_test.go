@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/progress"
+)
+
+func TestSetupProgressReporterFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-progress-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { progressReporter = nil }()
+
+	path := filepath.Join(dir, "progress.json")
+	cleanup, err := setupProgressReporter("json", path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	progressReporter.Report(progress.Event{Type: progress.InstallComplete})
+	cleanup()
+	cleanup()
+	assert.Nil(t, progressReporter)
+
+	info, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	data, err := ioutil.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"InstallComplete"`)
+		assert.True(t, strings.HasSuffix(string(data), "\n"))
+	}
+}
+
+func TestSetupProgressReporterInvalidFormat(t *testing.T) {
+	_, err := setupProgressReporter("yaml", "")
+	assert.EqualError(t, err, `invalid progress-format "yaml"`)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+// statePassphraseEnv is the environment variable holding the passphrase
+// used to encrypt the state file, if no key file is given.
+const statePassphraseEnv = "OPENSHIFT_INSTALL_STATE_PASSPHRASE"
+
+// secrets holds the secret values of the assets, which are redacted from
+// the log file.
+var secrets = asset.NewRedactor()
+
+// newAssetStore returns an asset store for the given directory. The assets
+// holding secrets are encrypted in its state file if a key file or a
+// passphrase is given.
+func newAssetStore(directory string) (asset.Store, error) {
+	options, err := storeOptions()
+	if err != nil {
+		return nil, err
+	}
+	return assetstore.NewStore(directory, options...)
+}
+
+func storeOptions() ([]assetstore.Option, error) {
+	options := []assetstore.Option{assetstore.WithRedactor(secrets)}
+	if rootOpts.stateKeyFile != "" {
+		key, err := ioutil.ReadFile(rootOpts.stateKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the state key file")
+		}
+		if len(key) == 0 {
+			return nil, errors.Errorf("state key file %q is empty", rootOpts.stateKeyFile)
+		}
+		return append(options, assetstore.WithEncryptionSecret(key)), nil
+	}
+	if passphrase := os.Getenv(statePassphraseEnv); passphrase != "" {
+		return append(options, assetstore.WithEncryptionSecret([]byte(passphrase))), nil
+	}
+	return options, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/storage"
+)
+
+// remoteStorage is the backend selected with --storage, which the assets
+// directory is synchronized with. It is nil if the assets directory is only
+// stored locally.
+var remoteStorage storage.Backend
+
+// pullStorage copies the files of the backend at the given URL into the
+// assets directory, and arranges for the assets directory to be pushed back
+// to the backend when the installer exits.
+func pullStorage(url string, directory string) error {
+	if url == "" {
+		return nil
+	}
+
+	backend, err := storage.New(url)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("Pulling the assets directory from %s", url)
+	if err := storage.Copy(storage.NewDir(directory), backend); err != nil {
+		return errors.Wrapf(err, "failed to pull the assets directory from %s", url)
+	}
+
+	remoteStorage = backend
+	// Fatal errors exit without returning to the root command, and are
+	// exactly when the state is needed to investigate or destroy the
+	// cluster.
+	logrus.RegisterExitHandler(pushStorage)
+	return nil
+}
+
+// pushStorage makes the backend selected with --storage hold the same files
+// as the assets directory, including the deletions of destroyed assets.
+func pushStorage() {
+	if remoteStorage == nil {
+		return
+	}
+	backend := remoteStorage
+	remoteStorage = nil
+
+	logrus.Debugf("Pushing the assets directory to %s", rootOpts.storage)
+	if err := storage.Mirror(backend, storage.NewDir(rootOpts.dir)); err != nil {
+		logrus.Errorf("Failed to push the assets directory to %s: %v", rootOpts.storage, err)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/installer/pkg/progress"
+)
+
+// phaseTimeout is the maximum duration of a phase waited on by the
+// installer. It can be set with a flag or, if the flag is not given, with
+// an environment variable.
+type phaseTimeout struct {
+	phase    progress.Phase
+	flag     string
+	env      string
+	usage    string
+	duration time.Duration
+}
+
+var (
+	apiTimeout = &phaseTimeout{
+		phase:    progress.PhaseAPI,
+		flag:     "api-timeout",
+		env:      "OPENSHIFT_INSTALL_API_TIMEOUT",
+		usage:    "maximum duration to wait for the Kubernetes API",
+		duration: 30 * time.Minute,
+	}
+	bootstrapTimeout = &phaseTimeout{
+		phase:    progress.PhaseBootstrap,
+		flag:     "bootstrap-timeout",
+		env:      "OPENSHIFT_INSTALL_BOOTSTRAP_TIMEOUT",
+		usage:    "maximum duration to wait for the bootstrap-complete event",
+		duration: 30 * time.Minute,
+	}
+	initializationTimeout = &phaseTimeout{
+		phase:    progress.PhaseInitialization,
+		flag:     "initialization-timeout",
+		env:      "OPENSHIFT_INSTALL_INITIALIZATION_TIMEOUT",
+		usage:    "maximum duration to wait for the cluster to initialize",
+		duration: 30 * time.Minute,
+	}
+	consoleTimeout = &phaseTimeout{
+		phase:    progress.PhaseConsole,
+		flag:     "console-timeout",
+		env:      "OPENSHIFT_INSTALL_CONSOLE_TIMEOUT",
+		usage:    "maximum duration to wait for the openshift-console route",
+		duration: 10 * time.Minute,
+	}
+
+	destroyTimeout = &phaseTimeout{
+		phase:    progress.PhaseDestroy,
+		flag:     "destroy-timeout",
+		env:      "OPENSHIFT_INSTALL_DESTROY_TIMEOUT",
+		usage:    "maximum duration to wait for the cluster resources to be deleted",
+		duration: 2 * time.Hour,
+	}
+
+	bootstrapTimeouts    = []*phaseTimeout{apiTimeout, bootstrapTimeout}
+	clusterReadyTimeouts = []*phaseTimeout{initializationTimeout, consoleTimeout}
+	clusterTimeouts      = append(bootstrapTimeouts, clusterReadyTimeouts...)
+)
+
+// addTimeoutFlags adds the flags of the given timeouts to the flag set.
+func addTimeoutFlags(flags *pflag.FlagSet, timeouts ...*phaseTimeout) {
+	for _, t := range timeouts {
+		flags.DurationVar(&t.duration, t.flag, t.duration, fmt.Sprintf("%s (also settable with %s)", t.usage, t.env))
+	}
+}
+
+// loadTimeoutsFromEnv sets the timeouts whose flags were not given from
+// their environment variables, and checks that all of them are positive.
+func loadTimeoutsFromEnv(flags *pflag.FlagSet, timeouts ...*phaseTimeout) error {
+	for _, t := range timeouts {
+		if flags.Changed(t.flag) {
+			if t.duration <= 0 {
+				return errors.Errorf("invalid --%s: must be positive", t.flag)
+			}
+			continue
+		}
+		value, ok := os.LookupEnv(t.env)
+		if !ok || value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", t.env)
+		}
+		if duration <= 0 {
+			return errors.Errorf("invalid %s: must be positive", t.env)
+		}
+		t.duration = duration
+	}
+	return nil
+}
+
+// expired returns the error reported when the phase did not complete
+// before its timeout, including the last status observed while waiting.
+func (t *phaseTimeout) expired(lastStatus string) error {
+	msg := fmt.Sprintf("%s phase did not complete within %v (see --%s or %s)", t.phase, t.duration, t.flag, t.env)
+	lastStatus = strings.TrimSpace(lastStatus)
+	if lastStatus == "" {
+		return errors.New(msg)
+	}
+	return errors.Errorf("%s: last observed status: %s", msg, lastStatus)
+}
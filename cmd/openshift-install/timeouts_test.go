@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/progress"
+)
+
+const testTimeoutEnv = "OPENSHIFT_INSTALL_TEST_TIMEOUT"
+
+func testTimeout() *phaseTimeout {
+	return &phaseTimeout{
+		phase:    progress.PhaseBootstrap,
+		flag:     "test-timeout",
+		env:      testTimeoutEnv,
+		usage:    "maximum duration of the test",
+		duration: 30 * time.Minute,
+	}
+}
+
+func TestPhaseTimeout(t *testing.T) {
+	cases := []struct {
+		name        string
+		args        []string
+		env         *string
+		expected    time.Duration
+		expectedErr string
+	}{
+		{
+			name:     "default",
+			expected: 30 * time.Minute,
+		},
+		{
+			name:     "flag",
+			args:     []string{"--test-timeout=1h"},
+			expected: time.Hour,
+		},
+		{
+			name:     "env",
+			env:      stringPointer("45m"),
+			expected: 45 * time.Minute,
+		},
+		{
+			name:     "empty env",
+			env:      stringPointer(""),
+			expected: 30 * time.Minute,
+		},
+		{
+			name:     "flag overrides env",
+			args:     []string{"--test-timeout=1h"},
+			env:      stringPointer("45m"),
+			expected: time.Hour,
+		},
+		{
+			name:     "flag overrides invalid env",
+			args:     []string{"--test-timeout=1h"},
+			env:      stringPointer("forever"),
+			expected: time.Hour,
+		},
+		{
+			name:        "invalid flag",
+			args:        []string{"--test-timeout=forever"},
+			expectedErr: `invalid argument "forever" for "--test-timeout" flag: time: invalid duration "forever"`,
+		},
+		{
+			name:        "zero flag",
+			args:        []string{"--test-timeout=0s"},
+			expectedErr: "invalid --test-timeout: must be positive",
+		},
+		{
+			name:        "negative flag",
+			args:        []string{"--test-timeout=-5m"},
+			env:         stringPointer("45m"),
+			expectedErr: "invalid --test-timeout: must be positive",
+		},
+		{
+			name:        "invalid env",
+			env:         stringPointer("forever"),
+			expectedErr: `invalid OPENSHIFT_INSTALL_TEST_TIMEOUT: time: invalid duration "forever"`,
+		},
+		{
+			name:        "zero env",
+			env:         stringPointer("0s"),
+			expectedErr: "invalid OPENSHIFT_INSTALL_TEST_TIMEOUT: must be positive",
+		},
+		{
+			name:        "negative env",
+			env:         stringPointer("-5m"),
+			expectedErr: "invalid OPENSHIFT_INSTALL_TEST_TIMEOUT: must be positive",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env == nil {
+				os.Unsetenv(testTimeoutEnv)
+			} else {
+				os.Setenv(testTimeoutEnv, *tc.env)
+			}
+			defer os.Unsetenv(testTimeoutEnv)
+
+			timeout := testTimeout()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			addTimeoutFlags(flags, timeout)
+			err := flags.Parse(tc.args)
+			if err == nil {
+				err = loadTimeoutsFromEnv(flags, timeout)
+			}
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, timeout.duration)
+			}
+		})
+	}
+}
+
+func TestPhaseTimeoutExpired(t *testing.T) {
+	cases := []struct {
+		name       string
+		lastStatus string
+		expected   string
+	}{
+		{
+			name:     "no status",
+			expected: "bootstrap phase did not complete within 30m0s (see --test-timeout or OPENSHIFT_INSTALL_TEST_TIMEOUT)",
+		},
+		{
+			name:       "blank status",
+			lastStatus: " \n",
+			expected:   "bootstrap phase did not complete within 30m0s (see --test-timeout or OPENSHIFT_INSTALL_TEST_TIMEOUT)",
+		},
+		{
+			name:       "status",
+			lastStatus: "Working towards 4.1.0: 45% complete\n",
+			expected:   "bootstrap phase did not complete within 30m0s (see --test-timeout or OPENSHIFT_INSTALL_TEST_TIMEOUT): last observed status: Working towards 4.1.0: 45% complete",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, testTimeout().expired(tc.lastStatus), tc.expected)
+		})
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
@@ -29,16 +29,20 @@ them directly.`,
 }
 
 func newWaitForBootstrapCompleteCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "bootstrap-complete",
 		Short: "Wait until cluster bootstrapping has completed",
 		Args:  cobra.ExactArgs(0),
-		Run: func(_ *cobra.Command, _ []string) {
+		Run: func(cmd *cobra.Command, _ []string) {
 			ctx := context.Background()
 
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
+			if err := loadTimeoutsFromEnv(cmd.Flags(), bootstrapTimeouts...); err != nil {
+				logrus.Fatal(err)
+			}
+
 			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
@@ -46,16 +50,19 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 
 			err = waitForBootstrapComplete(ctx, config, rootOpts.dir)
 			if err != nil {
+				gatherBootstrapOnFailure(rootOpts.dir)
 				logrus.Fatal(err)
 			}
 
 			logrus.Info("It is now safe to remove the bootstrap resources")
 		},
 	}
+	addTimeoutFlags(cmd.Flags(), bootstrapTimeouts...)
+	return cmd
 }
 
 func newWaitForClusterReadyCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster-ready",
 		Short: "Wait until the cluster is ready",
 		Args:  cobra.ExactArgs(0),
@@ -65,6 +72,10 @@ func newWaitForClusterReadyCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
+			if err := loadTimeoutsFromEnv(cmd.Flags(), clusterReadyTimeouts...); err != nil {
+				logrus.Fatal(err)
+			}
+
 			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
@@ -76,4 +87,6 @@ func newWaitForClusterReadyCmd() *cobra.Command {
 			}
 		},
 	}
+	addTimeoutFlags(cmd.Flags(), clusterReadyTimeouts...)
+	return cmd
 }
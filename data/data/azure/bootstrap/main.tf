@@ -0,0 +1,162 @@
+locals {
+  bootstrap_nic_ip_configuration_name = "bootstrap-nic-ip"
+}
+
+# the bootstrap Ignition config is too large for the custom data of a
+# virtual machine, so the machine fetches it from the storage account
+resource "azurerm_storage_container" "ignition" {
+  name                  = "ignition"
+  resource_group_name   = "${var.resource_group_name}"
+  storage_account_name  = "${var.storage_account}"
+  container_access_type = "private"
+}
+
+resource "azurerm_storage_blob" "ignition" {
+  name                   = "bootstrap.ign"
+  resource_group_name    = "${var.resource_group_name}"
+  storage_account_name   = "${var.storage_account}"
+  storage_container_name = "${azurerm_storage_container.ignition.name}"
+  type                   = "block"
+  source_content         = "${var.ignition}"
+}
+
+data "azurerm_storage_account_sas" "ignition" {
+  connection_string = "${var.storage_connection_string}"
+  https_only        = true
+
+  resource_types {
+    service   = false
+    container = false
+    object    = true
+  }
+
+  services {
+    blob  = true
+    queue = false
+    table = false
+    file  = false
+  }
+
+  start  = "${timestamp()}"
+  expiry = "${timeadd(timestamp(), "24h")}"
+
+  permissions {
+    read    = true
+    list    = false
+    create  = false
+    add     = false
+    delete  = false
+    process = false
+    write   = false
+    update  = false
+  }
+}
+
+data "ignition_config" "redirect" {
+  replace {
+    source = "${azurerm_storage_blob.ignition.url}${data.azurerm_storage_account_sas.ignition.sas}"
+  }
+}
+
+resource "azurerm_public_ip" "bootstrap_public_ip" {
+  name                = "${var.cluster_id}-bootstrap-pip"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+  sku                 = "Standard"
+  allocation_method   = "Static"
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_network_interface" "bootstrap" {
+  name                = "${var.cluster_id}-bootstrap-nic"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+
+  ip_configuration {
+    name                          = "${local.bootstrap_nic_ip_configuration_name}"
+    subnet_id                     = "${var.subnet_id}"
+    private_ip_address_allocation = "Dynamic"
+    public_ip_address_id          = "${azurerm_public_ip.bootstrap_public_ip.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_network_interface_backend_address_pool_association" "public_lb_bootstrap" {
+  network_interface_id    = "${azurerm_network_interface.bootstrap.id}"
+  backend_address_pool_id = "${var.elb_backend_pool_id}"
+  ip_configuration_name   = "${local.bootstrap_nic_ip_configuration_name}"
+}
+
+resource "azurerm_network_interface_backend_address_pool_association" "internal_lb_bootstrap" {
+  network_interface_id    = "${azurerm_network_interface.bootstrap.id}"
+  backend_address_pool_id = "${var.ilb_backend_pool_id}"
+  ip_configuration_name   = "${local.bootstrap_nic_ip_configuration_name}"
+}
+
+resource "azurerm_virtual_machine" "bootstrap" {
+  name                  = "${var.cluster_id}-bootstrap"
+  resource_group_name   = "${var.resource_group_name}"
+  location              = "${var.region}"
+  network_interface_ids = ["${azurerm_network_interface.bootstrap.id}"]
+  vm_size               = "${var.vm_size}"
+
+  delete_os_disk_on_termination    = true
+  delete_data_disks_on_termination = true
+
+  identity {
+    type         = "UserAssigned"
+    identity_ids = ["${var.identity}"]
+  }
+
+  storage_os_disk {
+    name              = "${var.cluster_id}-bootstrap_OSDisk"
+    caching           = "ReadWrite"
+    create_option     = "FromImage"
+    managed_disk_type = "Premium_LRS"
+    disk_size_gb      = 100
+  }
+
+  storage_image_reference {
+    id = "${var.vm_image}"
+  }
+
+  # RHCOS ignores the administrator, which Azure requires
+  os_profile {
+    computer_name  = "${var.cluster_id}-bootstrap"
+    admin_username = "core"
+    admin_password = "NotActuallyApplied!"
+    custom_data    = "${data.ignition_config.redirect.rendered}"
+  }
+
+  os_profile_linux_config {
+    disable_password_authentication = false
+  }
+
+  boot_diagnostics {
+    enabled     = true
+    storage_uri = "${var.storage_primary_endpoint}"
+  }
+
+  lifecycle {
+    # The SAS token of the redirect changes on every run.
+    ignore_changes = ["os_profile"]
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_network_security_rule" "bootstrap_ssh_in" {
+  name                        = "bootstrap_ssh_in"
+  priority                    = 103
+  direction                   = "Inbound"
+  access                      = "Allow"
+  protocol                    = "Tcp"
+  source_port_range           = "*"
+  destination_port_range      = "22"
+  source_address_prefix       = "*"
+  destination_address_prefix  = "*"
+  resource_group_name         = "${var.resource_group_name}"
+  network_security_group_name = "${var.master_nsg_name}"
+}
@@ -0,0 +1,75 @@
+variable "cluster_id" {
+  type        = "string"
+  description = "The identifier for the cluster."
+}
+
+variable "elb_backend_pool_id" {
+  type        = "string"
+  description = "The external load balancer backend pool ID, used to attach the bootstrap NIC."
+}
+
+variable "identity" {
+  type        = "string"
+  description = "The user assigned identity ID for the bootstrap node."
+}
+
+variable "ignition" {
+  type        = "string"
+  description = "The content of the bootstrap ignition file."
+}
+
+variable "ilb_backend_pool_id" {
+  type        = "string"
+  description = "The internal load balancer backend pool ID, used to attach the bootstrap NIC."
+}
+
+variable "master_nsg_name" {
+  type        = "string"
+  description = "The name of the network security group of the masters, which allows SSH to the bootstrap node."
+}
+
+variable "region" {
+  type        = "string"
+  description = "The region for the deployment."
+}
+
+variable "resource_group_name" {
+  type        = "string"
+  description = "The resource group for the deployment."
+}
+
+variable "storage_account" {
+  type        = "string"
+  description = "The name of the storage account holding the bootstrap ignition file."
+}
+
+variable "storage_connection_string" {
+  type        = "string"
+  description = "The connection string of the storage account, used to sign the URL of the bootstrap ignition file."
+}
+
+variable "storage_primary_endpoint" {
+  type        = "string"
+  description = "The primary blob endpoint of the storage account, used for boot diagnostics."
+}
+
+variable "subnet_id" {
+  type        = "string"
+  description = "The subnet ID for the bootstrap node."
+}
+
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "Azure tags to be applied to created resources."
+}
+
+variable "vm_image" {
+  type        = "string"
+  description = "The resource ID of the image for the bootstrap node."
+}
+
+variable "vm_size" {
+  type        = "string"
+  description = "The instance type of the bootstrap node."
+}
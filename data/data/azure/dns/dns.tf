@@ -0,0 +1,81 @@
+locals {
+  # the name of the cluster domain in the zone of the base domain
+  api_external_name = "api.${replace(var.cluster_domain, ".${var.base_domain}", "")}"
+
+  # Terraform 0.11 cannot build the record blocks from the count of etcd
+  # members, so they are sliced from the records of the largest supported
+  # etcd cluster
+  etcd_srv_records = [
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-0.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-1.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-2.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-3.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-4.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-5.${var.cluster_domain}")}",
+    "${map("priority", 0, "weight", 10, "port", 2380, "target", "etcd-6.${var.cluster_domain}")}",
+  ]
+}
+
+resource "azurerm_dns_a_record" "api_external" {
+  name                = "${local.api_external_name}"
+  zone_name           = "${var.base_domain}"
+  resource_group_name = "${var.base_domain_resource_group_name}"
+  ttl                 = 300
+  records             = ["${var.external_lb_ip_address}"]
+
+  tags = "${var.tags}"
+}
+
+# the private zone resolves the records of the cluster domain within the
+# virtual network, including the API, which resolves to the internal load
+# balancer there
+resource "azurerm_dns_zone" "private" {
+  name                           = "${var.cluster_domain}"
+  resource_group_name            = "${var.resource_group_name}"
+  zone_type                      = "Private"
+  resolution_virtual_network_ids = ["${var.virtual_network_id}"]
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_dns_a_record" "api_internal" {
+  name                = "api-int"
+  zone_name           = "${azurerm_dns_zone.private.name}"
+  resource_group_name = "${var.resource_group_name}"
+  ttl                 = 300
+  records             = ["${var.internal_lb_ip_address}"]
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_dns_a_record" "api_external_internal" {
+  name                = "api"
+  zone_name           = "${azurerm_dns_zone.private.name}"
+  resource_group_name = "${var.resource_group_name}"
+  ttl                 = 300
+  records             = ["${var.internal_lb_ip_address}"]
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_dns_a_record" "etcd_a_nodes" {
+  count = "${var.etcd_count}"
+
+  name                = "etcd-${count.index}"
+  zone_name           = "${azurerm_dns_zone.private.name}"
+  resource_group_name = "${var.resource_group_name}"
+  ttl                 = 60
+  records             = ["${element(var.etcd_ip_addresses, count.index)}"]
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_dns_srv_record" "etcd_cluster" {
+  name                = "_etcd-server-ssl._tcp"
+  zone_name           = "${azurerm_dns_zone.private.name}"
+  resource_group_name = "${var.resource_group_name}"
+  ttl                 = 60
+  record              = ["${slice(local.etcd_srv_records, 0, var.etcd_count)}"]
+
+  tags = "${var.tags}"
+}
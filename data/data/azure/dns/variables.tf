@@ -0,0 +1,50 @@
+variable "base_domain" {
+  type        = "string"
+  description = "The base domain of the cluster, whose DNS zone holds the public API record."
+}
+
+variable "base_domain_resource_group_name" {
+  type        = "string"
+  description = "The resource group that contains the DNS zone of the base domain."
+}
+
+variable "cluster_domain" {
+  type        = "string"
+  description = "The domain of the cluster, which is the name of the private DNS zone."
+}
+
+variable "etcd_count" {
+  type        = "string"
+  description = "The number of etcd members, at most seven."
+}
+
+variable "etcd_ip_addresses" {
+  type        = "list"
+  description = "The IP addresses of the etcd members."
+}
+
+variable "external_lb_ip_address" {
+  type        = "string"
+  description = "The public IP address of the external load balancer."
+}
+
+variable "internal_lb_ip_address" {
+  type        = "string"
+  description = "The private IP address of the internal load balancer."
+}
+
+variable "resource_group_name" {
+  type        = "string"
+  description = "The resource group for the private DNS zone."
+}
+
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "Azure tags to be applied to created resources."
+}
+
+variable "virtual_network_id" {
+  type        = "string"
+  description = "The ID of the virtual network that resolves the private DNS zone."
+}
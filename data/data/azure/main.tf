@@ -0,0 +1,150 @@
+locals {
+  tags = "${merge(map(
+    "kubernetes.io_cluster.${var.cluster_id}", "owned"
+  ), var.azure_extra_tags)}"
+
+  # storage account names are globally unique, 3 to 24 lowercase letters
+  # and digits
+  storage_account_prefix = "${lower(replace(var.cluster_id, "-", ""))}"
+  storage_account_name   = "${substr(local.storage_account_prefix, 0, min(24, length(local.storage_account_prefix)))}"
+}
+
+provider "azurerm" {}
+
+module "bootstrap" {
+  source = "./bootstrap"
+
+  resource_group_name       = "${azurerm_resource_group.main.name}"
+  region                    = "${var.azure_region}"
+  vm_size                   = "${var.azure_bootstrap_vm_type}"
+  vm_image                  = "${azurerm_image.cluster.id}"
+  identity                  = "${azurerm_user_assigned_identity.main.id}"
+  cluster_id                = "${var.cluster_id}"
+  ignition                  = "${var.ignition_bootstrap}"
+  subnet_id                 = "${module.vnet.master_subnet_id}"
+  elb_backend_pool_id       = "${module.vnet.public_lb_backend_pool_id}"
+  ilb_backend_pool_id       = "${module.vnet.internal_lb_backend_pool_id}"
+  storage_account           = "${azurerm_storage_account.cluster.name}"
+  storage_primary_endpoint  = "${azurerm_storage_account.cluster.primary_blob_endpoint}"
+  storage_connection_string = "${azurerm_storage_account.cluster.primary_connection_string}"
+  master_nsg_name           = "${module.vnet.master_nsg_name}"
+
+  tags = "${local.tags}"
+}
+
+module "vnet" {
+  source = "./vnet"
+
+  resource_group_name = "${azurerm_resource_group.main.name}"
+  region              = "${var.azure_region}"
+  cidr_block          = "${var.machine_cidr}"
+  cluster_id          = "${var.cluster_id}"
+
+  tags = "${local.tags}"
+}
+
+module "master" {
+  source = "./master"
+
+  resource_group_name      = "${azurerm_resource_group.main.name}"
+  cluster_id               = "${var.cluster_id}"
+  region                   = "${var.azure_region}"
+  availability_zones       = "${var.azure_master_availability_zones}"
+  vm_size                  = "${var.azure_master_vm_type}"
+  vm_image                 = "${azurerm_image.cluster.id}"
+  identity                 = "${azurerm_user_assigned_identity.main.id}"
+  ignition                 = "${var.ignition_master}"
+  subnet_id                = "${module.vnet.master_subnet_id}"
+  elb_backend_pool_id      = "${module.vnet.public_lb_backend_pool_id}"
+  ilb_backend_pool_id      = "${module.vnet.internal_lb_backend_pool_id}"
+  instance_count           = "${var.master_count}"
+  os_volume_size           = "${var.azure_master_root_volume_size}"
+  storage_primary_endpoint = "${azurerm_storage_account.cluster.primary_blob_endpoint}"
+
+  tags = "${local.tags}"
+}
+
+module "dns" {
+  source = "./dns"
+
+  cluster_domain                  = "${var.cluster_domain}"
+  base_domain                     = "${var.base_domain}"
+  base_domain_resource_group_name = "${var.azure_base_domain_resource_group_name}"
+  resource_group_name             = "${azurerm_resource_group.main.name}"
+  virtual_network_id              = "${module.vnet.virtual_network_id}"
+  external_lb_ip_address          = "${module.vnet.public_lb_ip_address}"
+  internal_lb_ip_address          = "${module.vnet.internal_lb_ip_address}"
+  etcd_count                      = "${var.master_count}"
+  etcd_ip_addresses               = "${module.master.ip_addresses}"
+
+  tags = "${local.tags}"
+}
+
+resource "azurerm_resource_group" "main" {
+  name     = "${var.cluster_id}-rg"
+  location = "${var.azure_region}"
+
+  tags = "${local.tags}"
+}
+
+resource "azurerm_user_assigned_identity" "main" {
+  name                = "${var.cluster_id}-identity"
+  resource_group_name = "${azurerm_resource_group.main.name}"
+  location            = "${azurerm_resource_group.main.location}"
+
+  tags = "${local.tags}"
+}
+
+# the machines manage the resources of the cluster with the identity, like
+# the load balancers of services and the disks of persistent volumes
+resource "azurerm_role_assignment" "main" {
+  scope                = "${azurerm_resource_group.main.id}"
+  role_definition_name = "Contributor"
+  principal_id         = "${azurerm_user_assigned_identity.main.principal_id}"
+}
+
+resource "azurerm_storage_account" "cluster" {
+  name                     = "${local.storage_account_name}"
+  resource_group_name      = "${azurerm_resource_group.main.name}"
+  location                 = "${var.azure_region}"
+  account_tier             = "Standard"
+  account_replication_type = "LRS"
+
+  tags = "${local.tags}"
+}
+
+resource "azurerm_storage_container" "vhd" {
+  name                  = "vhd"
+  resource_group_name   = "${azurerm_resource_group.main.name}"
+  storage_account_name  = "${azurerm_storage_account.cluster.name}"
+  container_access_type = "private"
+}
+
+# Azure only creates images from VHDs in a storage account of the same
+# region, so the RHCOS VHD is copied first
+resource "azurerm_storage_blob" "rhcos_image" {
+  name                   = "rhcos.vhd"
+  resource_group_name    = "${azurerm_resource_group.main.name}"
+  storage_account_name   = "${azurerm_storage_account.cluster.name}"
+  storage_container_name = "${azurerm_storage_container.vhd.name}"
+  type                   = "page"
+  source_uri             = "${var.azure_image_url}"
+
+  lifecycle {
+    ignore_changes = ["source_uri"]
+  }
+}
+
+resource "azurerm_image" "cluster" {
+  name                = "${var.cluster_id}"
+  resource_group_name = "${azurerm_resource_group.main.name}"
+  location            = "${var.azure_region}"
+
+  os_disk {
+    os_type  = "Linux"
+    os_state = "Generalized"
+    blob_uri = "${azurerm_storage_blob.rhcos_image.url}"
+  }
+
+  tags = "${local.tags}"
+}
@@ -0,0 +1,94 @@
+locals {
+  ip_configuration_name = "pipConfig"
+}
+
+resource "azurerm_network_interface" "master" {
+  count = "${var.instance_count}"
+
+  name                = "${var.cluster_id}-master${count.index}-nic"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+
+  ip_configuration {
+    name                          = "${local.ip_configuration_name}"
+    subnet_id                     = "${var.subnet_id}"
+    private_ip_address_allocation = "Dynamic"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_network_interface_backend_address_pool_association" "master" {
+  count = "${var.instance_count}"
+
+  network_interface_id    = "${element(azurerm_network_interface.master.*.id, count.index)}"
+  backend_address_pool_id = "${var.elb_backend_pool_id}"
+  ip_configuration_name   = "${local.ip_configuration_name}"
+}
+
+resource "azurerm_network_interface_backend_address_pool_association" "master_internal" {
+  count = "${var.instance_count}"
+
+  network_interface_id    = "${element(azurerm_network_interface.master.*.id, count.index)}"
+  backend_address_pool_id = "${var.ilb_backend_pool_id}"
+  ip_configuration_name   = "${local.ip_configuration_name}"
+}
+
+resource "azurerm_virtual_machine" "master" {
+  count = "${var.instance_count}"
+
+  name                  = "${var.cluster_id}-master-${count.index}"
+  resource_group_name   = "${var.resource_group_name}"
+  location              = "${var.region}"
+  network_interface_ids = ["${element(azurerm_network_interface.master.*.id, count.index)}"]
+  vm_size               = "${var.vm_size}"
+
+  # an empty zone places the master outside of the availability zones
+  zones = ["${compact(list(element(var.availability_zones, count.index)))}"]
+
+  delete_os_disk_on_termination    = true
+  delete_data_disks_on_termination = true
+
+  identity {
+    type         = "UserAssigned"
+    identity_ids = ["${var.identity}"]
+  }
+
+  storage_os_disk {
+    name              = "${var.cluster_id}-master-${count.index}_OSDisk"
+    caching           = "ReadOnly"
+    create_option     = "FromImage"
+    managed_disk_type = "Premium_LRS"
+    disk_size_gb      = "${var.os_volume_size}"
+  }
+
+  storage_image_reference {
+    id = "${var.vm_image}"
+  }
+
+  # RHCOS ignores the administrator, which Azure requires
+  os_profile {
+    computer_name  = "${var.cluster_id}-master-${count.index}"
+    admin_username = "core"
+    admin_password = "NotActuallyApplied!"
+    custom_data    = "${var.ignition}"
+  }
+
+  os_profile_linux_config {
+    disable_password_authentication = false
+  }
+
+  boot_diagnostics {
+    enabled     = true
+    storage_uri = "${var.storage_primary_endpoint}"
+  }
+
+  lifecycle {
+    # Ignore changes in the image which force recreation of the resource.
+    # This avoids accidental deletion of nodes whenever a new OS release
+    # comes out.
+    ignore_changes = ["storage_image_reference"]
+  }
+
+  tags = "${var.tags}"
+}
@@ -0,0 +1,3 @@
+output "ip_addresses" {
+  value = "${azurerm_network_interface.master.*.private_ip_address}"
+}
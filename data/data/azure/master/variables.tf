@@ -0,0 +1,75 @@
+variable "availability_zones" {
+  type        = "list"
+  description = "The availability zones of the masters, or empty strings for no zone."
+}
+
+variable "cluster_id" {
+  type        = "string"
+  description = "The identifier for the cluster."
+}
+
+variable "elb_backend_pool_id" {
+  type        = "string"
+  description = "The external load balancer backend pool ID, used to attach the master NICs."
+}
+
+variable "identity" {
+  type        = "string"
+  description = "The user assigned identity ID for the masters."
+}
+
+variable "ignition" {
+  type        = "string"
+  description = "The content of the master ignition file."
+}
+
+variable "ilb_backend_pool_id" {
+  type        = "string"
+  description = "The internal load balancer backend pool ID, used to attach the master NICs."
+}
+
+variable "instance_count" {
+  type        = "string"
+  description = "The number of masters."
+}
+
+variable "os_volume_size" {
+  type        = "string"
+  description = "The size of the volume in gigabytes for the root block device."
+}
+
+variable "region" {
+  type        = "string"
+  description = "The region for the deployment."
+}
+
+variable "resource_group_name" {
+  type        = "string"
+  description = "The resource group for the deployment."
+}
+
+variable "storage_primary_endpoint" {
+  type        = "string"
+  description = "The primary blob endpoint of the storage account, used for boot diagnostics."
+}
+
+variable "subnet_id" {
+  type        = "string"
+  description = "The subnet ID for the masters."
+}
+
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "Azure tags to be applied to created resources."
+}
+
+variable "vm_image" {
+  type        = "string"
+  description = "The resource ID of the image for the masters."
+}
+
+variable "vm_size" {
+  type        = "string"
+  description = "The instance type of the masters."
+}
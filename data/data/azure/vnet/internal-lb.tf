@@ -0,0 +1,68 @@
+resource "azurerm_lb" "internal" {
+  name                = "${var.cluster_id}-internal"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+  sku                 = "Standard"
+
+  frontend_ip_configuration {
+    name                          = "internal-lb-ip"
+    subnet_id                     = "${azurerm_subnet.master_subnet.id}"
+    private_ip_address_allocation = "Dynamic"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_lb_backend_address_pool" "internal_lb_controlplane_pool" {
+  name                = "${var.cluster_id}-internal-controlplane"
+  resource_group_name = "${var.resource_group_name}"
+  loadbalancer_id     = "${azurerm_lb.internal.id}"
+}
+
+resource "azurerm_lb_probe" "internal_lb_probe_api" {
+  name                = "api-internal-probe"
+  resource_group_name = "${var.resource_group_name}"
+  loadbalancer_id     = "${azurerm_lb.internal.id}"
+  protocol            = "Tcp"
+  port                = 6443
+  interval_in_seconds = 10
+  number_of_probes    = 3
+}
+
+resource "azurerm_lb_probe" "internal_lb_probe_sint" {
+  name                = "sint-probe"
+  resource_group_name = "${var.resource_group_name}"
+  loadbalancer_id     = "${azurerm_lb.internal.id}"
+  protocol            = "Tcp"
+  port                = 22623
+  interval_in_seconds = 10
+  number_of_probes    = 3
+}
+
+resource "azurerm_lb_rule" "internal_lb_rule_api" {
+  name                           = "api-internal"
+  resource_group_name            = "${var.resource_group_name}"
+  loadbalancer_id                = "${azurerm_lb.internal.id}"
+  protocol                       = "Tcp"
+  frontend_port                  = 6443
+  backend_port                   = 6443
+  frontend_ip_configuration_name = "internal-lb-ip"
+  backend_address_pool_id        = "${azurerm_lb_backend_address_pool.internal_lb_controlplane_pool.id}"
+  probe_id                       = "${azurerm_lb_probe.internal_lb_probe_api.id}"
+  idle_timeout_in_minutes        = 30
+  load_distribution              = "Default"
+}
+
+resource "azurerm_lb_rule" "internal_lb_rule_sint" {
+  name                           = "sint"
+  resource_group_name            = "${var.resource_group_name}"
+  loadbalancer_id                = "${azurerm_lb.internal.id}"
+  protocol                       = "Tcp"
+  frontend_port                  = 22623
+  backend_port                   = 22623
+  frontend_ip_configuration_name = "internal-lb-ip"
+  backend_address_pool_id        = "${azurerm_lb_backend_address_pool.internal_lb_controlplane_pool.id}"
+  probe_id                       = "${azurerm_lb_probe.internal_lb_probe_sint.id}"
+  idle_timeout_in_minutes        = 30
+  load_distribution              = "Default"
+}
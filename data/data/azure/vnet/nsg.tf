@@ -0,0 +1,34 @@
+# The default rules of the security groups allow the traffic within the
+# virtual network and from the Azure load balancers, so only the traffic
+# from the Internet needs rules.
+
+resource "azurerm_network_security_group" "master" {
+  name                = "${var.cluster_id}-controlplane-nsg"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_network_security_rule" "apiserver_in" {
+  name                        = "apiserver_in"
+  priority                    = 101
+  direction                   = "Inbound"
+  access                      = "Allow"
+  protocol                    = "Tcp"
+  source_port_range           = "*"
+  destination_port_range      = "6443"
+  source_address_prefix       = "*"
+  destination_address_prefix  = "*"
+  resource_group_name         = "${var.resource_group_name}"
+  network_security_group_name = "${azurerm_network_security_group.master.name}"
+}
+
+# the cloud provider adds the rules of the services to this group
+resource "azurerm_network_security_group" "worker" {
+  name                = "${var.cluster_id}-node-nsg"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+
+  tags = "${var.tags}"
+}
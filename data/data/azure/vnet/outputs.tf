@@ -0,0 +1,31 @@
+output "virtual_network_id" {
+  value = "${azurerm_virtual_network.cluster_vnet.id}"
+}
+
+output "master_subnet_id" {
+  value = "${azurerm_subnet.master_subnet.id}"
+}
+
+output "worker_subnet_id" {
+  value = "${azurerm_subnet.worker_subnet.id}"
+}
+
+output "master_nsg_name" {
+  value = "${azurerm_network_security_group.master.name}"
+}
+
+output "public_lb_backend_pool_id" {
+  value = "${azurerm_lb_backend_address_pool.master_public_lb_pool.id}"
+}
+
+output "internal_lb_backend_pool_id" {
+  value = "${azurerm_lb_backend_address_pool.internal_lb_controlplane_pool.id}"
+}
+
+output "public_lb_ip_address" {
+  value = "${azurerm_public_ip.cluster_public_ip.ip_address}"
+}
+
+output "internal_lb_ip_address" {
+  value = "${azurerm_lb.internal.private_ip_address}"
+}
@@ -0,0 +1,56 @@
+resource "azurerm_public_ip" "cluster_public_ip" {
+  name                = "${var.cluster_id}-pip"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+  sku                 = "Standard"
+  allocation_method   = "Static"
+  domain_name_label   = "${var.cluster_id}"
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_lb" "public" {
+  name                = "${var.cluster_id}"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+  sku                 = "Standard"
+
+  frontend_ip_configuration {
+    name                 = "public-lb-ip"
+    public_ip_address_id = "${azurerm_public_ip.cluster_public_ip.id}"
+  }
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_lb_backend_address_pool" "master_public_lb_pool" {
+  name                = "${var.cluster_id}-public-lb-control-plane"
+  resource_group_name = "${var.resource_group_name}"
+  loadbalancer_id     = "${azurerm_lb.public.id}"
+}
+
+resource "azurerm_lb_probe" "public_lb_probe_api" {
+  name                = "api-public-probe"
+  resource_group_name = "${var.resource_group_name}"
+  loadbalancer_id     = "${azurerm_lb.public.id}"
+  protocol            = "Tcp"
+  port                = 6443
+  interval_in_seconds = 10
+  number_of_probes    = 3
+}
+
+# the rule also gives the masters their outbound connectivity, which the
+# Standard load balancers only provide to the members of a balanced pool
+resource "azurerm_lb_rule" "public_lb_rule_api" {
+  name                           = "api-public"
+  resource_group_name            = "${var.resource_group_name}"
+  loadbalancer_id                = "${azurerm_lb.public.id}"
+  protocol                       = "Tcp"
+  frontend_port                  = 6443
+  backend_port                   = 6443
+  frontend_ip_configuration_name = "public-lb-ip"
+  backend_address_pool_id        = "${azurerm_lb_backend_address_pool.master_public_lb_pool.id}"
+  probe_id                       = "${azurerm_lb_probe.public_lb_probe_api.id}"
+  idle_timeout_in_minutes        = 30
+  load_distribution              = "Default"
+}
@@ -0,0 +1,25 @@
+variable "cidr_block" {
+  type        = "string"
+  description = "The address space of the virtual network."
+}
+
+variable "cluster_id" {
+  type        = "string"
+  description = "The identifier for the cluster."
+}
+
+variable "region" {
+  type        = "string"
+  description = "The region for the deployment."
+}
+
+variable "resource_group_name" {
+  type        = "string"
+  description = "The resource group for the deployment."
+}
+
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "Azure tags to be applied to created resources."
+}
@@ -0,0 +1,50 @@
+locals {
+  master_subnet_cidr = "${cidrsubnet(var.cidr_block, 1, 0)}"
+  worker_subnet_cidr = "${cidrsubnet(var.cidr_block, 1, 1)}"
+}
+
+resource "azurerm_virtual_network" "cluster_vnet" {
+  name                = "${var.cluster_id}-vnet"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+  address_space       = ["${var.cidr_block}"]
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_route_table" "route_table" {
+  name                = "${var.cluster_id}-node-routetable"
+  resource_group_name = "${var.resource_group_name}"
+  location            = "${var.region}"
+
+  tags = "${var.tags}"
+}
+
+resource "azurerm_subnet" "master_subnet" {
+  name                 = "${var.cluster_id}-master-subnet"
+  resource_group_name  = "${var.resource_group_name}"
+  virtual_network_name = "${azurerm_virtual_network.cluster_vnet.name}"
+  address_prefix       = "${local.master_subnet_cidr}"
+}
+
+resource "azurerm_subnet_network_security_group_association" "master" {
+  subnet_id                 = "${azurerm_subnet.master_subnet.id}"
+  network_security_group_id = "${azurerm_network_security_group.master.id}"
+}
+
+resource "azurerm_subnet" "worker_subnet" {
+  name                 = "${var.cluster_id}-worker-subnet"
+  resource_group_name  = "${var.resource_group_name}"
+  virtual_network_name = "${azurerm_virtual_network.cluster_vnet.name}"
+  address_prefix       = "${local.worker_subnet_cidr}"
+}
+
+resource "azurerm_subnet_network_security_group_association" "worker" {
+  subnet_id                 = "${azurerm_subnet.worker_subnet.id}"
+  network_security_group_id = "${azurerm_network_security_group.worker.id}"
+}
+
+resource "azurerm_subnet_route_table_association" "worker" {
+  subnet_id      = "${azurerm_subnet.worker_subnet.id}"
+  route_table_id = "${azurerm_route_table.route_table.id}"
+}
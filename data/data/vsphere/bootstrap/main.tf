@@ -0,0 +1,97 @@
+locals {
+  prefix  = "${element(split("/", var.machine_cidr), 1)}"
+  gateway = "${cidrhost(var.machine_cidr, 1)}"
+
+  # ifcfg files take up to three DNS servers
+  dns_count = "${min(3, length(var.dns_servers))}"
+  dns       = "${join("\n", formatlist("DNS%s=%s", slice(list("1", "2", "3"), 0, local.dns_count), slice(var.dns_servers, 0, local.dns_count)))}"
+}
+
+data "ignition_file" "hostname" {
+  filesystem = "root"
+  path       = "/etc/hostname"
+  mode       = "420"
+
+  content {
+    content = "${var.cluster_id}-bootstrap"
+  }
+}
+
+data "ignition_file" "static_ip" {
+  filesystem = "root"
+  path       = "/etc/sysconfig/network-scripts/ifcfg-eth0"
+  mode       = "420"
+
+  content {
+    content = <<EOF
+TYPE=Ethernet
+BOOTPROTO=none
+NAME=eth0
+DEVICE=eth0
+ONBOOT=yes
+IPADDR=${var.ip_address}
+PREFIX=${local.prefix}
+GATEWAY=${local.gateway}
+${local.dns}
+EOF
+  }
+}
+
+# the network configuration only applies after a reboot
+data "ignition_systemd_unit" "restart" {
+  name = "restart.service"
+
+  content = <<EOF
+[Unit]
+ConditionFirstBoot=yes
+[Service]
+Type=idle
+ExecStart=/sbin/reboot
+[Install]
+WantedBy=multi-user.target
+EOF
+}
+
+data "ignition_config" "bootstrap" {
+  append {
+    source = "data:text/plain;charset=utf-8;base64,${base64encode(var.ignition)}"
+  }
+
+  systemd = ["${data.ignition_systemd_unit.restart.id}"]
+
+  files = [
+    "${data.ignition_file.hostname.id}",
+    "${data.ignition_file.static_ip.id}",
+  ]
+}
+
+resource "vsphere_virtual_machine" "bootstrap" {
+  name             = "${var.cluster_id}-bootstrap"
+  resource_pool_id = "${var.resource_pool_id}"
+  datastore_id     = "${var.datastore_id}"
+  folder           = "${var.folder}"
+  num_cpus         = "${var.num_cpus}"
+  memory           = "${var.memory}"
+  guest_id         = "${var.guest_id}"
+  enable_disk_uuid = "true"
+  tags             = ["${var.tag_id}"]
+
+  network_interface {
+    network_id = "${var.network_id}"
+  }
+
+  disk {
+    label            = "disk0"
+    size             = "${var.disk_size}"
+    thin_provisioned = "${var.thin_provisioned}"
+  }
+
+  clone {
+    template_uuid = "${var.template_id}"
+  }
+
+  extra_config {
+    "guestinfo.ignition.config.data"          = "${base64encode(data.ignition_config.bootstrap.rendered)}"
+    "guestinfo.ignition.config.data.encoding" = "base64"
+  }
+}
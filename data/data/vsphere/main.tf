@@ -0,0 +1,88 @@
+provider "vsphere" {}
+
+data "vsphere_datacenter" "dc" {
+  name = "${var.vsphere_datacenter}"
+}
+
+data "vsphere_resource_pool" "pool" {
+  name          = "${var.vsphere_resource_pool}"
+  datacenter_id = "${data.vsphere_datacenter.dc.id}"
+}
+
+data "vsphere_datastore" "datastore" {
+  name          = "${var.vsphere_datastore}"
+  datacenter_id = "${data.vsphere_datacenter.dc.id}"
+}
+
+data "vsphere_network" "network" {
+  name          = "${var.vsphere_network}"
+  datacenter_id = "${data.vsphere_datacenter.dc.id}"
+}
+
+# the installer imports the template before running Terraform
+data "vsphere_virtual_machine" "template" {
+  name          = "${var.vsphere_template}"
+  datacenter_id = "${data.vsphere_datacenter.dc.id}"
+}
+
+# the installer creates the tag with the template, and the destroyer
+# deletes the resources it is attached to
+data "vsphere_tag_category" "category" {
+  name = "${var.vsphere_tag_category}"
+}
+
+data "vsphere_tag" "tag" {
+  name        = "${var.vsphere_tag}"
+  category_id = "${data.vsphere_tag_category.category.id}"
+}
+
+resource "vsphere_folder" "folder" {
+  path          = "${var.vsphere_folder}"
+  type          = "vm"
+  datacenter_id = "${data.vsphere_datacenter.dc.id}"
+  tags          = ["${data.vsphere_tag.tag.id}"]
+}
+
+module "bootstrap" {
+  source = "./bootstrap"
+
+  cluster_id       = "${var.cluster_id}"
+  ignition         = "${var.ignition_bootstrap}"
+  ip_address       = "${var.vsphere_bootstrap_ip}"
+  machine_cidr     = "${var.machine_cidr}"
+  dns_servers      = ["${var.vsphere_dns_servers}"]
+  resource_pool_id = "${data.vsphere_resource_pool.pool.id}"
+  datastore_id     = "${data.vsphere_datastore.datastore.id}"
+  network_id       = "${data.vsphere_network.network.id}"
+  folder           = "${vsphere_folder.folder.path}"
+  template_id      = "${data.vsphere_virtual_machine.template.id}"
+  guest_id         = "${data.vsphere_virtual_machine.template.guest_id}"
+  thin_provisioned = "${data.vsphere_virtual_machine.template.disks.0.thin_provisioned}"
+  num_cpus         = "${var.vsphere_control_plane_num_cpus}"
+  memory           = "${var.vsphere_control_plane_memory_mib}"
+  disk_size        = "${var.vsphere_control_plane_disk_gib}"
+  tag_id           = "${data.vsphere_tag.tag.id}"
+}
+
+module "master" {
+  source = "./master"
+
+  cluster_id       = "${var.cluster_id}"
+  instance_count   = "${var.master_count}"
+  ignition         = "${var.ignition_master}"
+  ip_addresses     = ["${var.vsphere_control_plane_ips}"]
+  machine_cidr     = "${var.machine_cidr}"
+  dns_servers      = ["${var.vsphere_dns_servers}"]
+  resource_pool_id = "${data.vsphere_resource_pool.pool.id}"
+  datastore_id     = "${data.vsphere_datastore.datastore.id}"
+  network_id       = "${data.vsphere_network.network.id}"
+  folder           = "${vsphere_folder.folder.path}"
+  template_id      = "${data.vsphere_virtual_machine.template.id}"
+  guest_id         = "${data.vsphere_virtual_machine.template.guest_id}"
+  thin_provisioned = "${data.vsphere_virtual_machine.template.disks.0.thin_provisioned}"
+  num_cpus         = "${var.vsphere_control_plane_num_cpus}"
+  cores_per_socket = "${var.vsphere_control_plane_cores_per_socket}"
+  memory           = "${var.vsphere_control_plane_memory_mib}"
+  disk_size        = "${var.vsphere_control_plane_disk_gib}"
+  tag_id           = "${data.vsphere_tag.tag.id}"
+}
@@ -0,0 +1,74 @@
+variable "cluster_id" {
+  type = "string"
+}
+
+variable "instance_count" {
+  type = "string"
+}
+
+variable "ignition" {
+  type = "string"
+}
+
+variable "ip_addresses" {
+  type        = "list"
+  description = "The static IP addresses of the masters."
+}
+
+variable "machine_cidr" {
+  type = "string"
+}
+
+variable "dns_servers" {
+  type = "list"
+}
+
+variable "resource_pool_id" {
+  type = "string"
+}
+
+variable "datastore_id" {
+  type = "string"
+}
+
+variable "network_id" {
+  type = "string"
+}
+
+variable "folder" {
+  type = "string"
+}
+
+variable "template_id" {
+  type = "string"
+}
+
+variable "guest_id" {
+  type = "string"
+}
+
+variable "thin_provisioned" {
+  type = "string"
+}
+
+variable "num_cpus" {
+  type = "string"
+}
+
+variable "cores_per_socket" {
+  type = "string"
+}
+
+variable "memory" {
+  type        = "string"
+  description = "The memory size in MiB."
+}
+
+variable "disk_size" {
+  type        = "string"
+  description = "The disk size in GiB."
+}
+
+variable "tag_id" {
+  type = "string"
+}
@@ -0,0 +1,92 @@
+variable "vsphere_config_version" {
+  description = <<EOF
+(internal) This declares the version of the vSphere configuration variables.
+It has no impact on generated assets but declares the version contract of the configuration.
+EOF
+
+  default = "0.1"
+}
+
+variable "vsphere_datacenter" {
+  type        = "string"
+  description = "The datacenter in which the cluster is created."
+}
+
+variable "vsphere_cluster" {
+  type        = "string"
+  description = "The vSphere cluster in which the machines are created."
+}
+
+variable "vsphere_resource_pool" {
+  type        = "string"
+  description = "The inventory path of the resource pool of the machines."
+}
+
+variable "vsphere_datastore" {
+  type        = "string"
+  description = "The datastore of the disks of the machines."
+}
+
+variable "vsphere_folder" {
+  type        = "string"
+  description = "The path of the VM folder of the cluster, which is created in the datacenter."
+}
+
+variable "vsphere_network" {
+  type        = "string"
+  description = "The network to which the machines are connected."
+}
+
+variable "vsphere_template" {
+  type        = "string"
+  description = "The name of the RHCOS template from which the machines are cloned."
+}
+
+variable "vsphere_tag_category" {
+  type        = "string"
+  description = "The tag category of the cluster."
+}
+
+variable "vsphere_tag" {
+  type        = "string"
+  description = "The tag attached to the resources of the cluster."
+}
+
+variable "vsphere_bootstrap_ip" {
+  type        = "string"
+  description = "The static IP address of the bootstrap machine."
+}
+
+variable "vsphere_control_plane_ips" {
+  type        = "list"
+  description = "The static IP addresses of the control-plane machines. The length of this list must match master_count."
+}
+
+variable "vsphere_dns_servers" {
+  type        = "list"
+  description = "The DNS servers of the bootstrap and control-plane machines."
+}
+
+variable "vsphere_control_plane_num_cpus" {
+  type        = "string"
+  description = "The number of virtual CPUs of the bootstrap and control-plane machines."
+  default     = "4"
+}
+
+variable "vsphere_control_plane_cores_per_socket" {
+  type        = "string"
+  description = "The number of cores per socket of the control-plane machines."
+  default     = "1"
+}
+
+variable "vsphere_control_plane_memory_mib" {
+  type        = "string"
+  description = "The memory size in MiB of the bootstrap and control-plane machines."
+  default     = "16384"
+}
+
+variable "vsphere_control_plane_disk_gib" {
+  type        = "string"
+  description = "The disk size in GiB of the bootstrap and control-plane machines."
+  default     = "120"
+}
@@ -0,0 +1,47 @@
+#!/bin/sh
+# dep does not support the semantic import versioning of
+# github.com/coreos/ignition/v2, so this copies its spec 3.0 types, and the
+# packages they import, into vendor/.  Run it again after `dep ensure`.
+# Example:  ./hack/vendor-ignition-v2.sh
+
+set -ex
+
+IGNITION_VERSION=v2.0.0
+VCONTEXT_VERSION=22b159166068
+
+TMP_DIR="$(mktemp -d)"
+trap 'rm -rf "${TMP_DIR}"' EXIT
+
+git clone --quiet https://github.com/coreos/ignition "${TMP_DIR}/ignition"
+git -C "${TMP_DIR}/ignition" checkout --quiet "${IGNITION_VERSION}"
+git clone --quiet https://github.com/coreos/vcontext "${TMP_DIR}/vcontext"
+git -C "${TMP_DIR}/vcontext" checkout --quiet "${VCONTEXT_VERSION}"
+
+copy() {
+  SOURCE="${1}"
+  DESTINATION="${2}"
+  shift 2
+  rm -rf "${DESTINATION}"
+  mkdir -p "${DESTINATION}"
+  for FILE in LICENSE NOTICE
+  do
+    if [ -e "${SOURCE}/${FILE}" ]; then
+      cp "${SOURCE}/${FILE}" "${DESTINATION}"
+    fi
+  done
+  for PACKAGE in "${@}"
+  do
+    mkdir -p "${DESTINATION}/${PACKAGE}"
+    find "${SOURCE}/${PACKAGE}" -maxdepth 1 -name '*.go' ! -name '*_test.go' -exec cp {} "${DESTINATION}/${PACKAGE}" \;
+  done
+}
+
+copy "${TMP_DIR}/ignition" vendor/github.com/coreos/ignition/v2 \
+  config/shared/errors \
+  config/shared/validations \
+  config/util \
+  config/v3_0/types
+copy "${TMP_DIR}/vcontext" vendor/github.com/coreos/vcontext \
+  path \
+  report \
+  tree
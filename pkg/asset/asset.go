@@ -36,6 +36,27 @@ type WritableAsset interface {
 	Load(FileFetcher) (found bool, err error)
 }
 
+// ConcurrentAsset is an Asset that can be generated concurrently with other
+// assets. Its Generate must only depend on its parents and must not prompt
+// the user or modify any shared state.
+type ConcurrentAsset interface {
+	Asset
+
+	// Concurrent marks the asset as safe for concurrent generation.
+	Concurrent()
+}
+
+// PersistentAsset is a WritableAsset whose files are inputs provided by the
+// user, which are kept in the target directory instead of being consumed
+// when the assets depending on them are fetched.
+type PersistentAsset interface {
+	WritableAsset
+
+	// Persistent marks the files of the asset as kept in the target
+	// directory.
+	Persistent()
+}
+
 // File is a file for an Asset.
 type File struct {
 	// Filename is the name of the file.
@@ -0,0 +1,23 @@
+// Package azure extracts Azure metadata from install configurations.
+package azure
+
+import (
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+// Metadata converts an install configuration to Azure metadata.
+func Metadata(infraID string, config *types.InstallConfig) *azure.Metadata {
+	return &azure.Metadata{
+		Region:                      config.Platform.Azure.Region,
+		ResourceGroupName:           ResourceGroupName(infraID),
+		BaseDomainResourceGroupName: config.Platform.Azure.BaseDomainResourceGroupName,
+		ClusterDomain:               config.ClusterDomain(),
+	}
+}
+
+// ResourceGroupName returns the name of the resource group holding the
+// resources of the cluster with the given infrastructure ID.
+func ResourceGroupName(infraID string) string {
+	return infraID + "-rg"
+}
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,11 +11,22 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster/vsphere"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	"github.com/openshift/installer/pkg/asset/password"
+	"github.com/openshift/installer/pkg/asset/rhcos"
 	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types"
 )
 
+// applyTerraform runs 'terraform apply'.  It is replaced in tests.
+var applyTerraform = terraform.Apply
+
+// importTemplate imports the vSphere RHCOS template.  It is replaced in
+// tests.
+var importTemplate = importVSphereTemplate
+
 // Cluster uses the terraform executable to launch a cluster
 // with the given terraform tfvar and generated templates.
 type Cluster struct {
@@ -40,6 +52,7 @@ func (c *Cluster) Dependencies() []asset.Asset {
 		&installconfig.PlatformCredsCheck{},
 		&TerraformVariables{},
 		&password.KubeadminPassword{},
+		new(rhcos.Image),
 	}
 }
 
@@ -48,7 +61,8 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	terraformVariables := &TerraformVariables{}
-	parents.Get(clusterID, installConfig, terraformVariables)
+	rhcosImage := new(rhcos.Image)
+	parents.Get(clusterID, installConfig, terraformVariables, rhcosImage)
 
 	if installConfig.Config.Platform.None != nil {
 		return errors.New("cluster cannot be created with platform set to 'none'")
@@ -61,16 +75,26 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	extraArgs := []string{}
-	for _, file := range terraformVariables.Files() {
-		if err := ioutil.WriteFile(filepath.Join(tmpDir, file.Filename), file.Data, 0600); err != nil {
-			return err
-		}
-		extraArgs = append(extraArgs, fmt.Sprintf("-var-file=%s", filepath.Join(tmpDir, file.Filename)))
+	extraArgs, err := writeTerraformVariables(tmpDir, terraformVariables)
+	if err != nil {
+		return err
+	}
+
+	if err := setupTerraformEnvironment(installConfig); err != nil {
+		return err
+	}
+	if err := importRHCOSTemplate(clusterID, installConfig, rhcosImage); err != nil {
+		return err
 	}
 
 	logrus.Infof("Creating infrastructure resources...")
-	stateFile, err := terraform.Apply(tmpDir, installConfig.Config.Platform.Name(), extraArgs...)
+	return c.apply(tmpDir, installConfig.Config.Platform.Name(), extraArgs)
+}
+
+// apply runs 'terraform apply' in the given directory and adds the
+// resulting Terraform state file to the asset, even if the apply failed.
+func (c *Cluster) apply(dir string, platform string, extraArgs []string) error {
+	stateFile, err := applyTerraform(dir, platform, extraArgs...)
 	if err != nil {
 		err = errors.Wrap(err, "failed to create cluster")
 		if stateFile == "" {
@@ -96,6 +120,66 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	return err
 }
 
+// setupTerraformEnvironment exports the platform credentials which are not
+// written in the Terraform variables, for the Terraform runs creating,
+// planning or resuming the cluster.
+func setupTerraformEnvironment(installConfig *installconfig.InstallConfig) error {
+	if installConfig.Config.Platform.Azure != nil {
+		credentials, err := azureconfig.LoadCredentials()
+		if err != nil {
+			return err
+		}
+		if err := credentials.SetTerraformEnvironment(); err != nil {
+			return err
+		}
+	}
+
+	if installConfig.Config.Platform.VSphere != nil {
+		metadata := vsphere.Metadata(installConfig.Config)
+		if err := vsphere.SetTerraformEnvironment(metadata, vsphere.Password(installConfig.Config)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRHCOSTemplate imports the RHCOS template read by the Terraform
+// configuration on vSphere, before the Terraform runs creating or resuming
+// the cluster.  Terraform clones the machines from the template, which the
+// vsphere provider cannot import without powering it on.
+func importRHCOSTemplate(clusterID *installconfig.ClusterID, installConfig *installconfig.InstallConfig, rhcosImage *rhcos.Image) error {
+	if installConfig.Config.Platform.VSphere == nil {
+		return nil
+	}
+	err := importTemplate(context.TODO(), installConfig.Config, clusterID.InfraID, string(*rhcosImage))
+	return errors.Wrap(err, "failed to import the RHCOS template")
+}
+
+// importVSphereTemplate logs in to the vCenter of the workspace and imports
+// the RHCOS template from the OVA at ovaURL.
+func importVSphereTemplate(ctx context.Context, config *types.InstallConfig, infraID string, ovaURL string) error {
+	metadata := vsphere.Metadata(config)
+	client, err := vsphere.NewClient(ctx, metadata.VCenter, metadata.Username, vsphere.Password(config))
+	if err != nil {
+		return err
+	}
+	defer client.Logout(ctx)
+	return vsphere.ImportTemplate(ctx, client, config.Platform.VSphere, infraID, ovaURL)
+}
+
+// writeTerraformVariables writes the Terraform variables into the given
+// directory and returns the arguments that pass them to Terraform.
+func writeTerraformVariables(dir string, terraformVariables *TerraformVariables) ([]string, error) {
+	args := []string{}
+	for _, file := range terraformVariables.Files() {
+		if err := ioutil.WriteFile(filepath.Join(dir, file.Filename), file.Data, 0600); err != nil {
+			return nil, err
+		}
+		args = append(args, fmt.Sprintf("-var-file=%s", filepath.Join(dir, file.Filename)))
+	}
+	return args, nil
+}
+
 // Files returns the FileList generated by the asset.
 func (c *Cluster) Files() []*asset.File {
 	return c.FileList
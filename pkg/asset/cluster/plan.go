@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/terraform"
+	texec "github.com/openshift/installer/pkg/terraform/exec"
+)
+
+// Plan uses the terraform executable to plan the cluster described by the
+// given dependencies of the cluster and returns the resources that would be created, without
+// creating any of them.  vSphere is not supported, since its Terraform
+// configuration reads the RHCOS template, which only exists once the cluster
+// is being created.
+func Plan(parents asset.Parents) ([]texec.ResourceChange, error) {
+	installConfig := &installconfig.InstallConfig{}
+	terraformVariables := &TerraformVariables{}
+	parents.Get(installConfig, terraformVariables)
+
+	if installConfig.Config.Platform.None != nil {
+		return nil, errors.New("cluster cannot be planned with platform set to 'none'")
+	}
+	if installConfig.Config.Platform.VSphere != nil {
+		return nil, errors.New("cluster cannot be planned on vSphere, since the RHCOS template read by the Terraform configuration is only imported when the cluster is created")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir for terraform execution")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	extraArgs, err := writeTerraformVariables(tmpDir, terraformVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setupTerraformEnvironment(installConfig); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Planning infrastructure resources...")
+	changes, err := terraform.Plan(tmpDir, installConfig.Config.Platform.Name(), extraArgs...)
+	return changes, errors.Wrap(err, "failed to plan cluster")
+}
@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/none"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestPlanUnsupportedPlatforms(t *testing.T) {
+	cases := []struct {
+		name        string
+		platform    types.Platform
+		expectedErr string
+	}{
+		{
+			name:        "none",
+			platform:    types.Platform{None: &none.Platform{}},
+			expectedErr: "cluster cannot be planned with platform set to 'none'",
+		},
+		{
+			name:        "vsphere",
+			platform:    types.Platform{VSphere: &vsphere.Platform{}},
+			expectedErr: "cluster cannot be planned on vSphere, since the RHCOS template read by the Terraform configuration is only imported when the cluster is created",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(
+				&installconfig.InstallConfig{
+					Config: &types.InstallConfig{Platform: tc.platform},
+				},
+				&TerraformVariables{},
+			)
+			_, err := Plan(parents)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/rhcos"
+	"github.com/openshift/installer/pkg/terraform"
+)
+
+// Resume uses the terraform executable to finish launching a cluster whose
+// creation failed part-way, starting from the terraform state file left in
+// the given directory and from the given dependencies of the cluster. The
+// returned cluster holds the updated terraform
+// state file, and is returned even if the apply failed so that the state
+// can be written back to the directory.
+func Resume(directory string, parents asset.Parents) (*Cluster, error) {
+	clusterID := &installconfig.ClusterID{}
+	installConfig := &installconfig.InstallConfig{}
+	terraformVariables := &TerraformVariables{}
+	rhcosImage := new(rhcos.Image)
+	parents.Get(clusterID, installConfig, terraformVariables, rhcosImage)
+
+	if installConfig.Config.Platform.None != nil {
+		return nil, errors.New("cluster cannot be created with platform set to 'none'")
+	}
+
+	state, err := ioutil.ReadFile(filepath.Join(directory, terraform.StateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("%q does not exist, there is no cluster to resume", terraform.StateFileName)
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(state, &terraform.State{}); err != nil {
+		return nil, errors.Wrapf(err, "%q is corrupt, the cluster cannot be resumed", terraform.StateFileName)
+	}
+
+	// Copy the terraform.tfvars and the terraform.tfstate to a temp directory where the terraform will be invoked within.
+	tmpDir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir for terraform execution")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, terraform.StateFileName), state, 0600); err != nil {
+		return nil, err
+	}
+	extraArgs, err := writeTerraformVariables(tmpDir, terraformVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setupTerraformEnvironment(installConfig); err != nil {
+		return nil, err
+	}
+	if err := importRHCOSTemplate(clusterID, installConfig, rhcosImage); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Resuming creation of infrastructure resources...")
+	c := &Cluster{}
+	err = c.apply(tmpDir, installConfig.Config.Platform.Name(), extraArgs)
+	if len(c.FileList) == 0 {
+		return nil, err
+	}
+	return c, err
+}
@@ -0,0 +1,190 @@
+package cluster
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/rhcos"
+	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/none"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+const (
+	partialState = `{"modules": [{"path": ["root"], "resources": {"aws_vpc.new_vpc": {"type": "aws_vpc", "primary": {"id": "vpc-1"}}}}]}`
+	fullState    = `{"modules": [{"path": ["root"], "resources": {"aws_vpc.new_vpc": {"type": "aws_vpc", "primary": {"id": "vpc-1"}}, "aws_instance.bootstrap": {"type": "aws_instance", "primary": {"id": "i-1"}}}}]}`
+)
+
+func TestResume(t *testing.T) {
+	cases := []struct {
+		name          string
+		state         string
+		platform      types.Platform
+		env           map[string]string
+		applyErr      error
+		expectedEnv   map[string]string
+		expectedOVA   string
+		expectedState string
+		expectedErr   string
+	}{
+		{
+			name:          "re-apply",
+			state:         partialState,
+			platform:      types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			expectedState: fullState,
+		},
+		{
+			name:          "failed re-apply",
+			state:         partialState,
+			platform:      types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			applyErr:      errors.New("failed to apply using Terraform"),
+			expectedState: fullState,
+			expectedErr:   "failed to create cluster: failed to apply using Terraform",
+		},
+		{
+			name:     "azure credentials",
+			state:    partialState,
+			platform: types.Platform{Azure: &azure.Platform{Region: "centralus"}},
+			env: map[string]string{
+				"AZURE_SUBSCRIPTION_ID": "subscription",
+				"AZURE_TENANT_ID":       "tenant",
+				"AZURE_CLIENT_ID":       "client",
+				"AZURE_CLIENT_SECRET":   "secret",
+			},
+			expectedEnv: map[string]string{
+				"ARM_SUBSCRIPTION_ID": "subscription",
+				"ARM_TENANT_ID":       "tenant",
+				"ARM_CLIENT_ID":       "client",
+				"ARM_CLIENT_SECRET":   "secret",
+			},
+			expectedState: fullState,
+		},
+		{
+			name:  "vsphere template and credentials",
+			state: partialState,
+			platform: types.Platform{VSphere: &vsphere.Platform{
+				VirtualCenters: []vsphere.VirtualCenter{{Name: "vcenter.example.com", Username: "admin", Password: "secret"}},
+				Workspace:      vsphere.Workspace{Server: "vcenter.example.com", Datacenter: "dc1"},
+			}},
+			expectedEnv: map[string]string{
+				"VSPHERE_SERVER":   "vcenter.example.com",
+				"VSPHERE_USER":     "admin",
+				"VSPHERE_PASSWORD": "secret",
+			},
+			expectedOVA:   "https://example.com/rhcos.ova",
+			expectedState: fullState,
+		},
+		{
+			name:        "missing state",
+			platform:    types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			expectedErr: `"terraform.tfstate" does not exist, there is no cluster to resume`,
+		},
+		{
+			name:        "corrupt state",
+			state:       `{"modules": [`,
+			platform:    types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			expectedErr: `"terraform.tfstate" is corrupt, the cluster cannot be resumed: unexpected end of JSON input`,
+		},
+		{
+			name:        "none platform",
+			state:       partialState,
+			platform:    types.Platform{None: &none.Platform{}},
+			expectedErr: "cluster cannot be created with platform set to 'none'",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "openshift-install-resume-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			if tc.state != "" {
+				if err := ioutil.WriteFile(filepath.Join(dir, terraform.StateFileName), []byte(tc.state), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for key, value := range tc.env {
+				os.Setenv(key, value)
+			}
+			defer func() {
+				for key := range tc.env {
+					os.Unsetenv(key)
+				}
+				for key := range tc.expectedEnv {
+					os.Unsetenv(key)
+				}
+			}()
+
+			imported := ""
+			defer func(importer func(context.Context, *types.InstallConfig, string, string) error) {
+				importTemplate = importer
+			}(importTemplate)
+			importTemplate = func(_ context.Context, _ *types.InstallConfig, infraID string, ovaURL string) error {
+				assert.Equal(t, "mycluster-abcde", infraID)
+				imported = ovaURL
+				return nil
+			}
+
+			defer func(apply func(string, string, ...string) (string, error)) { applyTerraform = apply }(applyTerraform)
+			applyTerraform = func(tmpDir string, platform string, extraArgs ...string) (string, error) {
+				assert.Equal(t, tc.platform.Name(), platform)
+				for key, value := range tc.expectedEnv {
+					assert.Equal(t, value, os.Getenv(key), key)
+				}
+				assert.Equal(t, []string{"-var-file=" + filepath.Join(tmpDir, TfVarsFileName)}, extraArgs)
+
+				// The apply starts from the state left by the failed
+				// creation.
+				stateFile := filepath.Join(tmpDir, terraform.StateFileName)
+				state, err := ioutil.ReadFile(stateFile)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.state, string(state))
+				}
+				if err := ioutil.WriteFile(stateFile, []byte(fullState), 0600); err != nil {
+					return "", err
+				}
+				return stateFile, tc.applyErr
+			}
+
+			rhcosImage := rhcos.Image("https://example.com/rhcos.ova")
+			parents := asset.Parents{}
+			parents.Add(
+				&installconfig.ClusterID{InfraID: "mycluster-abcde"},
+				&installconfig.InstallConfig{
+					Config: &types.InstallConfig{Platform: tc.platform},
+				},
+				&TerraformVariables{
+					FileList: []*asset.File{{Filename: TfVarsFileName, Data: []byte("{}")}},
+				},
+				&rhcosImage,
+			)
+			c, err := Resume(dir, parents)
+			assert.Equal(t, tc.expectedOVA, imported)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+			if tc.expectedState == "" {
+				assert.Nil(t, c)
+				return
+			}
+			if assert.NotNil(t, c) {
+				assert.Equal(t, []*asset.File{{Filename: terraform.StateFileName, Data: []byte(tc.expectedState)}}, c.Files())
+			}
+		})
+	}
+}
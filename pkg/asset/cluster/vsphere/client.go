@@ -0,0 +1,122 @@
+package vsphere
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// tagDescription is the description of the tags and tag categories created
+// by the installer.
+const tagDescription = "Added by openshift-install, do not remove"
+
+// Client holds the sessions of a vCenter user with the SOAP API, which
+// manages the inventory, and with the REST API, which manages the tags and
+// the content libraries.
+type Client struct {
+	SOAP *govmomi.Client
+	REST *rest.Client
+}
+
+// NewClient logs in to the vCenter, given as a domain name, an IP address
+// or the URL of its SDK endpoint.
+func NewClient(ctx context.Context, server string, username string, password string) (*Client, error) {
+	u, err := soap.ParseURL(server)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse the URL of %s", server)
+	}
+	u.User = url.UserPassword(username, password)
+
+	client, err := govmomi.NewClient(ctx, u, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "log in to %s", server)
+	}
+	restClient := rest.NewClient(client.Client)
+	if err := restClient.Login(ctx, u.User); err != nil {
+		client.Logout(ctx)
+		return nil, errors.Wrapf(err, "log in to the REST API of %s", server)
+	}
+	return &Client{SOAP: client, REST: restClient}, nil
+}
+
+// Logout ends the sessions of the client.
+func (c *Client) Logout(ctx context.Context) error {
+	restErr := c.REST.Logout(ctx)
+	if err := c.SOAP.Logout(ctx); err != nil {
+		return err
+	}
+	return restErr
+}
+
+// IsInvalidLogin returns true if the error is the rejection of the
+// credentials of the user, which is not worth retrying.
+func IsInvalidLogin(err error) bool {
+	err = errors.Cause(err)
+	if !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.InvalidLogin)
+	return ok
+}
+
+// FindTag returns the IDs of the tag category with the given name and of
+// its tag with the given name, or empty strings when they do not exist.
+func FindTag(ctx context.Context, manager *tags.Manager, categoryName string, name string) (categoryID string, tagID string, err error) {
+	categories, err := manager.GetCategories(ctx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "list tag categories")
+	}
+	for _, category := range categories {
+		if category.Name == categoryName {
+			categoryID = category.ID
+			break
+		}
+	}
+	if categoryID == "" {
+		return "", "", nil
+	}
+
+	categoryTags, err := manager.GetTagsForCategory(ctx, categoryID)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "list the tags of %s", categoryName)
+	}
+	for _, tag := range categoryTags {
+		if tag.Name == name {
+			return categoryID, tag.ID, nil
+		}
+	}
+	return categoryID, "", nil
+}
+
+// CreateTag returns the ID of the tag with the given name in the category
+// with the given name, creating both when they do not exist.
+func CreateTag(ctx context.Context, manager *tags.Manager, categoryName string, name string) (string, error) {
+	categoryID, tagID, err := FindTag(ctx, manager, categoryName, name)
+	if err != nil || tagID != "" {
+		return tagID, err
+	}
+
+	if categoryID == "" {
+		categoryID, err = manager.CreateCategory(ctx, &tags.Category{
+			Name:        categoryName,
+			Description: tagDescription,
+			Cardinality: "SINGLE",
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "create tag category %s", categoryName)
+		}
+	}
+
+	tagID, err = manager.CreateTag(ctx, &tags.Tag{
+		Name:        name,
+		Description: tagDescription,
+		CategoryID:  categoryID,
+	})
+	return tagID, errors.Wrapf(err, "create tag %s", name)
+}
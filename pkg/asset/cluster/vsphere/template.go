@@ -0,0 +1,183 @@
+package vsphere
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vapi/vcenter"
+
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// pollInterval is the wait between two checks of the OVA transfer.
+var pollInterval = 10 * time.Second
+
+// ImportTemplate imports the RHCOS OVA at ovaURL as the template of the
+// cluster with the given infrastructure ID, unless the template already
+// exists.  The vCenter pulls the OVA into a content library, removed once
+// the template is deployed from it, and the template is tagged like the
+// other resources of the cluster.  The template is never powered on, since
+// Ignition only runs on the first boot of its clones.
+func ImportTemplate(ctx context.Context, client *Client, platform *vsphere.Platform, infraID string, ovaURL string) error {
+	tagManager := tags.NewManager(client.REST)
+	tagID, err := CreateTag(ctx, tagManager, TagCategoryName(infraID), infraID)
+	if err != nil {
+		return err
+	}
+
+	finder := find.NewFinder(client.SOAP.Client, false)
+	datacenter, err := finder.Datacenter(ctx, platform.Workspace.Datacenter)
+	if err != nil {
+		return errors.Wrapf(err, "find datacenter %s", platform.Workspace.Datacenter)
+	}
+	finder.SetDatacenter(datacenter)
+
+	name := TemplateName(infraID)
+	_, err = finder.VirtualMachine(ctx, name)
+	if err == nil {
+		logrus.Debugf("Using the existing RHCOS template %s", name)
+		return nil
+	}
+	if _, ok := err.(*find.NotFoundError); !ok {
+		return errors.Wrapf(err, "find virtual machine %s", name)
+	}
+
+	datastore, err := finder.Datastore(ctx, platform.Workspace.DefaultDatastore)
+	if err != nil {
+		return errors.Wrapf(err, "find datastore %s", platform.Workspace.DefaultDatastore)
+	}
+	resourcePool, err := finder.ResourcePool(ctx, ResourcePoolPath(platform))
+	if err != nil {
+		return errors.Wrapf(err, "find resource pool %s", ResourcePoolPath(platform))
+	}
+
+	logrus.Infof("Importing the RHCOS template %s from %s", name, ovaURL)
+	libraryManager := library.NewManager(client.REST)
+	libraryID, err := libraryManager.CreateLibrary(ctx, library.Library{
+		Name: name,
+		Type: "LOCAL",
+		Storage: []library.StorageBackings{
+			{Type: "DATASTORE", DatastoreID: datastore.Reference().Value},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "create content library %s", name)
+	}
+	defer func() {
+		if err := libraryManager.DeleteLibrary(context.Background(), &library.Library{ID: libraryID}); err != nil {
+			logrus.Warnf("Failed to delete the content library %s: %v", name, err)
+		}
+	}()
+
+	item, err := pullOVA(ctx, libraryManager, libraryID, name, ovaURL)
+	if err != nil {
+		return errors.Wrapf(err, "import %s", ovaURL)
+	}
+
+	template, err := vcenter.NewManager(client.REST).DeployLibraryItem(ctx, item, vcenter.Deploy{
+		DeploymentSpec: vcenter.DeploymentSpec{
+			Name:               name,
+			AcceptAllEULA:      true,
+			DefaultDatastoreID: datastore.Reference().Value,
+		},
+		Target: vcenter.Target{
+			ResourcePoolID: resourcePool.Reference().Value,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "deploy %s", name)
+	}
+	return errors.Wrapf(tagManager.AttachTag(ctx, tagID, template), "tag %s", name)
+}
+
+// pullOVA creates an item in the content library and has the vCenter pull
+// the OVA into it, returning the ID of the item once the transfer is done.
+func pullOVA(ctx context.Context, manager *library.Manager, libraryID string, name string, ovaURL string) (string, error) {
+	item, err := manager.CreateLibraryItem(ctx, library.Item{
+		LibraryID: libraryID,
+		Name:      name,
+		Type:      "ovf",
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "create library item")
+	}
+
+	session, err := manager.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: item})
+	if err != nil {
+		return "", errors.Wrap(err, "create update session")
+	}
+	_, err = manager.AddLibraryItemFile(ctx, session, library.UpdateFile{
+		Name:           path.Base(ovaURL),
+		SourceType:     "PULL",
+		SourceEndpoint: &library.TransferEndpoint{URI: ovaURL},
+	})
+	if err != nil {
+		manager.CancelLibraryItemUpdateSession(ctx, session)
+		return "", errors.Wrap(err, "add the OVA to the update session")
+	}
+
+	// the update session is completed once the files are transferred,
+	// since completing it earlier drops the transfers in progress
+	for {
+		files, err := updateSessionFiles(ctx, manager, session)
+		if err != nil {
+			return "", errors.Wrap(err, "get the status of the transfer")
+		}
+		ready := true
+		for _, file := range files {
+			switch file.Status {
+			case "READY":
+			case "ERROR":
+				manager.CancelLibraryItemUpdateSession(ctx, session)
+				message := "unknown error"
+				if file.ErrorMessage != nil {
+					message = file.ErrorMessage.Error()
+				}
+				return "", errors.Errorf("transfer %s: %s", file.Name, message)
+			default:
+				ready = false
+			}
+		}
+		if ready {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			manager.CancelLibraryItemUpdateSession(context.Background(), session)
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+
+	if err := manager.CompleteLibraryItemUpdateSession(ctx, session); err != nil {
+		return "", errors.Wrap(err, "complete the update session")
+	}
+	return item, nil
+}
+
+// updateSessionFiles returns the files of the update session with their
+// transfer status, which the library package does not list.
+func updateSessionFiles(ctx context.Context, manager *library.Manager, session string) ([]library.UpdateFile, error) {
+	u := manager.URL()
+	u.Path += "/content/library/item/updatesession/file"
+	u.RawQuery = url.Values{"update_session_id": {session}}.Encode()
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []library.UpdateFile
+	if err := manager.Do(ctx, request, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,177 @@
+package vsphere
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// ovf is a minimal OVF descriptor, the only file of the OVA which vcsim
+// reads.
+const ovf = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData" xmlns:vssd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_VirtualSystemSettingData">
+  <References/>
+  <VirtualSystem ovf:id="rhcos" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1">
+    <Info>RHCOS</Info>
+    <Name>rhcos</Name>
+    <VirtualHardwareSection>
+      <Info>Virtual hardware requirements</Info>
+      <System>
+        <vssd:ElementName>Virtual Hardware Family</vssd:ElementName>
+        <vssd:InstanceID>0</vssd:InstanceID>
+        <vssd:VirtualSystemType>vmx-13</vssd:VirtualSystemType>
+      </System>
+      <Item>
+        <rasd:AllocationUnits>hertz * 10^6</rasd:AllocationUnits>
+        <rasd:ElementName>2 virtual CPU(s)</rasd:ElementName>
+        <rasd:InstanceID>1</rasd:InstanceID>
+        <rasd:ResourceType>3</rasd:ResourceType>
+        <rasd:VirtualQuantity>2</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:AllocationUnits>byte * 2^20</rasd:AllocationUnits>
+        <rasd:ElementName>4096MB of memory</rasd:ElementName>
+        <rasd:InstanceID>2</rasd:InstanceID>
+        <rasd:ResourceType>4</rasd:ResourceType>
+        <rasd:VirtualQuantity>4096</rasd:VirtualQuantity>
+      </Item>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>
+`
+
+// ovaServer serves the RHCOS OVA at /rhcos-vmware.ova, and counts the
+// downloads.
+type ovaServer struct {
+	*httptest.Server
+
+	lock      sync.Mutex
+	downloads int
+}
+
+func newOVAServer(t *testing.T) *ovaServer {
+	var ova bytes.Buffer
+	writer := tar.NewWriter(&ova)
+	if err := writer.WriteHeader(&tar.Header{Name: "rhcos.ovf", Mode: 0644, Size: int64(len(ovf))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Write([]byte(ovf)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &ovaServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rhcos-vmware.ova", func(w http.ResponseWriter, r *http.Request) {
+		server.lock.Lock()
+		server.downloads++
+		server.lock.Unlock()
+		w.Write(ova.Bytes())
+	})
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// newVCenter returns a vcsim vCenter and a client logged in to it.
+func newVCenter(t *testing.T) (*simulator.Model, *simulator.Server, *Client) {
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	server := model.Service.NewServer()
+	model.Service.Handle(vapi.New(server.URL, nil))
+
+	password, _ := server.URL.User.Password()
+	client, err := NewClient(context.Background(), server.URL.String(), server.URL.User.Username(), password)
+	if err != nil {
+		server.Close()
+		model.Remove()
+		t.Fatal(err)
+	}
+	return model, server, client
+}
+
+func testPlatform() *vsphere.Platform {
+	return &vsphere.Platform{
+		Workspace: vsphere.Workspace{
+			Datacenter:       "DC0",
+			DefaultDatastore: "LocalDS_0",
+		},
+		Cluster: "DC0_C0",
+	}
+}
+
+func TestImportTemplate(t *testing.T) {
+	pollInterval = 0
+	model, server, client := newVCenter(t)
+	defer model.Remove()
+	defer server.Close()
+	defer client.Logout(context.Background())
+	ova := newOVAServer(t)
+	defer ova.Close()
+
+	ctx := context.Background()
+	err := ImportTemplate(ctx, client, testPlatform(), "mycluster-abcde", ova.URL+"/rhcos-vmware.ova")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	template, err := find.NewFinder(client.SOAP.Client, false).VirtualMachine(ctx, "/DC0/vm/mycluster-abcde-rhcos")
+	if !assert.NoError(t, err) {
+		return
+	}
+	manager := tags.NewManager(client.REST)
+	_, tagID, err := FindTag(ctx, manager, "openshift-mycluster-abcde", "mycluster-abcde")
+	if !assert.NoError(t, err) {
+		return
+	}
+	attached, err := manager.ListAttachedObjects(ctx, tagID)
+	assert.NoError(t, err)
+	assert.Len(t, attached, 1)
+	assert.Equal(t, template.Reference(), attached[0].Reference())
+	libraries, err := library.NewManager(client.REST).ListLibraries(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, libraries, "the content library is deleted")
+
+	// the template is only imported once
+	err = ImportTemplate(ctx, client, testPlatform(), "mycluster-abcde", ova.URL+"/rhcos-vmware.ova")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ova.downloads)
+}
+
+func TestImportTemplateTransferError(t *testing.T) {
+	pollInterval = 0
+	model, server, client := newVCenter(t)
+	defer model.Remove()
+	defer server.Close()
+	defer client.Logout(context.Background())
+	ova := newOVAServer(t)
+	defer ova.Close()
+
+	ctx := context.Background()
+	err := ImportTemplate(ctx, client, testPlatform(), "mycluster-abcde", ova.URL+"/missing.ova")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "import "+ova.URL+"/missing.ova: transfer missing.ova: ")
+	}
+
+	_, err = find.NewFinder(client.SOAP.Client, false).VirtualMachine(ctx, "/DC0/vm/mycluster-abcde-rhcos")
+	assert.IsType(t, &find.NotFoundError{}, err)
+	libraries, err := library.NewManager(client.REST).ListLibraries(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, libraries, "the content library is deleted")
+}
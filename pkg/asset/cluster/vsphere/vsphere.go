@@ -0,0 +1,111 @@
+// Package vsphere extracts vSphere metadata from install configurations and
+// imports the RHCOS template of installer-provisioned clusters.
+package vsphere
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// passwordEnv is the environment variable holding the password of the
+// vCenter user, which is also read by the Terraform vsphere provider.
+const passwordEnv = "VSPHERE_PASSWORD"
+
+// Metadata converts an install configuration to vSphere metadata.
+func Metadata(config *types.InstallConfig) *vsphere.Metadata {
+	platform := config.Platform.VSphere
+	metadata := &vsphere.Metadata{
+		VCenter:    platform.Workspace.Server,
+		Datacenter: platform.Workspace.Datacenter,
+		Folder:     platform.Workspace.Folder,
+	}
+	if vCenter := virtualCenter(platform); vCenter != nil {
+		metadata.Username = vCenter.Username
+	}
+	return metadata
+}
+
+// Password returns the password of the user connecting to the vCenter of
+// the workspace.
+func Password(config *types.InstallConfig) string {
+	if vCenter := virtualCenter(config.Platform.VSphere); vCenter != nil {
+		return vCenter.Password
+	}
+	return ""
+}
+
+// LoadPassword returns the password of the user of the metadata from the
+// VSPHERE_PASSWORD environment variable if it is set, and asks for it
+// otherwise.  The password is not kept in metadata.json, so it is loaded
+// this way to destroy the cluster.
+func LoadPassword(metadata *vsphere.Metadata) (string, error) {
+	if password, ok := os.LookupEnv(passwordEnv); ok {
+		return password, nil
+	}
+
+	var password string
+	err := survey.AskOne(&survey.Password{
+		Message: fmt.Sprintf("Password of %s on %s", metadata.Username, metadata.VCenter),
+		Help:    fmt.Sprintf("The password of the vCenter user, which can also be set with %s.", passwordEnv),
+	}, &password, survey.Required)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to ask for the vCenter password")
+	}
+	return password, nil
+}
+
+// virtualCenter returns the vCenter of the workspace, or nil if the
+// platform does not list it.
+func virtualCenter(platform *vsphere.Platform) *vsphere.VirtualCenter {
+	for i := range platform.VirtualCenters {
+		if platform.VirtualCenters[i].Name == platform.Workspace.Server {
+			return &platform.VirtualCenters[i]
+		}
+	}
+	return nil
+}
+
+// SetTerraformEnvironment exports the vCenter credentials in the
+// environment variables read by the Terraform vsphere provider, so they are
+// not written in the Terraform variables of the install directory.
+func SetTerraformEnvironment(metadata *vsphere.Metadata, password string) error {
+	for key, value := range map[string]string{
+		"VSPHERE_SERVER": metadata.VCenter,
+		"VSPHERE_USER":   metadata.Username,
+		passwordEnv:      password,
+	} {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TagCategoryName returns the name of the tag category of the cluster with
+// the given infrastructure ID.  The category holds a single tag, named
+// after the infrastructure ID, attached to the resources of the cluster.
+func TagCategoryName(infraID string) string {
+	return "openshift-" + infraID
+}
+
+// TemplateName returns the name of the RHCOS template from which the
+// machines of the cluster with the given infrastructure ID are cloned.
+func TemplateName(infraID string) string {
+	return infraID + "-rhcos"
+}
+
+// ResourcePoolPath returns the inventory path of the resource pool of the
+// machines: the workspace resource pool when it is set, and the root
+// resource pool of the vSphere cluster otherwise.
+func ResourcePoolPath(platform *vsphere.Platform) string {
+	if platform.Workspace.ResourcePoolPath != "" {
+		return platform.Workspace.ResourcePoolPath
+	}
+	return fmt.Sprintf("/%s/host/%s/Resources", platform.Workspace.Datacenter, platform.Cluster)
+}
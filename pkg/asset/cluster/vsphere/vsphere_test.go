@@ -0,0 +1,59 @@
+package vsphere
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestMetadataOmitsPassword(t *testing.T) {
+	config := &types.InstallConfig{
+		Platform: types.Platform{
+			VSphere: &vsphere.Platform{
+				VirtualCenters: []vsphere.VirtualCenter{
+					{Name: "other.example.com", Username: "other", Password: "other-secret"},
+					{Name: "vcenter.example.com", Username: "admin", Password: "secret"},
+				},
+				Workspace: vsphere.Workspace{
+					Server:     "vcenter.example.com",
+					Datacenter: "dc1",
+					Folder:     "mycluster",
+				},
+			},
+		},
+	}
+
+	metadata := Metadata(config)
+	assert.Equal(t, &vsphere.Metadata{
+		VCenter:    "vcenter.example.com",
+		Username:   "admin",
+		Datacenter: "dc1",
+		Folder:     "mycluster",
+	}, metadata)
+	data, err := json.Marshal(metadata)
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(data), "secret")
+	}
+	assert.Equal(t, "secret", Password(config))
+}
+
+func TestLoadPasswordFromEnv(t *testing.T) {
+	defer func(password string, ok bool) {
+		if ok {
+			os.Setenv(passwordEnv, password)
+		} else {
+			os.Unsetenv(passwordEnv)
+		}
+	}(os.LookupEnv(passwordEnv))
+	os.Setenv(passwordEnv, "secret")
+
+	password, err := LoadPassword(&vsphere.Metadata{VCenter: "vcenter.example.com", Username: "admin"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "secret", password)
+	}
+}
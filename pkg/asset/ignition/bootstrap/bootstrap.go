@@ -2,7 +2,6 @@ package bootstrap
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,10 +19,12 @@ import (
 	"github.com/openshift/installer/data"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/asset/ignition/fragments"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/kubeconfig"
 	"github.com/openshift/installer/pkg/asset/machines"
 	"github.com/openshift/installer/pkg/asset/manifests"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/types"
 )
@@ -53,6 +54,7 @@ var _ asset.WritableAsset = (*Bootstrap)(nil)
 // Dependencies returns the assets on which the Bootstrap asset depends.
 func (a *Bootstrap) Dependencies() []asset.Asset {
 	return []asset.Asset{
+		&fragments.Fragments{},
 		&installconfig.InstallConfig{},
 		&kubeconfig.AdminClient{},
 		&kubeconfig.Kubelet{},
@@ -60,6 +62,7 @@ func (a *Bootstrap) Dependencies() []asset.Asset {
 		&machines.Worker{},
 		&manifests.Manifests{},
 		&manifests.Openshift{},
+		&overlays.Overlays{},
 		&tls.AdminKubeConfigCABundle{},
 		&tls.AggregatorCA{},
 		&tls.AggregatorCABundle{},
@@ -132,12 +135,29 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 	}
 	a.addParentFiles(dependencies)
 
+	if len(installConfig.Config.ImageContentSources) > 0 {
+		a.Config.Storage.Files = append(a.Config.Storage.Files, ignition.FileFromBytes(registriesConfPath, "root", 0644, registriesConf(installConfig.Config.ImageContentSources)))
+	}
+	if installConfig.Config.AdditionalTrustBundle != "" {
+		// RHCOS runs update-ca-trust on boot, after Ignition writes the anchor.
+		a.Config.Storage.Files = append(a.Config.Storage.Files, ignition.FileFromString("/etc/pki/ca-trust/source/anchors/ca.crt", "root", 0644, installConfig.Config.AdditionalTrustBundle))
+	}
+	if proxy := installConfig.ProxyConfig(); proxy != nil {
+		a.Config.Storage.Files = append(a.Config.Storage.Files, ignition.ProxyEnvironmentFile(proxy))
+	}
+
 	a.Config.Passwd.Users = append(
 		a.Config.Passwd.Users,
 		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{igntypes.SSHAuthorizedKey(installConfig.Config.SSHKey)}},
 	)
 
-	data, err := json.Marshal(a.Config)
+	userFragments := &fragments.Fragments{}
+	dependencies.Get(userFragments)
+	if err := userFragments.Apply("bootstrap", a.Config); err != nil {
+		return err
+	}
+
+	data, err := ignition.Marshal(a.Config)
 	if err != nil {
 		return errors.Wrap(err, "failed to Marshal Ignition config")
 	}
@@ -365,6 +385,7 @@ func readFile(name string, reader io.Reader, templateData interface{}) (finalNam
 func (a *Bootstrap) addParentFiles(dependencies asset.Parents) {
 	// These files are all added with mode 0644, i.e. readable
 	// by all processes on the system.
+	var manifestFiles []*asset.File
 	for _, asset := range []asset.WritableAsset{
 		&manifests.Manifests{},
 		&manifests.Openshift{},
@@ -373,6 +394,13 @@ func (a *Bootstrap) addParentFiles(dependencies asset.Parents) {
 	} {
 		dependencies.Get(asset)
 		a.Config.Storage.Files = append(a.Config.Storage.Files, ignition.FilesFromAsset(rootDir, "root", 0644, asset)...)
+		manifestFiles = append(manifestFiles, asset.Files()...)
+	}
+
+	overlay := &overlays.Overlays{}
+	dependencies.Get(overlay)
+	for _, filename := range overlay.Unmatched(manifestFiles) {
+		logrus.Warnf("Overlay %s does not match any manifest", filename)
 	}
 
 	// These files are all added with mode 0600; use for secret keys and the like.
@@ -449,8 +477,8 @@ func (a *Bootstrap) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, err
 	}
 
-	config := &igntypes.Config{}
-	if err := json.Unmarshal(file.Data, config); err != nil {
+	config, err := ignition.Unmarshal(file.Data)
+	if err != nil {
 		return false, errors.Wrap(err, "failed to unmarshal")
 	}
 
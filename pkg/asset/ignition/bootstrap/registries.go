@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// registriesConfPath is the configuration of the registries used by
+// podman and CRI-O on the bootstrap machine.
+const registriesConfPath = "/etc/containers/registries.conf"
+
+// registriesConf renders a containers-registries.conf(5) file in the v2
+// format, which redirects the pulls by digest from each source to its
+// mirrors, and falls back to the source when no mirror has the image.
+func registriesConf(sources []types.ImageContentSource) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, `unqualified-search-registries = ["registry.access.redhat.com", "docker.io"]`)
+	for _, source := range sources {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "[[registry]]")
+		fmt.Fprintf(buf, "location = %q\n", source.Source)
+		fmt.Fprintln(buf, "mirror-by-digest-only = true")
+		for _, mirror := range source.Mirrors {
+			fmt.Fprintln(buf)
+			fmt.Fprintln(buf, "[[registry.mirror]]")
+			fmt.Fprintf(buf, "location = %q\n", mirror)
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestRegistriesConf(t *testing.T) {
+	sources := []types.ImageContentSource{
+		{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []string{"mirror.example.com:5000/ocp/release"},
+		},
+		{
+			Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+			Mirrors: []string{"mirror.example.com:5000/ocp/release", "backup.example.com/ocp/release"},
+		},
+	}
+	expected := `unqualified-search-registries = ["registry.access.redhat.com", "docker.io"]
+
+[[registry]]
+location = "quay.io/openshift-release-dev/ocp-release"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com:5000/ocp/release"
+
+[[registry]]
+location = "quay.io/openshift-release-dev/ocp-v4.0-art-dev"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "mirror.example.com:5000/ocp/release"
+
+[[registry.mirror]]
+location = "backup.example.com/ocp/release"
+`
+	assert.Equal(t, expected, string(registriesConf(sources)))
+}
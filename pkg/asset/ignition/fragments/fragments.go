@@ -0,0 +1,133 @@
+// Package fragments merges user-provided Ignition configs into the Ignition
+// configs generated by the installer.
+package fragments
+
+import (
+	"path"
+	"path/filepath"
+	"reflect"
+
+	ignv2_2 "github.com/coreos/ignition/config/v2_2"
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/coreos/ignition/config/validate/report"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	// fragmentsDir is the directory of the asset directory holding the
+	// fragments, in a subdirectory for each role.
+	fragmentsDir = "ignition"
+)
+
+var (
+	_ asset.PersistentAsset = (*Fragments)(nil)
+
+	// roles are the roles of the generated Ignition configs.
+	roles = []string{"bootstrap", "master", "worker"}
+)
+
+// Fragments is the asset holding the Ignition configs merged into the
+// bootstrap, master and worker Ignition configs generated by the
+// installer, which are read from the ignition/<role> directories of the
+// asset directory. Like the overlays, the fragments are kept in the state
+// file, so they are merged again whenever the Ignition configs are
+// regenerated, and they are left in the asset directory.
+type Fragments struct {
+	FileList []*asset.File
+}
+
+// Name returns a human friendly name for the asset.
+func (f *Fragments) Name() string {
+	return "Ignition Fragments"
+}
+
+// Dependencies returns all of the dependencies directly needed by the
+// Fragments asset.
+func (f *Fragments) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates an empty set of fragments. Fragments are only provided
+// by users.
+func (f *Fragments) Generate(dependencies asset.Parents) error {
+	f.FileList = []*asset.File{}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (f *Fragments) Files() []*asset.File {
+	return f.FileList
+}
+
+// Persistent marks the fragments as kept in the asset directory.
+func (f *Fragments) Persistent() {}
+
+// Load returns the fragments from disk.
+func (f *Fragments) Load(fetcher asset.FileFetcher) (bool, error) {
+	var fileList []*asset.File
+	for _, role := range roles {
+		files, err := fetcher.FetchByPattern(filepath.Join(fragmentsDir, role, "*.ign"))
+		if err != nil {
+			return false, err
+		}
+		fileList = append(fileList, files...)
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+
+	asset.SortFiles(fileList)
+	for _, file := range fileList {
+		_, report, err := parseFragment(file)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid Ignition fragment %s", file.Filename)
+		}
+		for _, entry := range report.Entries {
+			logrus.Warnf("Ignition fragment %s: %s", file.Filename, entry)
+		}
+	}
+	f.FileList = fileList
+	return true, nil
+}
+
+// Apply merges the fragments of the role into the config, in the order of
+// the names of the fragment files. The files, systemd units, users and
+// other lists of the fragments are appended to the ones of the config.
+func (f *Fragments) Apply(role string, config *igntypes.Config) error {
+	for _, file := range f.FileList {
+		if path.Base(path.Dir(filepath.ToSlash(file.Filename))) != role {
+			continue
+		}
+		fragment, _, err := parseFragment(file)
+		if err != nil {
+			return errors.Wrapf(err, "invalid Ignition fragment %s", file.Filename)
+		}
+		logrus.Debugf("Merging Ignition fragment %s into the %s Ignition config", file.Filename, role)
+
+		// Append takes the ignition.config of the new config, but the
+		// pointer configs must keep referencing the machine-config server.
+		ignitionConfig := config.Ignition.Config
+		*config = ignv2_2.Append(*config, *fragment)
+		config.Ignition.Config = ignitionConfig
+	}
+	return nil
+}
+
+// parseFragment parses and validates the Ignition config of the fragment,
+// translating it to the spec version used by the installer.
+func parseFragment(file *asset.File) (*igntypes.Config, report.Report, error) {
+	config, rpt, err := ignv2_2.Parse(file.Data)
+	if err != nil {
+		if rpt.IsFatal() {
+			return nil, rpt, errors.Errorf("%s: %s", err, rpt)
+		}
+		return nil, rpt, err
+	}
+	if !reflect.DeepEqual(config.Ignition.Config, igntypes.IgnitionConfig{}) {
+		return nil, rpt, errors.New("fragments cannot set ignition.config, they are merged into the generated configs")
+	}
+	return &config, rpt, nil
+}
@@ -0,0 +1,125 @@
+package fragments
+
+import (
+	"testing"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/mock"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         map[string][]*asset.File
+		expectedFound bool
+		expectedError string
+	}{
+		{
+			name: "no fragments",
+		},
+		{
+			name: "valid fragments",
+			files: map[string][]*asset.File{
+				"master": {
+					{Filename: "ignition/master/chrony.ign", Data: []byte(`{"ignition":{"version":"2.2.0"},"storage":{"files":[{"filesystem":"root","path":"/etc/chrony.conf","mode":420,"contents":{"source":"data:,server%20ntp.example.com"}}]}}`)},
+				},
+				"worker": {
+					{Filename: "ignition/worker/agent.ign", Data: []byte(`{"ignition":{"version":"2.1.0"},"systemd":{"units":[{"name":"agent.service","enabled":true,"contents":"[Service]\nExecStart=/usr/bin/agent\n[Install]\nWantedBy=multi-user.target\n"}]}}`)},
+				},
+			},
+			expectedFound: true,
+		},
+		{
+			name: "invalid JSON",
+			files: map[string][]*asset.File{
+				"bootstrap": {
+					{Filename: "ignition/bootstrap/a.ign", Data: []byte(`{"ignition":`)},
+				},
+			},
+			expectedError: `^invalid Ignition fragment ignition/bootstrap/a\.ign: config is not valid: error at line 1, column 12\n(?s:.*)unexpected end of JSON input$`,
+		},
+		{
+			name: "unsupported version",
+			files: map[string][]*asset.File{
+				"bootstrap": {
+					{Filename: "ignition/bootstrap/a.ign", Data: []byte(`{"ignition":{"version":"3.0.0"}}`)},
+				},
+			},
+			expectedError: `^invalid Ignition fragment ignition/bootstrap/a\.ign: unsupported config version$`,
+		},
+		{
+			name: "config reference",
+			files: map[string][]*asset.File{
+				"worker": {
+					{Filename: "ignition/worker/a.ign", Data: []byte(`{"ignition":{"version":"2.2.0","config":{"replace":{"source":"https://example.com/worker.ign"}}}}`)},
+				},
+			},
+			expectedError: `^invalid Ignition fragment ignition/worker/a\.ign: fragments cannot set ignition\.config, they are merged into the generated configs$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			for _, role := range roles {
+				fileFetcher.EXPECT().FetchByPattern("ignition/"+role+"/*.ign").Return(tc.files[role], nil)
+			}
+
+			f := &Fragments{}
+			found, err := f.Load(fileFetcher)
+			if tc.expectedError != "" {
+				if assert.Error(t, err) {
+					assert.Regexp(t, tc.expectedError, err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	f := &Fragments{
+		FileList: []*asset.File{
+			{Filename: "ignition/master/a.ign", Data: []byte(`{"ignition":{"version":"2.2.0"},"storage":{"files":[{"filesystem":"root","path":"/etc/chrony.conf","contents":{"source":"data:,server%20ntp.example.com"}}]}}`)},
+			{Filename: "ignition/master/b.ign", Data: []byte(`{"ignition":{"version":"2.2.0"},"passwd":{"users":[{"name":"monitor","sshAuthorizedKeys":["ssh-rsa AAAA"]}]}}`)},
+			{Filename: "ignition/worker/a.ign", Data: []byte(`{"ignition":{"version":"2.2.0"},"storage":{"files":[{"filesystem":"root","path":"/etc/worker","contents":{"source":"data:,worker"}}]}}`)},
+		},
+	}
+
+	pointer := func() *igntypes.Config {
+		return &igntypes.Config{
+			Ignition: igntypes.Ignition{
+				Version: igntypes.MaxVersion.String(),
+				Config: igntypes.IgnitionConfig{
+					Append: []igntypes.ConfigReference{{Source: "https://api.test-cluster.test-domain:22623/config/master"}},
+				},
+			},
+		}
+	}
+
+	config := pointer()
+	err := f.Apply("master", config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, pointer().Ignition, config.Ignition, "the pointer config is kept")
+	if assert.Len(t, config.Storage.Files, 1) {
+		assert.Equal(t, "/etc/chrony.conf", config.Storage.Files[0].Path)
+	}
+	if assert.Len(t, config.Passwd.Users, 1) {
+		assert.Equal(t, "monitor", config.Passwd.Users[0].Name)
+	}
+
+	config = pointer()
+	err = f.Apply("bootstrap", config)
+	assert.NoError(t, err)
+	assert.Equal(t, pointer(), config, "no fragments for the role")
+}
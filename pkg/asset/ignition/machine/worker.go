@@ -1,13 +1,14 @@
 package machine
 
 import (
-	"encoding/json"
 	"os"
 
 	igntypes "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/pkg/errors"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/asset/ignition/fragments"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/tls"
 )
@@ -27,6 +28,7 @@ var _ asset.WritableAsset = (*Worker)(nil)
 // Dependencies returns the assets on which the Worker asset depends.
 func (a *Worker) Dependencies() []asset.Asset {
 	return []asset.Asset{
+		&fragments.Fragments{},
 		&installconfig.InstallConfig{},
 		&tls.RootCA{},
 	}
@@ -36,11 +38,15 @@ func (a *Worker) Dependencies() []asset.Asset {
 func (a *Worker) Generate(dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	rootCA := &tls.RootCA{}
-	dependencies.Get(installConfig, rootCA)
+	userFragments := &fragments.Fragments{}
+	dependencies.Get(installConfig, rootCA, userFragments)
 
 	a.Config = pointerIgnitionConfig(installConfig.Config, rootCA.Cert(), "worker")
+	if err := userFragments.Apply("worker", a.Config); err != nil {
+		return err
+	}
 
-	data, err := json.Marshal(a.Config)
+	data, err := ignition.Marshal(a.Config)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal Ignition config")
 	}
@@ -75,8 +81,8 @@ func (a *Worker) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, err
 	}
 
-	config := &igntypes.Config{}
-	if err := json.Unmarshal(file.Data, config); err != nil {
+	config, err := ignition.Unmarshal(file.Data)
+	if err != nil {
 		return false, errors.Wrap(err, "failed to unmarshal")
 	}
 
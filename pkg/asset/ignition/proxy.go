@@ -0,0 +1,34 @@
+package ignition
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ignition "github.com/coreos/ignition/config/v2_2/types"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ProxyEnvironmentFile creates the ignition-config file of a systemd
+// manager drop-in, which sets the proxy environment variables of all the
+// units of the machine.
+func ProxyEnvironmentFile(proxy *types.Proxy) ignition.File {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "[Manager]")
+	for _, env := range []struct {
+		name  string
+		value string
+	}{
+		{name: "HTTP_PROXY", value: proxy.HTTPProxy},
+		{name: "HTTPS_PROXY", value: proxy.HTTPSProxy},
+		{name: "NO_PROXY", value: proxy.NoProxy},
+	} {
+		if env.value == "" {
+			continue
+		}
+		// curl ignores HTTP_PROXY, so the lower-case variables are set too
+		fmt.Fprintf(buf, "DefaultEnvironment=%q %q\n", env.name+"="+env.value, strings.ToLower(env.name)+"="+env.value)
+	}
+	return FileFromBytes("/etc/systemd/system.conf.d/10-default-env.conf", "root", 0644, buf.Bytes())
+}
@@ -0,0 +1,71 @@
+package ignition
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	ignition "github.com/coreos/ignition/config/v2_2/types"
+	ignitionv3 "github.com/coreos/ignition/v2/config/v3_0/types"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset/ignition/spec3"
+)
+
+// specEnv is the environment variable selecting the Ignition spec of the
+// generated configs when Spec is empty.
+const specEnv = "OPENSHIFT_INSTALL_IGNITION_SPEC"
+
+// Spec is the Ignition spec of the generated configs, "2" (the default) or
+// "3" for the images whose Ignition only consumes spec 3.  It is set by the
+// --ignition-spec flag and, when empty, read from
+// OPENSHIFT_INSTALL_IGNITION_SPEC.
+var Spec string
+
+// Marshal returns the JSON of the config, translated to Ignition spec 3.0
+// when the selected spec is 3. The installer builds all its configs with
+// the spec 2.2 types, and only translates them on output.
+func Marshal(config *ignition.Config) ([]byte, error) {
+	spec, source := Spec, "--ignition-spec"
+	if spec == "" {
+		spec, source = os.Getenv(specEnv), specEnv
+	}
+	switch spec {
+	case "", "2":
+		return json.Marshal(config)
+	case "3":
+		translated, err := spec3.FromSpec2(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to translate the Ignition config to spec 3")
+		}
+		return json.Marshal(translated)
+	default:
+		return nil, errors.Errorf("unsupported Ignition spec %q in %s, must be 2 or 3", spec, source)
+	}
+}
+
+// Unmarshal parses a config written by Marshal, of either spec.
+func Unmarshal(data []byte) (*ignition.Config, error) {
+	var versioned struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(data, &versioned); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(versioned.Ignition.Version, "3.") {
+		config := &ignition.Config{}
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
+	config := &ignitionv3.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return spec3.ToSpec2(config)
+}
@@ -0,0 +1,331 @@
+// Package spec3 translates the Ignition configs generated by the installer,
+// built with the spec 2.2 types, to and from Ignition spec 3.0.
+package spec3
+
+import (
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/coreos/ignition/v2/config/v3_0/types"
+	"github.com/pkg/errors"
+)
+
+// FromSpec2 translates the spec 2.2 config to spec 3.0. It fails on the
+// parts of the config which have no spec 3.0 equivalent, like networkd
+// units, or which the installer does not generate, like disks and nodes
+// outside of the root filesystem.
+func FromSpec2(old *igntypes.Config) (*types.Config, error) {
+	if len(old.Networkd.Units) > 0 {
+		return nil, errors.New("networkd units are not supported by Ignition spec 3")
+	}
+	if len(old.Storage.Disks) > 0 || len(old.Storage.Raid) > 0 || len(old.Storage.Filesystems) > 0 {
+		return nil, errors.New("disks, RAID arrays and filesystems cannot be translated to Ignition spec 3")
+	}
+
+	config := &types.Config{
+		Ignition: types.Ignition{
+			Version: types.MaxVersion.String(),
+			Timeouts: types.Timeouts{
+				HTTPResponseHeaders: old.Ignition.Timeouts.HTTPResponseHeaders,
+				HTTPTotal:           old.Ignition.Timeouts.HTTPTotal,
+			},
+		},
+	}
+	for _, ref := range old.Ignition.Config.Append {
+		config.Ignition.Config.Merge = append(config.Ignition.Config.Merge, types.ConfigReference{
+			Source:       strPtr(ref.Source),
+			Verification: types.Verification{Hash: ref.Verification.Hash},
+		})
+	}
+	if ref := old.Ignition.Config.Replace; ref != nil {
+		config.Ignition.Config.Replace = types.ConfigReference{
+			Source:       strPtr(ref.Source),
+			Verification: types.Verification{Hash: ref.Verification.Hash},
+		}
+	}
+	for _, ca := range old.Ignition.Security.TLS.CertificateAuthorities {
+		config.Ignition.Security.TLS.CertificateAuthorities = append(config.Ignition.Security.TLS.CertificateAuthorities, types.CaReference{
+			Source:       ca.Source,
+			Verification: types.Verification{Hash: ca.Verification.Hash},
+		})
+	}
+
+	for _, group := range old.Passwd.Groups {
+		config.Passwd.Groups = append(config.Passwd.Groups, types.PasswdGroup{
+			Gid:          group.Gid,
+			Name:         group.Name,
+			PasswordHash: strPtr(group.PasswordHash),
+			System:       boolPtr(group.System),
+		})
+	}
+	for _, user := range old.Passwd.Users {
+		if user.Create != nil {
+			return nil, errors.Errorf("user %s: passwd.users.create is not supported by Ignition spec 3", user.Name)
+		}
+		newUser := types.PasswdUser{
+			Gecos:        strPtr(user.Gecos),
+			HomeDir:      strPtr(user.HomeDir),
+			Name:         user.Name,
+			NoCreateHome: boolPtr(user.NoCreateHome),
+			NoLogInit:    boolPtr(user.NoLogInit),
+			NoUserGroup:  boolPtr(user.NoUserGroup),
+			PasswordHash: user.PasswordHash,
+			PrimaryGroup: strPtr(user.PrimaryGroup),
+			Shell:        strPtr(user.Shell),
+			System:       boolPtr(user.System),
+			UID:          user.UID,
+		}
+		for _, group := range user.Groups {
+			newUser.Groups = append(newUser.Groups, types.Group(group))
+		}
+		for _, key := range user.SSHAuthorizedKeys {
+			newUser.SSHAuthorizedKeys = append(newUser.SSHAuthorizedKeys, types.SSHAuthorizedKey(key))
+		}
+		config.Passwd.Users = append(config.Passwd.Users, newUser)
+	}
+
+	paths := map[string]bool{}
+	nodeFromSpec2 := func(node igntypes.Node) (types.Node, error) {
+		if node.Filesystem != "root" {
+			return types.Node{}, errors.Errorf("%s: only the nodes of the root filesystem can be translated to Ignition spec 3", node.Path)
+		}
+		if paths[node.Path] {
+			return types.Node{}, errors.Errorf("%s: Ignition spec 3 does not allow several nodes with the same path", node.Path)
+		}
+		paths[node.Path] = true
+		newNode := types.Node{
+			Overwrite: node.Overwrite,
+			Path:      node.Path,
+		}
+		if node.User != nil {
+			newNode.User = types.NodeUser{ID: node.User.ID, Name: strPtr(node.User.Name)}
+		}
+		if node.Group != nil {
+			newNode.Group = types.NodeGroup{ID: node.Group.ID, Name: strPtr(node.Group.Name)}
+		}
+		return newNode, nil
+	}
+	for _, file := range old.Storage.Files {
+		node, err := nodeFromSpec2(file.Node)
+		if err != nil {
+			return nil, err
+		}
+		contents := types.FileContents{
+			Compression:  strPtr(file.Contents.Compression),
+			Source:       strPtr(file.Contents.Source),
+			Verification: types.Verification{Hash: file.Contents.Verification.Hash},
+		}
+		newFile := types.File{Node: node, FileEmbedded1: types.FileEmbedded1{Mode: file.Mode}}
+		if file.Append {
+			newFile.Append = []types.FileContents{contents}
+		} else {
+			newFile.Contents = contents
+			// spec 2 overwrites files by default, spec 3 does not
+			if newFile.Overwrite == nil && contents.Source != nil {
+				newFile.Overwrite = boolPtr(true)
+			}
+		}
+		config.Storage.Files = append(config.Storage.Files, newFile)
+	}
+	for _, directory := range old.Storage.Directories {
+		node, err := nodeFromSpec2(directory.Node)
+		if err != nil {
+			return nil, err
+		}
+		config.Storage.Directories = append(config.Storage.Directories, types.Directory{
+			Node:               node,
+			DirectoryEmbedded1: types.DirectoryEmbedded1{Mode: directory.Mode},
+		})
+	}
+	for _, link := range old.Storage.Links {
+		node, err := nodeFromSpec2(link.Node)
+		if err != nil {
+			return nil, err
+		}
+		config.Storage.Links = append(config.Storage.Links, types.Link{
+			Node:          node,
+			LinkEmbedded1: types.LinkEmbedded1{Hard: boolPtr(link.Hard), Target: link.Target},
+		})
+	}
+
+	for _, unit := range old.Systemd.Units {
+		newUnit := types.Unit{
+			Contents: strPtr(unit.Contents),
+			Enabled:  unit.Enabled,
+			Mask:     boolPtr(unit.Mask),
+			Name:     unit.Name,
+		}
+		if unit.Enable && newUnit.Enabled == nil {
+			newUnit.Enabled = boolPtr(true)
+		}
+		for _, dropin := range unit.Dropins {
+			newUnit.Dropins = append(newUnit.Dropins, types.Dropin{
+				Contents: strPtr(dropin.Contents),
+				Name:     dropin.Name,
+			})
+		}
+		config.Systemd.Units = append(config.Systemd.Units, newUnit)
+	}
+
+	return config, nil
+}
+
+// ToSpec2 translates the spec 3.0 config back to spec 2.2. It is the
+// inverse of FromSpec2 for the configs generated by the installer.
+func ToSpec2(config *types.Config) (*igntypes.Config, error) {
+	old := &igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+			Timeouts: igntypes.Timeouts{
+				HTTPResponseHeaders: config.Ignition.Timeouts.HTTPResponseHeaders,
+				HTTPTotal:           config.Ignition.Timeouts.HTTPTotal,
+			},
+		},
+	}
+	for _, ref := range config.Ignition.Config.Merge {
+		old.Ignition.Config.Append = append(old.Ignition.Config.Append, igntypes.ConfigReference{
+			Source:       str(ref.Source),
+			Verification: igntypes.Verification{Hash: ref.Verification.Hash},
+		})
+	}
+	if ref := config.Ignition.Config.Replace; ref.Source != nil {
+		old.Ignition.Config.Replace = &igntypes.ConfigReference{
+			Source:       *ref.Source,
+			Verification: igntypes.Verification{Hash: ref.Verification.Hash},
+		}
+	}
+	for _, ca := range config.Ignition.Security.TLS.CertificateAuthorities {
+		old.Ignition.Security.TLS.CertificateAuthorities = append(old.Ignition.Security.TLS.CertificateAuthorities, igntypes.CaReference{
+			Source:       ca.Source,
+			Verification: igntypes.Verification{Hash: ca.Verification.Hash},
+		})
+	}
+
+	for _, group := range config.Passwd.Groups {
+		old.Passwd.Groups = append(old.Passwd.Groups, igntypes.PasswdGroup{
+			Gid:          group.Gid,
+			Name:         group.Name,
+			PasswordHash: str(group.PasswordHash),
+			System:       boolean(group.System),
+		})
+	}
+	for _, user := range config.Passwd.Users {
+		oldUser := igntypes.PasswdUser{
+			Gecos:        str(user.Gecos),
+			HomeDir:      str(user.HomeDir),
+			Name:         user.Name,
+			NoCreateHome: boolean(user.NoCreateHome),
+			NoLogInit:    boolean(user.NoLogInit),
+			NoUserGroup:  boolean(user.NoUserGroup),
+			PasswordHash: user.PasswordHash,
+			PrimaryGroup: str(user.PrimaryGroup),
+			Shell:        str(user.Shell),
+			System:       boolean(user.System),
+			UID:          user.UID,
+		}
+		for _, group := range user.Groups {
+			oldUser.Groups = append(oldUser.Groups, igntypes.Group(group))
+		}
+		for _, key := range user.SSHAuthorizedKeys {
+			oldUser.SSHAuthorizedKeys = append(oldUser.SSHAuthorizedKeys, igntypes.SSHAuthorizedKey(key))
+		}
+		old.Passwd.Users = append(old.Passwd.Users, oldUser)
+	}
+
+	nodeToSpec2 := func(node types.Node) igntypes.Node {
+		oldNode := igntypes.Node{
+			Filesystem: "root",
+			Overwrite:  node.Overwrite,
+			Path:       node.Path,
+		}
+		if node.User != (types.NodeUser{}) {
+			oldNode.User = &igntypes.NodeUser{ID: node.User.ID, Name: str(node.User.Name)}
+		}
+		if node.Group != (types.NodeGroup{}) {
+			oldNode.Group = &igntypes.NodeGroup{ID: node.Group.ID, Name: str(node.Group.Name)}
+		}
+		return oldNode
+	}
+	for _, file := range config.Storage.Files {
+		oldFile := igntypes.File{
+			Node:          nodeToSpec2(file.Node),
+			FileEmbedded1: igntypes.FileEmbedded1{Mode: file.Mode},
+		}
+		contents := file.Contents
+		switch {
+		case len(file.Append) == 0:
+			if oldFile.Overwrite != nil && *oldFile.Overwrite && contents.Source != nil {
+				// the default of spec 2
+				oldFile.Overwrite = nil
+			}
+		case len(file.Append) == 1 && contents == (types.FileContents{}):
+			oldFile.Append = true
+			contents = file.Append[0]
+		default:
+			return nil, errors.Errorf("%s: spec 2 can only append a single content to a file", file.Path)
+		}
+		oldFile.Contents = igntypes.FileContents{
+			Compression:  str(contents.Compression),
+			Source:       str(contents.Source),
+			Verification: igntypes.Verification{Hash: contents.Verification.Hash},
+		}
+		old.Storage.Files = append(old.Storage.Files, oldFile)
+	}
+	for _, directory := range config.Storage.Directories {
+		old.Storage.Directories = append(old.Storage.Directories, igntypes.Directory{
+			Node:               nodeToSpec2(directory.Node),
+			DirectoryEmbedded1: igntypes.DirectoryEmbedded1{Mode: directory.Mode},
+		})
+	}
+	for _, link := range config.Storage.Links {
+		old.Storage.Links = append(old.Storage.Links, igntypes.Link{
+			Node:          nodeToSpec2(link.Node),
+			LinkEmbedded1: igntypes.LinkEmbedded1{Hard: boolean(link.Hard), Target: link.Target},
+		})
+	}
+
+	for _, unit := range config.Systemd.Units {
+		oldUnit := igntypes.Unit{
+			Contents: str(unit.Contents),
+			Enabled:  unit.Enabled,
+			Mask:     boolean(unit.Mask),
+			Name:     unit.Name,
+		}
+		for _, dropin := range unit.Dropins {
+			oldUnit.Dropins = append(oldUnit.Dropins, igntypes.SystemdDropin{
+				Contents: str(dropin.Contents),
+				Name:     dropin.Name,
+			})
+		}
+		old.Systemd.Units = append(old.Systemd.Units, oldUnit)
+	}
+
+	return old, nil
+}
+
+// strPtr returns a pointer to the string, or nil for an empty string, the
+// unset value of the spec 2 strings.
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// boolPtr returns a pointer to the boolean, or nil for false, the unset
+// value of the spec 2 booleans.
+func boolPtr(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func boolean(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,205 @@
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func spec2Config() *igntypes.Config {
+	mode := 0644
+	enabled := true
+	return &igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+			Config: igntypes.IgnitionConfig{
+				Append: []igntypes.ConfigReference{{Source: "https://api.test-cluster.test-domain:22623/config/master"}},
+			},
+			Security: igntypes.Security{
+				TLS: igntypes.TLS{
+					CertificateAuthorities: []igntypes.CaReference{{Source: "data:text/plain;charset=utf-8;base64,Y2E="}},
+				},
+			},
+		},
+		Passwd: igntypes.Passwd{
+			Users: []igntypes.PasswdUser{{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{"ssh-rsa AAAA"}}},
+		},
+		Storage: igntypes.Storage{
+			Files: []igntypes.File{
+				{
+					Node: igntypes.Node{Filesystem: "root", Path: "/etc/motd", User: &igntypes.NodeUser{Name: "root"}},
+					FileEmbedded1: igntypes.FileEmbedded1{
+						Append:   true,
+						Contents: igntypes.FileContents{Source: "data:,hello"},
+						Mode:     &mode,
+					},
+				},
+				{
+					Node: igntypes.Node{Filesystem: "root", Path: "/etc/chrony.conf", User: &igntypes.NodeUser{Name: "root"}},
+					FileEmbedded1: igntypes.FileEmbedded1{
+						Contents: igntypes.FileContents{Source: "data:,server%20ntp.example.com"},
+						Mode:     &mode,
+					},
+				},
+			},
+		},
+		Systemd: igntypes.Systemd{
+			Units: []igntypes.Unit{
+				{Name: "kubelet.service", Contents: "[Service]\n", Enabled: &enabled},
+				{Name: "systemd-journal-gatewayd.service", Dropins: []igntypes.SystemdDropin{{Name: "certs.conf", Contents: "[Service]\n"}}},
+			},
+		},
+	}
+}
+
+func TestFromSpec2(t *testing.T) {
+	expected := `{
+  "ignition": {
+    "config": {
+      "merge": [
+        {
+          "source": "https://api.test-cluster.test-domain:22623/config/master",
+          "verification": {}
+        }
+      ],
+      "replace": {
+        "source": null,
+        "verification": {}
+      }
+    },
+    "security": {
+      "tls": {
+        "certificateAuthorities": [
+          {
+            "source": "data:text/plain;charset=utf-8;base64,Y2E=",
+            "verification": {}
+          }
+        ]
+      }
+    },
+    "timeouts": {},
+    "version": "3.0.0"
+  },
+  "passwd": {
+    "users": [
+      {
+        "name": "core",
+        "sshAuthorizedKeys": [
+          "ssh-rsa AAAA"
+        ]
+      }
+    ]
+  },
+  "storage": {
+    "files": [
+      {
+        "group": {},
+        "path": "/etc/motd",
+        "user": {
+          "name": "root"
+        },
+        "append": [
+          {
+            "source": "data:,hello",
+            "verification": {}
+          }
+        ],
+        "contents": {
+          "verification": {}
+        },
+        "mode": 420
+      },
+      {
+        "group": {},
+        "overwrite": true,
+        "path": "/etc/chrony.conf",
+        "user": {
+          "name": "root"
+        },
+        "contents": {
+          "source": "data:,server%20ntp.example.com",
+          "verification": {}
+        },
+        "mode": 420
+      }
+    ]
+  },
+  "systemd": {
+    "units": [
+      {
+        "contents": "[Service]\n",
+        "enabled": true,
+        "name": "kubelet.service"
+      },
+      {
+        "dropins": [
+          {
+            "contents": "[Service]\n",
+            "name": "certs.conf"
+          }
+        ],
+        "name": "systemd-journal-gatewayd.service"
+      }
+    ]
+  }
+}`
+	config, err := FromSpec2(spec2Config())
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, string(data))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	config, err := FromSpec2(spec2Config())
+	if !assert.NoError(t, err) {
+		return
+	}
+	old, err := ToSpec2(config)
+	if assert.NoError(t, err) {
+		assert.Equal(t, spec2Config(), old)
+	}
+}
+
+func TestFromSpec2Unsupported(t *testing.T) {
+	cases := []struct {
+		name          string
+		modify        func(*igntypes.Config)
+		expectedError string
+	}{
+		{
+			name: "networkd units",
+			modify: func(c *igntypes.Config) {
+				c.Networkd.Units = []igntypes.Networkdunit{{Name: "eth0.network"}}
+			},
+			expectedError: "networkd units are not supported by Ignition spec 3",
+		},
+		{
+			name: "other filesystem",
+			modify: func(c *igntypes.Config) {
+				c.Storage.Files[1].Filesystem = "var"
+			},
+			expectedError: "/etc/chrony.conf: only the nodes of the root filesystem can be translated to Ignition spec 3",
+		},
+		{
+			name: "duplicate path",
+			modify: func(c *igntypes.Config) {
+				c.Storage.Files[1].Path = "/etc/motd"
+			},
+			expectedError: "/etc/motd: Ignition spec 3 does not allow several nodes with the same path",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := spec2Config()
+			tc.modify(config)
+			_, err := FromSpec2(config)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
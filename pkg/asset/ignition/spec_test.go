@@ -0,0 +1,74 @@
+package ignition
+
+import (
+	"os"
+	"testing"
+
+	ignition "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestMarshal(t *testing.T) {
+	defer os.Unsetenv(specEnv)
+	defer func() { Spec = "" }()
+
+	enabled := true
+	config := &ignition.Config{
+		Ignition: ignition.Ignition{
+			Version: ignition.MaxVersion.String(),
+			Config: ignition.IgnitionConfig{
+				Append: []ignition.ConfigReference{{Source: "https://api.test-cluster.test-domain:22623/config/worker"}},
+			},
+		},
+		Passwd: ignition.Passwd{
+			Users: []ignition.PasswdUser{{Name: "core", SSHAuthorizedKeys: []ignition.SSHAuthorizedKey{"ssh-rsa AAAA"}}},
+		},
+		Storage: ignition.Storage{
+			Files: []ignition.File{
+				FileFromString("/etc/motd", "root", 0644, "hello"),
+				ProxyEnvironmentFile(&types.Proxy{HTTPProxy: "http://proxy.example.com"}),
+			},
+		},
+		Systemd: ignition.Systemd{
+			Units: []ignition.Unit{{Name: "kubelet.service", Contents: "[Service]\n", Enabled: &enabled}},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		flag            string
+		env             string
+		expectedVersion string
+		expectedError   string
+	}{
+		{name: "default", expectedVersion: `"version":"2.2.0"`},
+		{name: "env 2", env: "2", expectedVersion: `"version":"2.2.0"`},
+		{name: "env 3", env: "3", expectedVersion: `"version":"3.0.0"`},
+		{name: "env 4", env: "4", expectedError: `unsupported Ignition spec "4" in OPENSHIFT_INSTALL_IGNITION_SPEC, must be 2 or 3`},
+		{name: "flag 3", flag: "3", expectedVersion: `"version":"3.0.0"`},
+		{name: "flag over env", flag: "2", env: "3", expectedVersion: `"version":"2.2.0"`},
+		{name: "flag 4", flag: "4", expectedError: `unsupported Ignition spec "4" in --ignition-spec, must be 2 or 3`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			Spec = tc.flag
+			os.Setenv(specEnv, tc.env)
+			data, err := Marshal(config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Contains(t, string(data), tc.expectedVersion)
+
+			parsed, err := Unmarshal(data)
+			if assert.NoError(t, err) {
+				assert.Equal(t, config, parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package azure collects Azure-specific configuration.
+package azure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/azure/validation"
+)
+
+// Platform collects Azure-specific configuration.
+func Platform() (*azure.Platform, error) {
+	longRegions := make([]string, 0, len(validation.Regions))
+	shortRegions := make([]string, 0, len(validation.Regions))
+	for id, location := range validation.Regions {
+		longRegions = append(longRegions, fmt.Sprintf("%s (%s)", id, location))
+		shortRegions = append(shortRegions, id)
+	}
+	regionTransform := survey.TransformString(func(s string) string {
+		return strings.SplitN(s, " ", 2)[0]
+	})
+
+	defaultRegion := "eastus"
+	_, ok := validation.Regions[defaultRegion]
+	if !ok {
+		panic(fmt.Sprintf("installer bug: invalid default Azure region %q", defaultRegion))
+	}
+
+	_, err := GetCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(longRegions)
+	sort.Strings(shortRegions)
+
+	var region string
+	err = survey.Ask([]*survey.Question{
+		{
+			Prompt: &survey.Select{
+				Message: "Region",
+				Help:    "The Azure region to be used for installation.",
+				Default: fmt.Sprintf("%s (%s)", defaultRegion, validation.Regions[defaultRegion]),
+				Options: longRegions,
+			},
+			Validate: survey.ComposeValidators(survey.Required, func(ans interface{}) error {
+				choice := regionTransform(ans).(string)
+				i := sort.SearchStrings(shortRegions, choice)
+				if i == len(shortRegions) || shortRegions[i] != choice {
+					return errors.Errorf("invalid region %q", choice)
+				}
+				return nil
+			}),
+			Transform: regionTransform,
+		},
+	}, &region)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceGroup string
+	err = survey.Ask([]*survey.Question{
+		{
+			Prompt: &survey.Input{
+				Message: "Base Domain Resource Group",
+				Help:    "The resource group holding the public DNS zone of the base domain.  The records of the cluster are created in this zone.",
+			},
+			Validate: survey.Required,
+		},
+	}, &resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azure.Platform{
+		Region:                      region,
+		BaseDomainResourceGroupName: resourceGroup,
+	}, nil
+}
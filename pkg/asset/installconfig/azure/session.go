@@ -0,0 +1,150 @@
+package azure
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// Credentials are the credentials of the service principal the installer
+// authenticates as.
+type Credentials struct {
+	SubscriptionID string `json:"subscriptionId"`
+	ClientID       string `json:"clientId"`
+	ClientSecret   string `json:"clientSecret"`
+	TenantID       string `json:"tenantId"`
+}
+
+// credentialsPath returns the path of the file holding the credentials.
+func credentialsPath() string {
+	// FIXME: Use os.UserHomeDir() once we bump to Go 1.12
+	return filepath.Join(os.Getenv("HOME"), ".azure", "osServicePrincipal.json")
+}
+
+// LoadCredentials loads the credentials from the AZURE_SUBSCRIPTION_ID,
+// AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment
+// variables if they are set, and from ~/.azure/osServicePrincipal.json
+// otherwise.
+func LoadCredentials() (*Credentials, error) {
+	credentials := &Credentials{
+		SubscriptionID: os.Getenv("AZURE_SUBSCRIPTION_ID"),
+		TenantID:       os.Getenv("AZURE_TENANT_ID"),
+		ClientID:       os.Getenv("AZURE_CLIENT_ID"),
+		ClientSecret:   os.Getenv("AZURE_CLIENT_SECRET"),
+	}
+	if *credentials != (Credentials{}) {
+		return credentials, credentials.validate()
+	}
+
+	path := credentialsPath()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the Azure credentials")
+	}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return credentials, errors.Wrap(credentials.validate(), path)
+}
+
+// GetCredentials returns the credentials like LoadCredentials and, if no
+// credentials are found, asks for them and stores them on disk in
+// ~/.azure/osServicePrincipal.json.
+func GetCredentials() (*Credentials, error) {
+	credentials, err := LoadCredentials()
+	if err == nil {
+		return credentials, nil
+	}
+	if !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+
+	credentials = &Credentials{}
+	err = survey.Ask([]*survey.Question{
+		{
+			Name: "SubscriptionID",
+			Prompt: &survey.Input{
+				Message: "Azure Subscription ID",
+				Help:    "The Azure subscription ID to use for installation.",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "TenantID",
+			Prompt: &survey.Input{
+				Message: "Azure Tenant ID",
+				Help:    "The Azure Active Directory tenant ID of the service principal.",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "ClientID",
+			Prompt: &survey.Input{
+				Message: "Azure Service Principal Client ID",
+				Help:    "The application ID of the service principal to use for installation.\nhttps://docs.microsoft.com/en-us/azure/active-directory/develop/howto-create-service-principal-portal",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "ClientSecret",
+			Prompt: &survey.Password{
+				Message: "Azure Service Principal Client Secret",
+				Help:    "The secret of the service principal (this is not your password).",
+			},
+			Validate: survey.Required,
+		},
+	}, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	path := credentialsPath()
+	logrus.Infof("Writing Azure credentials to %q", path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// SetTerraformEnvironment exports the credentials in the environment
+// variables read by the Terraform azurerm provider, so they are not
+// written in the Terraform variables of the install directory.
+func (c *Credentials) SetTerraformEnvironment() error {
+	for key, value := range map[string]string{
+		"ARM_SUBSCRIPTION_ID": c.SubscriptionID,
+		"ARM_TENANT_ID":       c.TenantID,
+		"ARM_CLIENT_ID":       c.ClientID,
+		"ARM_CLIENT_SECRET":   c.ClientSecret,
+	} {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Credentials) validate() error {
+	switch {
+	case c.SubscriptionID == "":
+		return errors.New("the subscription ID is missing from the Azure credentials")
+	case c.TenantID == "":
+		return errors.New("the tenant ID is missing from the Azure credentials")
+	case c.ClientID == "":
+		return errors.New("the client ID is missing from the Azure credentials")
+	case c.ClientSecret == "":
+		return errors.New("the client secret is missing from the Azure credentials")
+	}
+	return nil
+}
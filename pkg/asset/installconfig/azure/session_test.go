@@ -0,0 +1,122 @@
+package azure
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var credentialsEnv = []string{
+	"AZURE_SUBSCRIPTION_ID",
+	"AZURE_TENANT_ID",
+	"AZURE_CLIENT_ID",
+	"AZURE_CLIENT_SECRET",
+	"ARM_SUBSCRIPTION_ID",
+	"ARM_TENANT_ID",
+	"ARM_CLIENT_ID",
+	"ARM_CLIENT_SECRET",
+}
+
+func TestLoadCredentials(t *testing.T) {
+	cases := []struct {
+		name        string
+		env         map[string]string
+		file        string
+		expected    *Credentials
+		expectedErr string
+	}{
+		{
+			name: "environment",
+			env: map[string]string{
+				"AZURE_SUBSCRIPTION_ID": "subscription",
+				"AZURE_TENANT_ID":       "tenant",
+				"AZURE_CLIENT_ID":       "client",
+				"AZURE_CLIENT_SECRET":   "secret",
+			},
+			file:     `{"subscriptionId": "other"}`,
+			expected: &Credentials{SubscriptionID: "subscription", TenantID: "tenant", ClientID: "client", ClientSecret: "secret"},
+		},
+		{
+			name: "incomplete environment",
+			env: map[string]string{
+				"AZURE_SUBSCRIPTION_ID": "subscription",
+				"AZURE_TENANT_ID":       "tenant",
+				"AZURE_CLIENT_ID":       "client",
+			},
+			expectedErr: "the client secret is missing from the Azure credentials",
+		},
+		{
+			name:     "file",
+			file:     `{"subscriptionId": "subscription", "tenantId": "tenant", "clientId": "client", "clientSecret": "secret"}`,
+			expected: &Credentials{SubscriptionID: "subscription", TenantID: "tenant", ClientID: "client", ClientSecret: "secret"},
+		},
+		{
+			name:        "incomplete file",
+			file:        `{"subscriptionId": "subscription", "clientId": "client", "clientSecret": "secret"}`,
+			expectedErr: "~/.azure/osServicePrincipal.json: the tenant ID is missing from the Azure credentials",
+		},
+		{
+			name:        "corrupt file",
+			file:        `{`,
+			expectedErr: "failed to parse ~/.azure/osServicePrincipal.json: unexpected end of JSON input",
+		},
+		{
+			name:        "no credentials",
+			expectedErr: "failed to load the Azure credentials: open ~/.azure/osServicePrincipal.json: no such file or directory",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			home, err := ioutil.TempDir("", "openshift-install-azure-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(home)
+			defer func(home string) { os.Setenv("HOME", home) }(os.Getenv("HOME"))
+			os.Setenv("HOME", home)
+			for _, key := range credentialsEnv {
+				os.Unsetenv(key)
+			}
+			defer func() {
+				for _, key := range credentialsEnv {
+					os.Unsetenv(key)
+				}
+			}()
+			for key, value := range tc.env {
+				os.Setenv(key, value)
+			}
+			path := filepath.Join(home, ".azure", "osServicePrincipal.json")
+			if tc.file != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(path, []byte(tc.file), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			credentials, err := LoadCredentials()
+			if tc.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, tc.expectedErr, strings.Replace(err.Error(), home, "~", -1))
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, credentials)
+
+			if assert.NoError(t, credentials.SetTerraformEnvironment()) {
+				assert.Equal(t, "subscription", os.Getenv("ARM_SUBSCRIPTION_ID"))
+				assert.Equal(t, "tenant", os.Getenv("ARM_TENANT_ID"))
+				assert.Equal(t, "client", os.Getenv("ARM_CLIENT_ID"))
+				assert.Equal(t, "secret", os.Getenv("ARM_CLIENT_SECRET"))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package installconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+// ProxyConfig returns the proxy settings of the cluster, with the networks
+// and the internal domains of the cluster, which are never reached through
+// the proxy, added to the NoProxy of the install config.  It returns nil
+// when the cluster does not use a proxy.
+func (a *InstallConfig) ProxyConfig() *types.Proxy {
+	ic := a.Config
+	if ic.Proxy == nil {
+		return nil
+	}
+	proxy := *ic.Proxy
+	if proxy.NoProxy == "*" {
+		return &proxy
+	}
+
+	noProxy := sets.NewString(
+		"localhost",
+		"127.0.0.1",
+		".svc",
+		".cluster.local",
+		"api."+ic.ClusterDomain(),
+		"api-int."+ic.ClusterDomain(),
+	)
+	if ic.ControlPlane != nil && ic.ControlPlane.Replicas != nil {
+		for i := int64(0); i < *ic.ControlPlane.Replicas; i++ {
+			noProxy.Insert(fmt.Sprintf("etcd-%d.%s", i, ic.ClusterDomain()))
+		}
+	}
+	if ic.Networking != nil {
+		if ic.Networking.MachineCIDR != nil {
+			noProxy.Insert(ic.Networking.MachineCIDR.String())
+		}
+		for _, network := range ic.Networking.ServiceNetwork {
+			noProxy.Insert(network.String())
+		}
+		for _, network := range ic.Networking.ClusterNetwork {
+			noProxy.Insert(network.CIDR.String())
+		}
+	}
+
+	switch ic.Platform.Name() {
+	case aws.Name:
+		// the instance metadata service and the internal hostnames
+		noProxy.Insert("169.254.169.254")
+		if ic.Platform.AWS.Region == "us-east-1" {
+			noProxy.Insert(".ec2.internal")
+		} else {
+			noProxy.Insert(fmt.Sprintf(".%s.compute.internal", ic.Platform.AWS.Region))
+		}
+	case azure.Name, openstack.Name:
+		// the instance metadata service
+		noProxy.Insert("169.254.169.254")
+	}
+
+	for _, v := range strings.Split(proxy.NoProxy, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			noProxy.Insert(v)
+		}
+	}
+	proxy.NoProxy = strings.Join(noProxy.List(), ",")
+	return &proxy
+}
@@ -0,0 +1,82 @@
+package installconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestProxyConfig(t *testing.T) {
+	installConfig := func(proxy *types.Proxy) *InstallConfig {
+		return &InstallConfig{Config: &types.InstallConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-cluster",
+			},
+			BaseDomain: "test-domain",
+			Networking: &types.Networking{
+				MachineCIDR:    ipnet.MustParseCIDR("10.0.0.0/16"),
+				ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+				ClusterNetwork: []types.ClusterNetworkEntry{
+					{
+						CIDR:       *ipnet.MustParseCIDR("10.128.0.0/14"),
+						HostPrefix: 23,
+					},
+				},
+			},
+			ControlPlane: &types.MachinePool{
+				Replicas: pointer.Int64Ptr(2),
+			},
+			Platform: types.Platform{
+				AWS: &aws.Platform{
+					Region: "us-west-2",
+				},
+			},
+			Proxy: proxy,
+		}}
+	}
+
+	cases := []struct {
+		name     string
+		proxy    *types.Proxy
+		expected *types.Proxy
+	}{
+		{
+			name: "no proxy",
+		},
+		{
+			name: "no proxy additions",
+			proxy: &types.Proxy{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3128",
+				NoProxy:    "internal.example.com, 192.168.0.0/24",
+			},
+			expected: &types.Proxy{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3128",
+				NoProxy:    ".cluster.local,.svc,.us-west-2.compute.internal,10.0.0.0/16,10.128.0.0/14,127.0.0.1,169.254.169.254,172.30.0.0/16,192.168.0.0/24,api-int.test-cluster.test-domain,api.test-cluster.test-domain,etcd-0.test-cluster.test-domain,etcd-1.test-cluster.test-domain,internal.example.com,localhost",
+			},
+		},
+		{
+			name: "bypass for all destinations",
+			proxy: &types.Proxy{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   "*",
+			},
+			expected: &types.Proxy{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   "*",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, installConfig(tc.proxy).ProxyConfig())
+		})
+	}
+}
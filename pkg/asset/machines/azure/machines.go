@@ -0,0 +1,111 @@
+// Package azure generates Machine objects for azure.
+package azure
+
+import (
+	"fmt"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	clusterazure "github.com/openshift/installer/pkg/asset/cluster/azure"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+// Machines returns a list of machines for a machinepool.
+func Machines(clusterID string, config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]machineapi.Machine, error) {
+	if configPlatform := config.Platform.Name(); configPlatform != azure.Name {
+		return nil, fmt.Errorf("non-Azure configuration: %q", configPlatform)
+	}
+	if poolPlatform := pool.Platform.Name(); poolPlatform != azure.Name {
+		return nil, fmt.Errorf("non-Azure machine-pool: %q", poolPlatform)
+	}
+	platform := config.Platform.Azure
+	mpool := pool.Platform.Azure
+
+	total := int64(1)
+	if pool.Replicas != nil {
+		total = *pool.Replicas
+	}
+	var machines []machineapi.Machine
+	for idx := int64(0); idx < total; idx++ {
+		var zone *string
+		if len(mpool.Zones) > 0 {
+			zone = pointer.StringPtr(mpool.Zones[int(idx)%len(mpool.Zones)])
+		}
+		provider, err := provider(clusterID, platform, mpool, zone, role, userDataSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create provider")
+		}
+		machine := machineapi.Machine{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "machine.openshift.io/v1beta1",
+				Kind:       "Machine",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "openshift-machine-api",
+				Name:      fmt.Sprintf("%s-%s-%d", clusterID, pool.Name, idx),
+				Labels: map[string]string{
+					"machine.openshift.io/cluster-api-cluster":      clusterID,
+					"machine.openshift.io/cluster-api-machine-role": role,
+					"machine.openshift.io/cluster-api-machine-type": role,
+				},
+			},
+			Spec: machineapi.MachineSpec{
+				ProviderSpec: machineapi.ProviderSpec{
+					Value: &runtime.RawExtension{Object: provider},
+				},
+				// we don't need to set Versions, because we control those via operators.
+			},
+		}
+
+		machines = append(machines, machine)
+	}
+
+	return machines, nil
+}
+
+func provider(clusterID string, platform *azure.Platform, mpool *azure.MachinePool, zone *string, role, userDataSecret string) (*AzureMachineProviderSpec, error) {
+	if mpool.InstanceType == "" {
+		return nil, errors.New("no instance type")
+	}
+	resourceGroup := clusterazure.ResourceGroupName(clusterID)
+	return &AzureMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "AzureMachineProviderSpec",
+		},
+		UserDataSecret:    &corev1.SecretReference{Name: userDataSecret},
+		CredentialsSecret: &corev1.SecretReference{Name: "azure-cloud-credentials", Namespace: "openshift-machine-api"},
+		Location:          platform.Region,
+		VMSize:            mpool.InstanceType,
+		Image: Image{
+			ResourceID: fmt.Sprintf("/resourceGroups/%s/providers/Microsoft.Compute/images/%s", resourceGroup, clusterID),
+		},
+		OSDisk: OSDisk{
+			OSType:      "Linux",
+			DiskSizeGB:  128,
+			ManagedDisk: ManagedDisk{StorageAccountType: "Premium_LRS"},
+		},
+		Vnet:                 fmt.Sprintf("%s-vnet", clusterID),
+		Subnet:               fmt.Sprintf("%s-%s-subnet", clusterID, role),
+		Zone:                 zone,
+		NetworkResourceGroup: resourceGroup,
+		ResourceGroup:        resourceGroup,
+		ManagedIdentity:      fmt.Sprintf("%s-identity", clusterID),
+	}, nil
+}
+
+// ConfigMasters assigns the public and internal load balancers to the
+// given machines.
+func ConfigMasters(machines []machineapi.Machine, clusterID string) {
+	for _, machine := range machines {
+		providerSpec := machine.Spec.ProviderSpec.Value.Object.(*AzureMachineProviderSpec)
+		providerSpec.PublicLoadBalancer = clusterID
+		providerSpec.InternalLoadBalancer = fmt.Sprintf("%s-internal", clusterID)
+	}
+}
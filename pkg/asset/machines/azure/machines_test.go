@@ -0,0 +1,123 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+func installConfig() *types.InstallConfig {
+	return &types.InstallConfig{
+		Platform: types.Platform{
+			Azure: &azure.Platform{Region: "centralus"},
+		},
+	}
+}
+
+func machinePool(replicas int64, zones ...string) *types.MachinePool {
+	return &types.MachinePool{
+		Name:     "master",
+		Replicas: pointer.Int64Ptr(replicas),
+		Platform: types.MachinePoolPlatform{
+			Azure: &azure.MachinePool{
+				InstanceType: "Standard_DS4_v2",
+				Zones:        zones,
+			},
+		},
+	}
+}
+
+func TestProvider(t *testing.T) {
+	spec, err := provider("mycluster-abcde", &azure.Platform{Region: "centralus"}, &azure.MachinePool{InstanceType: "Standard_DS4_v2"}, pointer.StringPtr("2"), "master", "master-user-data")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &AzureMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "azureproviderconfig.openshift.io/v1beta1",
+			Kind:       "AzureMachineProviderSpec",
+		},
+		UserDataSecret:    &corev1.SecretReference{Name: "master-user-data"},
+		CredentialsSecret: &corev1.SecretReference{Name: "azure-cloud-credentials", Namespace: "openshift-machine-api"},
+		Location:          "centralus",
+		VMSize:            "Standard_DS4_v2",
+		Image: Image{
+			ResourceID: "/resourceGroups/mycluster-abcde-rg/providers/Microsoft.Compute/images/mycluster-abcde",
+		},
+		OSDisk: OSDisk{
+			OSType:      "Linux",
+			DiskSizeGB:  128,
+			ManagedDisk: ManagedDisk{StorageAccountType: "Premium_LRS"},
+		},
+		Vnet:                 "mycluster-abcde-vnet",
+		Subnet:               "mycluster-abcde-master-subnet",
+		Zone:                 pointer.StringPtr("2"),
+		NetworkResourceGroup: "mycluster-abcde-rg",
+		ResourceGroup:        "mycluster-abcde-rg",
+		ManagedIdentity:      "mycluster-abcde-identity",
+	}, spec)
+}
+
+func TestProviderNoInstanceType(t *testing.T) {
+	_, err := provider("mycluster-abcde", &azure.Platform{Region: "centralus"}, &azure.MachinePool{}, nil, "master", "master-user-data")
+	assert.EqualError(t, err, "no instance type")
+}
+
+func TestMachines(t *testing.T) {
+	cases := []struct {
+		name          string
+		pool          *types.MachinePool
+		expectedNames []string
+		expectedZones []*string
+	}{
+		{
+			name:          "no zones",
+			pool:          machinePool(2),
+			expectedNames: []string{"mycluster-abcde-master-0", "mycluster-abcde-master-1"},
+			expectedZones: []*string{nil, nil},
+		},
+		{
+			name:          "zones",
+			pool:          machinePool(3, "1", "2"),
+			expectedNames: []string{"mycluster-abcde-master-0", "mycluster-abcde-master-1", "mycluster-abcde-master-2"},
+			expectedZones: []*string{pointer.StringPtr("1"), pointer.StringPtr("2"), pointer.StringPtr("1")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machines, err := Machines("mycluster-abcde", installConfig(), tc.pool, "master", "master-user-data")
+			if !assert.NoError(t, err) {
+				return
+			}
+			ConfigMasters(machines, "mycluster-abcde")
+
+			names := []string{}
+			zones := []*string{}
+			for _, machine := range machines {
+				names = append(names, machine.Name)
+				assert.Equal(t, "master", machine.Labels["machine.openshift.io/cluster-api-machine-role"])
+				spec := machine.Spec.ProviderSpec.Value.Object.(*AzureMachineProviderSpec)
+				zones = append(zones, spec.Zone)
+				assert.Equal(t, "mycluster-abcde", spec.PublicLoadBalancer)
+				assert.Equal(t, "mycluster-abcde-internal", spec.InternalLoadBalancer)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+			assert.Equal(t, tc.expectedZones, zones)
+		})
+	}
+}
+
+func TestMachinesNonAzure(t *testing.T) {
+	config := &types.InstallConfig{
+		Platform: types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+	}
+	_, err := Machines("mycluster-abcde", config, machinePool(1), "master", "master-user-data")
+	assert.EqualError(t, err, `non-Azure configuration: "aws"`)
+}
@@ -0,0 +1,62 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+func TestMachineSets(t *testing.T) {
+	cases := []struct {
+		name             string
+		replicas         int64
+		zones            []string
+		expectedNames    []string
+		expectedReplicas []int32
+		expectedZones    []*string
+	}{
+		{
+			name:             "no zones",
+			replicas:         3,
+			expectedNames:    []string{"mycluster-abcde-worker-centralus"},
+			expectedReplicas: []int32{3},
+			expectedZones:    []*string{nil},
+		},
+		{
+			name:             "zones",
+			replicas:         4,
+			zones:            []string{"1", "2", "3"},
+			expectedNames:    []string{"mycluster-abcde-worker-centralus1", "mycluster-abcde-worker-centralus2", "mycluster-abcde-worker-centralus3"},
+			expectedReplicas: []int32{2, 1, 1},
+			expectedZones:    []*string{pointer.StringPtr("1"), pointer.StringPtr("2"), pointer.StringPtr("3")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := machinePool(tc.replicas, tc.zones...)
+			pool.Name = "worker"
+			machineSets, err := MachineSets("mycluster-abcde", installConfig(), pool, "worker", "worker-user-data")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			names := []string{}
+			replicas := []int32{}
+			zones := []*string{}
+			for _, machineSet := range machineSets {
+				names = append(names, machineSet.Name)
+				replicas = append(replicas, *machineSet.Spec.Replicas)
+				assert.Equal(t, machineSet.Name, machineSet.Spec.Selector.MatchLabels["machine.openshift.io/cluster-api-machineset"])
+				assert.Equal(t, machineSet.Name, machineSet.Spec.Template.Labels["machine.openshift.io/cluster-api-machineset"])
+				spec := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*AzureMachineProviderSpec)
+				assert.Equal(t, "mycluster-abcde-worker-subnet", spec.Subnet)
+				assert.Equal(t, "worker-user-data", spec.UserDataSecret.Name)
+				zones = append(zones, spec.Zone)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+			assert.Equal(t, tc.expectedReplicas, replicas)
+			assert.Equal(t, tc.expectedZones, zones)
+		})
+	}
+}
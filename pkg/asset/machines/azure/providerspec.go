@@ -0,0 +1,96 @@
+package azure
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The cluster-api Azure provider is not vendored, so this file carries the
+// subset of its azureproviderconfig.openshift.io/v1beta1 API the installer
+// writes into the Machine and MachineSet manifests.
+
+var (
+	// SchemeGroupVersion is the group version of the provider spec.
+	SchemeGroupVersion = schema.GroupVersion{Group: "azureproviderconfig.openshift.io", Version: "v1beta1"}
+
+	schemeBuilder = runtime.NewSchemeBuilder(func(scheme *runtime.Scheme) error {
+		scheme.AddKnownTypes(SchemeGroupVersion, &AzureMachineProviderSpec{})
+		return nil
+	})
+
+	// AddToScheme adds the provider spec to a scheme.
+	AddToScheme = schemeBuilder.AddToScheme
+)
+
+// AzureMachineProviderSpec is the provider specification of an Azure
+// Machine.
+type AzureMachineProviderSpec struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// UserDataSecret is the secret holding the Ignition config of the
+	// machine.
+	UserDataSecret *corev1.SecretReference `json:"userDataSecret,omitempty"`
+
+	// CredentialsSecret is the secret holding the credentials of the
+	// machine controller.
+	CredentialsSecret *corev1.SecretReference `json:"credentialsSecret,omitempty"`
+
+	Location             string  `json:"location,omitempty"`
+	VMSize               string  `json:"vmSize,omitempty"`
+	Image                Image   `json:"image"`
+	OSDisk               OSDisk  `json:"osDisk"`
+	Vnet                 string  `json:"vnet,omitempty"`
+	Subnet               string  `json:"subnet"`
+	Zone                 *string `json:"zone,omitempty"`
+	NetworkResourceGroup string  `json:"networkResourceGroup,omitempty"`
+	ResourceGroup        string  `json:"resourceGroup,omitempty"`
+	ManagedIdentity      string  `json:"managedIdentity,omitempty"`
+	PublicIP             bool    `json:"publicIP"`
+
+	// InternalLoadBalancer and PublicLoadBalancer are the load
+	// balancers whose backend pools the machine joins.
+	InternalLoadBalancer string `json:"internalLoadBalancer,omitempty"`
+	PublicLoadBalancer   string `json:"publicLoadBalancer,omitempty"`
+}
+
+// Image is the image a machine boots.
+type Image struct {
+	ResourceID string `json:"resourceID"`
+}
+
+// OSDisk is the operating system disk of a machine.
+type OSDisk struct {
+	OSType      string      `json:"osType"`
+	ManagedDisk ManagedDisk `json:"managedDisk"`
+	DiskSizeGB  int32       `json:"diskSizeGB"`
+}
+
+// ManagedDisk is the storage of a managed disk.
+type ManagedDisk struct {
+	StorageAccountType string `json:"storageAccountType"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (s *AzureMachineProviderSpec) DeepCopyObject() runtime.Object {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	s.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if s.UserDataSecret != nil {
+		secret := *s.UserDataSecret
+		out.UserDataSecret = &secret
+	}
+	if s.CredentialsSecret != nil {
+		secret := *s.CredentialsSecret
+		out.CredentialsSecret = &secret
+	}
+	if s.Zone != nil {
+		zone := *s.Zone
+		out.Zone = &zone
+	}
+	return &out
+}
@@ -13,12 +13,17 @@ import (
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/machines/aws"
+	"github.com/openshift/installer/pkg/asset/machines/azure"
 	"github.com/openshift/installer/pkg/asset/machines/libvirt"
 	"github.com/openshift/installer/pkg/asset/machines/machineconfig"
 	"github.com/openshift/installer/pkg/asset/machines/openstack"
+	"github.com/openshift/installer/pkg/asset/machines/vsphere"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/rhcos"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
 	awsdefaults "github.com/openshift/installer/pkg/types/aws/defaults"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	azuredefaults "github.com/openshift/installer/pkg/types/azure/defaults"
 	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
 	nonetypes "github.com/openshift/installer/pkg/types/none"
 	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
@@ -73,6 +78,7 @@ func (m *Master) Dependencies() []asset.Asset {
 		&installconfig.InstallConfig{},
 		new(rhcos.Image),
 		&machine.Master{},
+		&overlays.Overlays{},
 	}
 }
 
@@ -82,13 +88,19 @@ func awsDefaultMasterMachineType(installconfig *installconfig.InstallConfig) str
 	return fmt.Sprintf("%s.xlarge", instanceClass)
 }
 
+func azureDefaultMasterMachineType(installconfig *installconfig.InstallConfig) string {
+	region := installconfig.Config.Platform.Azure.Region
+	return azuredefaults.InstanceClass(region)
+}
+
 // Generate generates the Master asset.
 func (m *Master) Generate(dependencies asset.Parents) error {
 	clusterID := &installconfig.ClusterID{}
 	installconfig := &installconfig.InstallConfig{}
 	rhcosImage := new(rhcos.Image)
 	mign := &machine.Master{}
-	dependencies.Get(clusterID, installconfig, rhcosImage, mign)
+	overlay := &overlays.Overlays{}
+	dependencies.Get(clusterID, installconfig, rhcosImage, mign, overlay)
 
 	ic := installconfig.Config
 	pool := ic.ControlPlane
@@ -113,6 +125,17 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 			return errors.Wrap(err, "failed to create master machine objects")
 		}
 		aws.ConfigMasters(machines, clusterID.InfraID)
+	case azuretypes.Name:
+		mpool := defaultAzureMachinePoolPlatform()
+		mpool.InstanceType = azureDefaultMasterMachineType(installconfig)
+		mpool.Set(ic.Platform.Azure.DefaultMachinePlatform)
+		mpool.Set(pool.Platform.Azure)
+		pool.Platform.Azure = &mpool
+		machines, err = azure.Machines(clusterID.InfraID, ic, pool, "master", "master-user-data")
+		if err != nil {
+			return errors.Wrap(err, "failed to create master machine objects")
+		}
+		azure.ConfigMasters(machines, clusterID.InfraID)
 	case libvirttypes.Name:
 		mpool := defaultLibvirtMachinePoolPlatform()
 		mpool.Set(ic.Platform.Libvirt.DefaultMachinePlatform)
@@ -133,7 +156,22 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 			return errors.Wrap(err, "failed to create master machine objects")
 		}
 		openstack.ConfigMasters(machines, clusterID.InfraID)
-	case nonetypes.Name, vspheretypes.Name:
+	case vspheretypes.Name:
+		// only installer-provisioned clusters have machine objects
+		if ic.Platform.VSphere.Cluster == "" {
+			break
+		}
+		mpool := defaultVSphereMachinePoolPlatform()
+		mpool.NumCPUs = 4
+		mpool.MemoryMiB = 16384
+		mpool.Set(ic.Platform.VSphere.DefaultMachinePlatform)
+		mpool.Set(pool.Platform.VSphere)
+		pool.Platform.VSphere = &mpool
+		machines, err = vsphere.Machines(clusterID.InfraID, ic, pool, "master", "master-user-data")
+		if err != nil {
+			return errors.Wrap(err, "failed to create master machine objects")
+		}
+	case nonetypes.Name:
 	default:
 		return fmt.Errorf("invalid Platform")
 	}
@@ -153,6 +191,12 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 	if ic.SSHKey != "" {
 		machineConfigs = append(machineConfigs, machineconfig.ForAuthorizedKeys(ic.SSHKey, "master"))
 	}
+	if ic.AdditionalTrustBundle != "" {
+		machineConfigs = append(machineConfigs, machineconfig.ForAdditionalTrustBundle(ic.AdditionalTrustBundle, "master"))
+	}
+	if proxy := installconfig.ProxyConfig(); proxy != nil {
+		machineConfigs = append(machineConfigs, machineconfig.ForProxy(proxy, "master"))
+	}
 	m.MachineConfigFiles, err = machineconfig.Manifests(machineConfigs, "master", directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create MachineConfig manifests for master machines")
@@ -173,6 +217,18 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 		}
 	}
 
+	userDataFiles, err := overlay.Apply([]*asset.File{m.UserDataFile})
+	if err != nil {
+		return err
+	}
+	m.UserDataFile = userDataFiles[0]
+	if m.MachineConfigFiles, err = overlay.Apply(m.MachineConfigFiles); err != nil {
+		return err
+	}
+	if m.MachineFiles, err = overlay.Apply(m.MachineFiles); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -216,12 +272,16 @@ func (m *Master) Load(f asset.FileFetcher) (found bool, err error) {
 func (m *Master) Machines() ([]machineapi.Machine, error) {
 	scheme := runtime.NewScheme()
 	awsapi.AddToScheme(scheme)
+	azure.AddToScheme(scheme)
 	libvirtapi.AddToScheme(scheme)
 	openstackapi.AddToScheme(scheme)
+	vsphere.AddToScheme(scheme)
 	decoder := serializer.NewCodecFactory(scheme).UniversalDecoder(
 		awsprovider.SchemeGroupVersion,
+		azure.SchemeGroupVersion,
 		libvirtprovider.SchemeGroupVersion,
 		openstackprovider.SchemeGroupVersion,
+		vsphere.SchemeGroupVersion,
 	)
 
 	machines := []machineapi.Machine{}
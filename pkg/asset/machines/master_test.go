@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/rhcos"
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
@@ -19,6 +20,7 @@ func TestMasterGenerateMachineConfigs(t *testing.T) {
 	cases := []struct {
 		name                  string
 		key                   string
+		trustBundle           string
 		expectedMachineConfig string
 	}{
 		{
@@ -52,6 +54,41 @@ spec:
     storage: {}
     systemd: {}
   osImageURL: ""
+`,
+		},
+		{
+			name:        "additional trust bundle",
+			trustBundle: "test-bundle",
+			expectedMachineConfig: `---
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfig
+metadata:
+  creationTimestamp: null
+  labels:
+    machineconfiguration.openshift.io/role: master
+  name: 99-master-additional-trust-bundle
+spec:
+  config:
+    ignition:
+      config: {}
+      security:
+        tls: {}
+      timeouts: {}
+      version: 2.2.0
+    networkd: {}
+    passwd: {}
+    storage:
+      files:
+      - contents:
+          source: data:text/plain;charset=utf-8;base64,dGVzdC1idW5kbGU=
+          verification: {}
+        filesystem: root
+        mode: 420
+        path: /etc/pki/ca-trust/source/anchors/openshift-config-user-ca-bundle.crt
+        user:
+          name: root
+    systemd: {}
+  osImageURL: ""
 `,
 		},
 	}
@@ -68,8 +105,9 @@ spec:
 						ObjectMeta: metav1.ObjectMeta{
 							Name: "test-cluster",
 						},
-						SSHKey:     tc.key,
-						BaseDomain: "test-domain",
+						SSHKey:                tc.key,
+						BaseDomain:            "test-domain",
+						AdditionalTrustBundle: tc.trustBundle,
 						Platform: types.Platform{
 							AWS: &awstypes.Platform{
 								Region: "us-east-1",
@@ -92,6 +130,7 @@ spec:
 						Data:     []byte("test-ignition"),
 					},
 				},
+				&overlays.Overlays{},
 			)
 			master := &Master{}
 			if err := master.Generate(parents); err != nil {
@@ -0,0 +1,96 @@
+// Package vsphere generates Machine objects for vSphere.
+package vsphere
+
+import (
+	"fmt"
+	"path"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clustervsphere "github.com/openshift/installer/pkg/asset/cluster/vsphere"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// Machines returns a list of machines for a machinepool.
+func Machines(clusterID string, config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]machineapi.Machine, error) {
+	if configPlatform := config.Platform.Name(); configPlatform != vsphere.Name {
+		return nil, fmt.Errorf("non-vSphere configuration: %q", configPlatform)
+	}
+	if poolPlatform := pool.Platform.Name(); poolPlatform != vsphere.Name {
+		return nil, fmt.Errorf("non-vSphere machine-pool: %q", poolPlatform)
+	}
+	platform := config.Platform.VSphere
+	mpool := pool.Platform.VSphere
+
+	total := int64(1)
+	if pool.Replicas != nil {
+		total = *pool.Replicas
+	}
+	var machines []machineapi.Machine
+	for idx := int64(0); idx < total; idx++ {
+		provider, err := provider(clusterID, platform, mpool, userDataSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create provider")
+		}
+		machine := machineapi.Machine{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "machine.openshift.io/v1beta1",
+				Kind:       "Machine",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "openshift-machine-api",
+				Name:      fmt.Sprintf("%s-%s-%d", clusterID, pool.Name, idx),
+				Labels: map[string]string{
+					"machine.openshift.io/cluster-api-cluster":      clusterID,
+					"machine.openshift.io/cluster-api-machine-role": role,
+					"machine.openshift.io/cluster-api-machine-type": role,
+				},
+			},
+			Spec: machineapi.MachineSpec{
+				ProviderSpec: machineapi.ProviderSpec{
+					Value: &runtime.RawExtension{Object: provider},
+				},
+				// we don't need to set Versions, because we control those via operators.
+			},
+		}
+
+		machines = append(machines, machine)
+	}
+
+	return machines, nil
+}
+
+func provider(clusterID string, platform *vsphere.Platform, mpool *vsphere.MachinePool, userDataSecret string) (*VSphereMachineProviderSpec, error) {
+	if platform.Cluster == "" {
+		return nil, errors.New("no vSphere cluster")
+	}
+	datacenter := "/" + platform.Workspace.Datacenter
+	return &VSphereMachineProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "VSphereMachineProviderSpec",
+		},
+		UserDataSecret:    &corev1.LocalObjectReference{Name: userDataSecret},
+		CredentialsSecret: &corev1.LocalObjectReference{Name: "vsphere-cloud-credentials"},
+		Template:          clustervsphere.TemplateName(clusterID),
+		Workspace: &Workspace{
+			Server:       platform.Workspace.Server,
+			Datacenter:   platform.Workspace.Datacenter,
+			Folder:       path.Join(datacenter, "vm", platform.Workspace.Folder),
+			Datastore:    path.Join(datacenter, "datastore", platform.Workspace.DefaultDatastore),
+			ResourcePool: clustervsphere.ResourcePoolPath(platform),
+		},
+		Network: NetworkSpec{
+			Devices: []NetworkDeviceSpec{{NetworkName: platform.PublicNetwork}},
+		},
+		NumCPUs:           mpool.NumCPUs,
+		NumCoresPerSocket: mpool.NumCoresPerSocket,
+		MemoryMiB:         mpool.MemoryMiB,
+		DiskGiB:           mpool.OSDisk.DiskSizeGB,
+	}, nil
+}
@@ -0,0 +1,79 @@
+// Package vsphere generates Machine objects for vSphere.
+package vsphere
+
+import (
+	"fmt"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// MachineSets returns a list of machinesets for a machinepool.  vSphere has
+// no zones, so each pool gets a single machineset.
+func MachineSets(clusterID string, config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]*machineapi.MachineSet, error) {
+	if configPlatform := config.Platform.Name(); configPlatform != vsphere.Name {
+		return nil, fmt.Errorf("non-vSphere configuration: %q", configPlatform)
+	}
+	if poolPlatform := pool.Platform.Name(); poolPlatform != vsphere.Name {
+		return nil, fmt.Errorf("non-vSphere machine-pool: %q", poolPlatform)
+	}
+	platform := config.Platform.VSphere
+	mpool := pool.Platform.VSphere
+
+	total := int32(0)
+	if pool.Replicas != nil {
+		total = int32(*pool.Replicas)
+	}
+	provider, err := provider(clusterID, platform, mpool, userDataSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create provider")
+	}
+	name := fmt.Sprintf("%s-%s", clusterID, pool.Name)
+	mset := &machineapi.MachineSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machine.openshift.io/v1beta1",
+			Kind:       "MachineSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-machine-api",
+			Name:      name,
+			Labels: map[string]string{
+				"machine.openshift.io/cluster-api-cluster":      clusterID,
+				"machine.openshift.io/cluster-api-machine-role": role,
+				"machine.openshift.io/cluster-api-machine-type": role,
+			},
+		},
+		Spec: machineapi.MachineSetSpec{
+			Replicas: &total,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"machine.openshift.io/cluster-api-machineset": name,
+					"machine.openshift.io/cluster-api-cluster":    clusterID,
+				},
+			},
+			Template: machineapi.MachineTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"machine.openshift.io/cluster-api-machineset":   name,
+						"machine.openshift.io/cluster-api-cluster":      clusterID,
+						"machine.openshift.io/cluster-api-machine-role": role,
+						"machine.openshift.io/cluster-api-machine-type": role,
+					},
+				},
+				Spec: machineapi.MachineSpec{
+					ProviderSpec: machineapi.ProviderSpec{
+						Value: &runtime.RawExtension{Object: provider},
+					},
+					// we don't need to set Versions, because we control those via cluster operators.
+				},
+			},
+		},
+	}
+
+	return []*machineapi.MachineSet{mset}, nil
+}
@@ -0,0 +1,100 @@
+package vsphere
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The cluster-api vSphere provider is not vendored, so this file carries the
+// subset of its vsphereprovider.openshift.io/v1alpha1 API the installer
+// writes into the Machine and MachineSet manifests.
+
+var (
+	// SchemeGroupVersion is the group version of the provider spec.
+	SchemeGroupVersion = schema.GroupVersion{Group: "vsphereprovider.openshift.io", Version: "v1alpha1"}
+
+	schemeBuilder = runtime.NewSchemeBuilder(func(scheme *runtime.Scheme) error {
+		scheme.AddKnownTypes(SchemeGroupVersion, &VSphereMachineProviderSpec{})
+		return nil
+	})
+
+	// AddToScheme adds the provider spec to a scheme.
+	AddToScheme = schemeBuilder.AddToScheme
+)
+
+// VSphereMachineProviderSpec is the provider specification of a vSphere
+// Machine.
+type VSphereMachineProviderSpec struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// UserDataSecret is the secret holding the Ignition config of the
+	// machine.
+	UserDataSecret *corev1.LocalObjectReference `json:"userDataSecret,omitempty"`
+
+	// CredentialsSecret is the secret holding the credentials of the
+	// machine controller.
+	CredentialsSecret *corev1.LocalObjectReference `json:"credentialsSecret,omitempty"`
+
+	// Template is the name of the virtual machine the machine is cloned
+	// from.
+	Template string `json:"template"`
+
+	Workspace *Workspace  `json:"workspace,omitempty"`
+	Network   NetworkSpec `json:"network"`
+
+	NumCPUs           int32 `json:"numCPUs,omitempty"`
+	NumCoresPerSocket int32 `json:"numCoresPerSocket,omitempty"`
+	MemoryMiB         int64 `json:"memoryMiB,omitempty"`
+	DiskGiB           int32 `json:"diskGiB,omitempty"`
+}
+
+// Workspace locates the machine in the vCenter.  The folder, datastore and
+// resource pool are inventory paths.
+type Workspace struct {
+	Server       string `json:"server,omitempty"`
+	Datacenter   string `json:"datacenter,omitempty"`
+	Folder       string `json:"folder,omitempty"`
+	Datastore    string `json:"datastore,omitempty"`
+	ResourcePool string `json:"resourcePool,omitempty"`
+}
+
+// NetworkSpec is the network configuration of a machine.
+type NetworkSpec struct {
+	Devices []NetworkDeviceSpec `json:"devices"`
+}
+
+// NetworkDeviceSpec is a network device of a machine.
+type NetworkDeviceSpec struct {
+	// NetworkName is the name of the vSphere network the device is
+	// connected to.
+	NetworkName string `json:"networkName"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (s *VSphereMachineProviderSpec) DeepCopyObject() runtime.Object {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	s.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if s.UserDataSecret != nil {
+		secret := *s.UserDataSecret
+		out.UserDataSecret = &secret
+	}
+	if s.CredentialsSecret != nil {
+		secret := *s.CredentialsSecret
+		out.CredentialsSecret = &secret
+	}
+	if s.Workspace != nil {
+		workspace := *s.Workspace
+		out.Workspace = &workspace
+	}
+	if s.Network.Devices != nil {
+		out.Network.Devices = make([]NetworkDeviceSpec, len(s.Network.Devices))
+		copy(out.Network.Devices, s.Network.Devices)
+	}
+	return &out
+}
@@ -22,12 +22,16 @@ import (
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/machines/aws"
+	"github.com/openshift/installer/pkg/asset/machines/azure"
 	"github.com/openshift/installer/pkg/asset/machines/libvirt"
 	"github.com/openshift/installer/pkg/asset/machines/machineconfig"
 	"github.com/openshift/installer/pkg/asset/machines/openstack"
+	"github.com/openshift/installer/pkg/asset/machines/vsphere"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/rhcos"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
 	awsdefaults "github.com/openshift/installer/pkg/types/aws/defaults"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
 	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
 	nonetypes "github.com/openshift/installer/pkg/types/none"
 	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
@@ -57,6 +61,10 @@ func defaultAWSMachinePoolPlatform() awstypes.MachinePool {
 	}
 }
 
+func defaultAzureMachinePoolPlatform() azuretypes.MachinePool {
+	return azuretypes.MachinePool{}
+}
+
 func defaultLibvirtMachinePoolPlatform() libvirttypes.MachinePool {
 	return libvirttypes.MachinePool{}
 }
@@ -67,6 +75,16 @@ func defaultOpenStackMachinePoolPlatform(flavor string) openstacktypes.MachinePo
 	}
 }
 
+func defaultVSphereMachinePoolPlatform() vspheretypes.MachinePool {
+	return vspheretypes.MachinePool{
+		NumCPUs:   2,
+		MemoryMiB: 8192,
+		OSDisk: vspheretypes.OSDisk{
+			DiskSizeGB: 120,
+		},
+	}
+}
+
 // Worker generates the machinesets for `worker` machine pool.
 type Worker struct {
 	UserDataFile       *asset.File
@@ -91,6 +109,7 @@ func (w *Worker) Dependencies() []asset.Asset {
 		&installconfig.InstallConfig{},
 		new(rhcos.Image),
 		&machine.Worker{},
+		&overlays.Overlays{},
 	}
 }
 
@@ -100,17 +119,28 @@ func awsDefaultWorkerMachineType(installconfig *installconfig.InstallConfig) str
 	return fmt.Sprintf("%s.large", instanceClass)
 }
 
+func azureDefaultWorkerMachineType() string {
+	return "Standard_D2s_v3"
+}
+
 // Generate generates the Worker asset.
 func (w *Worker) Generate(dependencies asset.Parents) error {
 	clusterID := &installconfig.ClusterID{}
 	installconfig := &installconfig.InstallConfig{}
 	rhcosImage := new(rhcos.Image)
 	wign := &machine.Worker{}
-	dependencies.Get(clusterID, installconfig, rhcosImage, wign)
+	overlay := &overlays.Overlays{}
+	dependencies.Get(clusterID, installconfig, rhcosImage, wign, overlay)
 
 	machineConfigs := []*mcfgv1.MachineConfig{}
 	machineSets := []runtime.Object{}
 	ic := installconfig.Config
+	if ic.AdditionalTrustBundle != "" {
+		machineConfigs = append(machineConfigs, machineconfig.ForAdditionalTrustBundle(ic.AdditionalTrustBundle, "worker"))
+	}
+	if proxy := installconfig.ProxyConfig(); proxy != nil {
+		machineConfigs = append(machineConfigs, machineconfig.ForProxy(proxy, "worker"))
+	}
 	for _, pool := range ic.Compute {
 		if ic.SSHKey != "" {
 			machineConfigs = append(machineConfigs, machineconfig.ForAuthorizedKeys(ic.SSHKey, "worker"))
@@ -136,6 +166,19 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			for _, set := range sets {
 				machineSets = append(machineSets, set)
 			}
+		case azuretypes.Name:
+			mpool := defaultAzureMachinePoolPlatform()
+			mpool.InstanceType = azureDefaultWorkerMachineType()
+			mpool.Set(ic.Platform.Azure.DefaultMachinePlatform)
+			mpool.Set(pool.Platform.Azure)
+			pool.Platform.Azure = &mpool
+			sets, err := azure.MachineSets(clusterID.InfraID, ic, &pool, "worker", "worker-user-data")
+			if err != nil {
+				return errors.Wrap(err, "failed to create worker machine objects")
+			}
+			for _, set := range sets {
+				machineSets = append(machineSets, set)
+			}
 		case libvirttypes.Name:
 			mpool := defaultLibvirtMachinePoolPlatform()
 			mpool.Set(ic.Platform.Libvirt.DefaultMachinePlatform)
@@ -161,7 +204,23 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			for _, set := range sets {
 				machineSets = append(machineSets, set)
 			}
-		case nonetypes.Name, vspheretypes.Name:
+		case vspheretypes.Name:
+			// only installer-provisioned clusters have machine objects
+			if ic.Platform.VSphere.Cluster == "" {
+				break
+			}
+			mpool := defaultVSphereMachinePoolPlatform()
+			mpool.Set(ic.Platform.VSphere.DefaultMachinePlatform)
+			mpool.Set(pool.Platform.VSphere)
+			pool.Platform.VSphere = &mpool
+			sets, err := vsphere.MachineSets(clusterID.InfraID, ic, &pool, "worker", "worker-user-data")
+			if err != nil {
+				return errors.Wrap(err, "failed to create worker machine objects")
+			}
+			for _, set := range sets {
+				machineSets = append(machineSets, set)
+			}
+		case nonetypes.Name:
 		default:
 			return fmt.Errorf("invalid Platform")
 		}
@@ -197,6 +256,18 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 		}
 	}
 
+	userDataFiles, err := overlay.Apply([]*asset.File{w.UserDataFile})
+	if err != nil {
+		return err
+	}
+	w.UserDataFile = userDataFiles[0]
+	if w.MachineConfigFiles, err = overlay.Apply(w.MachineConfigFiles); err != nil {
+		return err
+	}
+	if w.MachineSetFiles, err = overlay.Apply(w.MachineSetFiles); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -240,12 +311,16 @@ func (w *Worker) Load(f asset.FileFetcher) (found bool, err error) {
 func (w *Worker) MachineSets() ([]machineapi.MachineSet, error) {
 	scheme := runtime.NewScheme()
 	awsapi.AddToScheme(scheme)
+	azure.AddToScheme(scheme)
 	libvirtapi.AddToScheme(scheme)
 	openstackapi.AddToScheme(scheme)
+	vsphere.AddToScheme(scheme)
 	decoder := serializer.NewCodecFactory(scheme).UniversalDecoder(
 		awsprovider.SchemeGroupVersion,
+		azure.SchemeGroupVersion,
 		libvirtprovider.SchemeGroupVersion,
 		openstackprovider.SchemeGroupVersion,
+		vsphere.SchemeGroupVersion,
 	)
 
 	machineSets := []machineapi.MachineSet{}
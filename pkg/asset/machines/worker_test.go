@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/rhcos"
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
@@ -94,6 +95,7 @@ spec:
 						Data:     []byte("test-ignition"),
 					},
 				},
+				&overlays.Overlays{},
 			)
 			worker := &Worker{}
 			if err := worker.Generate(parents); err != nil {
@@ -0,0 +1,70 @@
+// Package azure generates Azure-specific manifests.
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// CloudProviderConfig is the Azure cloud provider configuration of a
+// cluster.
+type CloudProviderConfig struct {
+	TenantID       string
+	SubscriptionID string
+	ResourceGroup  string
+	GroupLocation  string
+	ResourcePrefix string
+}
+
+// config is the JSON configuration read by the Azure cloud provider.
+type config struct {
+	Cloud                        string `json:"cloud"`
+	TenantID                     string `json:"tenantId"`
+	SubscriptionID               string `json:"subscriptionId"`
+	UseManagedIdentityExtension  bool   `json:"useManagedIdentityExtension"`
+	UserAssignedIdentityID       string `json:"userAssignedIdentityID"`
+	ResourceGroup                string `json:"resourceGroup"`
+	Location                     string `json:"location"`
+	VNetName                     string `json:"vnetName"`
+	VNetResourceGroup            string `json:"vnetResourceGroup"`
+	SubnetName                   string `json:"subnetName"`
+	SecurityGroupName            string `json:"securityGroupName"`
+	RouteTableName               string `json:"routeTableName"`
+	CloudProviderBackoff         bool   `json:"cloudProviderBackoff"`
+	CloudProviderBackoffDuration int    `json:"cloudProviderBackoffDuration"`
+	UseInstanceMetadata          bool   `json:"useInstanceMetadata"`
+	LoadBalancerSku              string `json:"loadBalancerSku"`
+}
+
+// JSON generates the cloud provider JSON config for the Azure platform.
+// The nodes authenticate with the user-assigned identity they are created
+// with, so the config holds no secret.
+func (params CloudProviderConfig) JSON() (string, error) {
+	config := config{
+		Cloud:                        "AzurePublicCloud",
+		TenantID:                     params.TenantID,
+		SubscriptionID:               params.SubscriptionID,
+		UseManagedIdentityExtension:  true,
+		ResourceGroup:                params.ResourceGroup,
+		Location:                     params.GroupLocation,
+		VNetName:                     fmt.Sprintf("%s-vnet", params.ResourcePrefix),
+		VNetResourceGroup:            params.ResourceGroup,
+		SubnetName:                   fmt.Sprintf("%s-worker-subnet", params.ResourcePrefix),
+		SecurityGroupName:            fmt.Sprintf("%s-node-nsg", params.ResourcePrefix),
+		RouteTableName:               fmt.Sprintf("%s-node-routetable", params.ResourcePrefix),
+		CloudProviderBackoff:         true,
+		CloudProviderBackoffDuration: 6,
+		UseInstanceMetadata:          true,
+		LoadBalancerSku:              "standard",
+	}
+	buff := &bytes.Buffer{}
+	encoder := json.NewEncoder(buff)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(config); err != nil {
+		return "", errors.Wrap(err, "failed to marshal the cloud provider config")
+	}
+	return buff.String(), nil
+}
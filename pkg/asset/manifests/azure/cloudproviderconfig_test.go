@@ -0,0 +1,39 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudProviderConfig(t *testing.T) {
+	config := CloudProviderConfig{
+		GroupLocation:  "westeurope",
+		ResourcePrefix: "mycluster-abcde",
+		SubscriptionID: "subID",
+		TenantID:       "tenantID",
+		ResourceGroup:  "mycluster-abcde-rg",
+	}
+	expected := `{
+	"cloud": "AzurePublicCloud",
+	"tenantId": "tenantID",
+	"subscriptionId": "subID",
+	"useManagedIdentityExtension": true,
+	"userAssignedIdentityID": "",
+	"resourceGroup": "mycluster-abcde-rg",
+	"location": "westeurope",
+	"vnetName": "mycluster-abcde-vnet",
+	"vnetResourceGroup": "mycluster-abcde-rg",
+	"subnetName": "mycluster-abcde-worker-subnet",
+	"securityGroupName": "mycluster-abcde-node-nsg",
+	"routeTableName": "mycluster-abcde-node-routetable",
+	"cloudProviderBackoff": true,
+	"cloudProviderBackoffDuration": 6,
+	"useInstanceMetadata": true,
+	"loadBalancerSku": "standard"
+}
+`
+	actual, err := config.JSON()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
@@ -11,10 +11,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/installer/pkg/asset"
+	clusterazure "github.com/openshift/installer/pkg/asset/cluster/azure"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	osmachine "github.com/openshift/installer/pkg/asset/machines/openstack"
+	azuremanifests "github.com/openshift/installer/pkg/asset/manifests/azure"
 	vspheremanifests "github.com/openshift/installer/pkg/asset/manifests/vsphere"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
 	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
 	nonetypes "github.com/openshift/installer/pkg/types/none"
 	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
@@ -46,6 +50,7 @@ func (*CloudProviderConfig) Name() string {
 // the asset.
 func (*CloudProviderConfig) Dependencies() []asset.Asset {
 	return []asset.Asset{
+		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
 		// PlatformCredsCheck just checks the creds (and asks, if needed)
 		// We do not actually use it in this asset directly, hence
@@ -56,8 +61,9 @@ func (*CloudProviderConfig) Dependencies() []asset.Asset {
 
 // Generate generates the CloudProviderConfig.
 func (cpc *CloudProviderConfig) Generate(dependencies asset.Parents) error {
+	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(installConfig)
+	dependencies.Get(clusterID, installConfig)
 
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -90,6 +96,22 @@ func (cpc *CloudProviderConfig) Generate(dependencies asset.Parents) error {
 			return err
 		}
 		cm.Data[cloudProviderConfigDataKey] = string(marshalled)
+	case azuretypes.Name:
+		creds, err := azureconfig.LoadCredentials()
+		if err != nil {
+			return err
+		}
+		azureConfig, err := azuremanifests.CloudProviderConfig{
+			GroupLocation:  installConfig.Config.Azure.Region,
+			ResourcePrefix: clusterID.InfraID,
+			SubscriptionID: creds.SubscriptionID,
+			TenantID:       creds.TenantID,
+			ResourceGroup:  clusterazure.ResourceGroupName(clusterID.InfraID),
+		}.JSON()
+		if err != nil {
+			return errors.Wrap(err, "could not create cloud provider config")
+		}
+		cm.Data[cloudProviderConfigDataKey] = azureConfig
 	case vspheretypes.Name:
 		vsphereConfig, err := vspheremanifests.CloudProviderConfig(installConfig.Config.Platform.VSphere)
 		if err != nil {
@@ -0,0 +1,53 @@
+package manifests
+
+import (
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+var (
+	imageContentSourcePolicyFilename = filepath.Join(manifestDir, "image-content-source-policy.yaml")
+	userCABundleFilename             = filepath.Join(manifestDir, "user-ca-bundle-config.yaml")
+)
+
+// imageContentSourcePolicy mirrors the ImageContentSourcePolicy of
+// operator.openshift.io/v1alpha1, which the machine-config operator renders
+// into the registries.conf of the nodes.
+type imageContentSourcePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec imageContentSourcePolicySpec `json:"spec"`
+}
+
+type imageContentSourcePolicySpec struct {
+	RepositoryDigestMirrors []repositoryDigestMirrors `json:"repositoryDigestMirrors"`
+}
+
+type repositoryDigestMirrors struct {
+	Source  string   `json:"source"`
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
+func newImageContentSourcePolicy(sources []types.ImageContentSource) *imageContentSourcePolicy {
+	policy := &imageContentSourcePolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "operator.openshift.io/v1alpha1",
+			Kind:       "ImageContentSourcePolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "image-content-sources",
+			// not namespaced
+		},
+	}
+	for _, source := range sources {
+		policy.Spec.RepositoryDigestMirrors = append(policy.Spec.RepositoryDigestMirrors, repositoryDigestMirrors{
+			Source:  source.Source,
+			Mirrors: source.Mirrors,
+		})
+	}
+	return policy
+}
@@ -0,0 +1,42 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestImageContentSourcePolicy(t *testing.T) {
+	sources := []types.ImageContentSource{
+		{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []string{"mirror.example.com:5000/ocp/release"},
+		},
+		{
+			Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+			Mirrors: []string{"mirror.example.com:5000/ocp/release", "backup.example.com/ocp/release"},
+		},
+	}
+	expectedYaml := `apiVersion: operator.openshift.io/v1alpha1
+kind: ImageContentSourcePolicy
+metadata:
+  creationTimestamp: null
+  name: image-content-sources
+spec:
+  repositoryDigestMirrors:
+  - mirrors:
+    - mirror.example.com:5000/ocp/release
+    source: quay.io/openshift-release-dev/ocp-release
+  - mirrors:
+    - mirror.example.com:5000/ocp/release
+    - backup.example.com/ocp/release
+    source: quay.io/openshift-release-dev/ocp-v4.0-art-dev
+`
+	actualYaml, err := yaml.Marshal(newImageContentSourcePolicy(sources))
+	if assert.NoError(t, err, "unexpected error") {
+		assert.Equal(t, expectedYaml, string(actualYaml), "unexpected yaml")
+	}
+}
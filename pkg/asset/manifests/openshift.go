@@ -9,12 +9,16 @@ import (
 
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	"github.com/openshift/installer/pkg/asset"
+	clusterazure "github.com/openshift/installer/pkg/asset/cluster/azure"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	"github.com/openshift/installer/pkg/asset/machines"
 	osmachine "github.com/openshift/installer/pkg/asset/machines/openstack"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
 	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
 	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
@@ -41,8 +45,10 @@ func (o *Openshift) Name() string {
 // Openshift asset
 func (o *Openshift) Dependencies() []asset.Asset {
 	return []asset.Asset{
+		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
 		&password.KubeadminPassword{},
+		&overlays.Overlays{},
 
 		&openshift.BindingDiscovery{},
 		&openshift.CloudCredsSecret{},
@@ -53,9 +59,11 @@ func (o *Openshift) Dependencies() []asset.Asset {
 
 // Generate generates the respective operator config.yml files
 func (o *Openshift) Generate(dependencies asset.Parents) error {
+	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	kubeadminPassword := &password.KubeadminPassword{}
-	dependencies.Get(installConfig, kubeadminPassword)
+	overlay := &overlays.Overlays{}
+	dependencies.Get(clusterID, installConfig, kubeadminPassword, overlay)
 	var cloudCreds cloudCredsSecretData
 	platform := installConfig.Config.Platform.Name()
 	switch platform {
@@ -73,6 +81,22 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 				Base64encodeSecretAccessKey: base64.StdEncoding.EncodeToString([]byte(creds.SecretAccessKey)),
 			},
 		}
+	case azuretypes.Name:
+		creds, err := azureconfig.LoadCredentials()
+		if err != nil {
+			return err
+		}
+		cloudCreds = cloudCredsSecretData{
+			Azure: &AzureCredsSecretData{
+				Base64encodeSubscriptionID: base64.StdEncoding.EncodeToString([]byte(creds.SubscriptionID)),
+				Base64encodeClientID:       base64.StdEncoding.EncodeToString([]byte(creds.ClientID)),
+				Base64encodeClientSecret:   base64.StdEncoding.EncodeToString([]byte(creds.ClientSecret)),
+				Base64encodeTenantID:       base64.StdEncoding.EncodeToString([]byte(creds.TenantID)),
+				Base64encodeResourcePrefix: base64.StdEncoding.EncodeToString([]byte(clusterID.InfraID)),
+				Base64encodeResourceGroup:  base64.StdEncoding.EncodeToString([]byte(clusterazure.ResourceGroupName(clusterID.InfraID))),
+				Base64encodeRegion:         base64.StdEncoding.EncodeToString([]byte(installConfig.Config.Azure.Region)),
+			},
+		}
 	case openstacktypes.Name:
 		opts := new(clientconfig.ClientOpts)
 		opts.Cloud = installConfig.Config.Platform.OpenStack.Cloud
@@ -131,7 +155,7 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 	}
 
 	switch platform {
-	case awstypes.Name, openstacktypes.Name, vspheretypes.Name:
+	case awstypes.Name, azuretypes.Name, openstacktypes.Name, vspheretypes.Name:
 		assetData["99_cloud-creds-secret.yaml"] = applyTemplateData(cloudCredsSecret.Files()[0].Data, templateData)
 		assetData["99_role-cloud-creds-secret-reader.yaml"] = applyTemplateData(roleCloudCredsSecretReader.Files()[0].Data, templateData)
 	}
@@ -147,6 +171,12 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		})
 	}
 
+	var err error
+	o.FileList, err = overlay.Apply(o.FileList)
+	if err != nil {
+		return err
+	}
+
 	asset.SortFiles(o.FileList)
 
 	return nil
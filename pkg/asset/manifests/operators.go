@@ -14,6 +14,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/overlays"
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/types"
@@ -60,6 +61,8 @@ func (m *Manifests) Dependencies() []asset.Asset {
 		&DNS{},
 		&Infrastructure{},
 		&Networking{},
+		&Proxy{},
+		&overlays.Overlays{},
 		&tls.RootCA{},
 		&tls.EtcdCA{},
 		&tls.EtcdSignerCertKey{},
@@ -97,8 +100,10 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	dns := &DNS{}
 	network := &Networking{}
 	infra := &Infrastructure{}
+	proxy := &Proxy{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(installConfig, ingress, dns, network, infra)
+	overlay := &overlays.Overlays{}
+	dependencies.Get(installConfig, ingress, dns, network, infra, proxy, overlay)
 
 	redactedConfig, err := redactedInstallConfig(*installConfig.Config)
 	if err != nil {
@@ -121,10 +126,39 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 	}
 	m.FileList = append(m.FileList, m.generateBootKubeManifests(dependencies)...)
 
+	if sources := installConfig.Config.ImageContentSources; len(sources) > 0 {
+		policyData, err := yaml.Marshal(newImageContentSourcePolicy(sources))
+		if err != nil {
+			return errors.Wrap(err, "failed to create the image content source policy")
+		}
+		m.FileList = append(m.FileList, &asset.File{
+			Filename: imageContentSourcePolicyFilename,
+			Data:     policyData,
+		})
+	}
+	if bundle := installConfig.Config.AdditionalTrustBundle; bundle != "" {
+		bundleData, err := yaml.Marshal(configMap("openshift-config", "user-ca-bundle", genericData{
+			"ca-bundle.crt": bundle,
+		}))
+		if err != nil {
+			return errors.Wrap(err, "failed to create openshift-config/user-ca-bundle configmap")
+		}
+		m.FileList = append(m.FileList, &asset.File{
+			Filename: userCABundleFilename,
+			Data:     bundleData,
+		})
+	}
+
 	m.FileList = append(m.FileList, ingress.Files()...)
 	m.FileList = append(m.FileList, dns.Files()...)
 	m.FileList = append(m.FileList, network.Files()...)
 	m.FileList = append(m.FileList, infra.Files()...)
+	m.FileList = append(m.FileList, proxy.Files()...)
+
+	m.FileList, err = overlay.Apply(m.FileList)
+	if err != nil {
+		return err
+	}
 
 	asset.SortFiles(m.FileList)
 
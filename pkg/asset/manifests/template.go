@@ -6,6 +6,17 @@ type AwsCredsSecretData struct {
 	Base64encodeSecretAccessKey string
 }
 
+// AzureCredsSecretData holds encoded credentials and is used to generate cloud-creds secret
+type AzureCredsSecretData struct {
+	Base64encodeSubscriptionID string
+	Base64encodeClientID       string
+	Base64encodeClientSecret   string
+	Base64encodeTenantID       string
+	Base64encodeResourcePrefix string
+	Base64encodeResourceGroup  string
+	Base64encodeRegion         string
+}
+
 // OpenStackCredsSecretData holds encoded credentials and is used to generate cloud-creds secret
 type OpenStackCredsSecretData struct {
 	Base64encodeCloudCreds string
@@ -25,6 +36,7 @@ type VSphereVirtualCenterCredsSecretData struct {
 
 type cloudCredsSecretData struct {
 	AWS       *AwsCredsSecretData
+	Azure     *AzureCredsSecretData
 	OpenStack *OpenStackCredsSecretData
 	VSphere   *VSphereCredsSecretData
 }
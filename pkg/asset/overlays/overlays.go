@@ -0,0 +1,165 @@
+// Package overlays applies user-provided patches to the generated manifests.
+package overlays
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	// overlaysDir is the directory of the asset directory holding the
+	// overlays.
+	overlaysDir = "overlays"
+)
+
+var (
+	_ asset.PersistentAsset = (*Overlays)(nil)
+)
+
+// Overlays is the asset holding the patches applied to the manifests
+// generated by the installer, which are read from the overlays directory of
+// the asset directory. The patches are kept in the state file, so they are
+// re-applied whenever the manifests are regenerated, and they are left in
+// the asset directory, so they can be re-used for other installs.
+type Overlays struct {
+	FileList []*asset.File
+}
+
+// Name returns a human friendly name for the asset.
+func (o *Overlays) Name() string {
+	return "Manifest Overlays"
+}
+
+// Dependencies returns all of the dependencies directly needed by the
+// Overlays asset.
+func (o *Overlays) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates an empty set of overlays. Overlays are only provided
+// by users.
+func (o *Overlays) Generate(dependencies asset.Parents) error {
+	o.FileList = []*asset.File{}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (o *Overlays) Files() []*asset.File {
+	return o.FileList
+}
+
+// Persistent marks the overlays as kept in the asset directory.
+func (o *Overlays) Persistent() {}
+
+// Load returns the overlays from disk.
+func (o *Overlays) Load(f asset.FileFetcher) (bool, error) {
+	var fileList []*asset.File
+	for _, ext := range []string{"*.yaml", "*.yml", "*.json"} {
+		files, err := f.FetchByPattern(filepath.Join(overlaysDir, ext))
+		if err != nil {
+			return false, err
+		}
+		fileList = append(fileList, files...)
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+
+	asset.SortFiles(fileList)
+	o.FileList = fileList
+	if _, err := o.overlays(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (o *Overlays) overlays() ([]*overlay, error) {
+	overlays := make([]*overlay, 0, len(o.FileList))
+	for _, file := range o.FileList {
+		overlay, err := parseOverlay(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid overlay %s", file.Filename)
+		}
+		overlays = append(overlays, overlay)
+	}
+	return overlays, nil
+}
+
+// Apply returns the given manifests with the overlays matching them applied,
+// in the order of the names of the overlay files. The patched manifests are
+// new files, the given ones are left as they are, since they may be shared
+// with other assets.
+func (o *Overlays) Apply(files []*asset.File) ([]*asset.File, error) {
+	overlays, err := o.overlays()
+	if err != nil || len(overlays) == 0 {
+		return files, err
+	}
+
+	patchedFiles := make([]*asset.File, len(files))
+	for i, file := range files {
+		patchedFiles[i] = file
+
+		m := &manifest{file: file}
+		m.doc, _ = decode(file.Data)
+
+		patched := false
+		for _, overlay := range overlays {
+			if !overlay.matches(m) {
+				continue
+			}
+			if m.doc == nil {
+				return nil, errors.Errorf("failed to apply overlay %s: %s is not a YAML or JSON document", overlay.filename, file.Filename)
+			}
+			logrus.Debugf("Applying overlay %s to %s", overlay.filename, file.Filename)
+			if err := overlay.apply(m); err != nil {
+				return nil, errors.Wrapf(err, "failed to apply overlay %s to %s", overlay.filename, file.Filename)
+			}
+			patched = true
+		}
+		if !patched {
+			continue
+		}
+
+		data, err := m.encode()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode %s", file.Filename)
+		}
+		patchedFiles[i] = &asset.File{Filename: file.Filename, Data: data}
+	}
+	return patchedFiles, nil
+}
+
+// Unmatched returns the names of the overlay files which match none of the
+// given manifests.
+func (o *Overlays) Unmatched(files []*asset.File) []string {
+	overlays, err := o.overlays()
+	if err != nil {
+		return nil
+	}
+
+	manifests := make([]*manifest, 0, len(files))
+	for _, file := range files {
+		m := &manifest{file: file}
+		m.doc, _ = decode(file.Data)
+		manifests = append(manifests, m)
+	}
+
+	var unmatched []string
+	for _, overlay := range overlays {
+		matched := false
+		for _, m := range manifests {
+			if overlay.matches(m) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, overlay.filename)
+		}
+	}
+	return unmatched
+}
@@ -0,0 +1,293 @@
+package overlays
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/mock"
+)
+
+const (
+	masterMachine0 = `apiVersion: machine.openshift.io/v1beta1
+kind: Machine
+metadata:
+  name: test-master-0
+  namespace: openshift-machine-api
+spec:
+  providerSpec:
+    value:
+      instanceType: m4.xlarge
+`
+	masterMachine1 = `apiVersion: machine.openshift.io/v1beta1
+kind: Machine
+metadata:
+  name: test-master-1
+  namespace: openshift-machine-api
+spec:
+  providerSpec:
+    value:
+      instanceType: m4.xlarge
+`
+	clusterConfig = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-config-v1
+  namespace: kube-system
+data:
+  install-config: test
+`
+)
+
+func testManifests() []*asset.File {
+	return []*asset.File{
+		{Filename: "manifests/cluster-config.yaml", Data: []byte(clusterConfig)},
+		{Filename: "openshift/99_openshift-cluster-api_master-machines-0.yaml", Data: []byte(masterMachine0)},
+		{Filename: "openshift/99_openshift-cluster-api_master-machines-1.yaml", Data: []byte(masterMachine1)},
+		{Filename: "manifests/infrastructure.json", Data: []byte(`{"apiVersion":"config.openshift.io/v1","kind":"Infrastructure","metadata":{"name":"cluster"},"status":{"platform":"AWS"}}`)},
+	}
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name      string
+		overlays  map[string]string
+		expected  map[string]string
+		unmatched []string
+		err       string
+	}{
+		{
+			name: "manifest",
+			overlays: map[string]string{
+				"overlays/cluster-config.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-config-v1
+  namespace: kube-system
+  labels:
+    team: ci
+data:
+  extra: value
+`,
+			},
+			expected: map[string]string{
+				"manifests/cluster-config.yaml": `apiVersion: v1
+data:
+  extra: value
+  install-config: test
+kind: ConfigMap
+metadata:
+  labels:
+    team: ci
+  name: cluster-config-v1
+  namespace: kube-system
+`,
+			},
+		},
+		{
+			name: "manifest in other namespace",
+			overlays: map[string]string{
+				"overlays/cluster-config.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-config-v1
+  namespace: default
+data:
+  extra: value
+`,
+			},
+			unmatched: []string{"overlays/cluster-config.yaml"},
+		},
+		{
+			name: "JSON patch of all masters",
+			overlays: map[string]string{
+				"overlays/masters.yaml": `target:
+  kind: Machine
+  name: "*-master-*"
+patch:
+- op: test
+  path: /spec/providerSpec/value/instanceType
+  value: m4.xlarge
+- op: replace
+  path: /spec/providerSpec/value/instanceType
+  value: m5.2xlarge
+`,
+			},
+			expected: map[string]string{
+				"openshift/99_openshift-cluster-api_master-machines-0.yaml": `apiVersion: machine.openshift.io/v1beta1
+kind: Machine
+metadata:
+  name: test-master-0
+  namespace: openshift-machine-api
+spec:
+  providerSpec:
+    value:
+      instanceType: m5.2xlarge
+`,
+				"openshift/99_openshift-cluster-api_master-machines-1.yaml": `apiVersion: machine.openshift.io/v1beta1
+kind: Machine
+metadata:
+  name: test-master-1
+  namespace: openshift-machine-api
+spec:
+  providerSpec:
+    value:
+      instanceType: m5.2xlarge
+`,
+			},
+		},
+		{
+			name: "merge patch of a file in order",
+			overlays: map[string]string{
+				"overlays/00-platform.json": `{"target":{"file":"manifests/infrastructure.json"},"patch":{"status":{"platform":"None"}}}`,
+				"overlays/01-platform.yaml": `target:
+  file: manifests/*.json
+patch:
+- op: add
+  path: /status/apiServerURL
+  value: https://api.example.com:6443
+`,
+			},
+			expected: map[string]string{
+				"manifests/infrastructure.json": `{
+  "apiVersion": "config.openshift.io/v1",
+  "kind": "Infrastructure",
+  "metadata": {
+    "name": "cluster"
+  },
+  "status": {
+    "apiServerURL": "https://api.example.com:6443",
+    "platform": "None"
+  }
+}`,
+			},
+		},
+		{
+			name: "failed JSON patch",
+			overlays: map[string]string{
+				"overlays/masters.yaml": `target:
+  name: test-master-0
+patch:
+- op: remove
+  path: /spec/replicas
+`,
+			},
+			err: `failed to apply overlay overlays/masters.yaml to openshift/99_openshift-cluster-api_master-machines-0.yaml: operation 0 (remove /spec/replicas): "replicas" not found`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &Overlays{}
+			for filename, data := range tc.overlays {
+				o.FileList = append(o.FileList, &asset.File{Filename: filename, Data: []byte(data)})
+			}
+			asset.SortFiles(o.FileList)
+
+			original := testManifests()
+			files, err := o.Apply(original)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, testManifests(), original, "the given files should not be modified")
+			for i, file := range files {
+				expected, ok := tc.expected[file.Filename]
+				if !ok {
+					expected = string(original[i].Data)
+				}
+				assert.Equal(t, expected, string(file.Data), "unexpected %s", file.Filename)
+			}
+			assert.Equal(t, tc.unmatched, o.Unmatched(files))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         []*asset.File
+		expectedFound bool
+		expectedError string
+	}{
+		{
+			name: "no overlays",
+		},
+		{
+			name: "valid overlays",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("kind: ConfigMap\nmetadata:\n  name: a\n")},
+			},
+			expectedFound: true,
+		},
+		{
+			name: "manifest without name",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("kind: ConfigMap\n")},
+			},
+			expectedError: "invalid overlay overlays/a.yaml: a manifest overlay needs a kind and a metadata.name",
+		},
+		{
+			name: "no target",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("patch: []\n")},
+			},
+			expectedError: "invalid overlay overlays/a.yaml: an overlay needs either a kind and a metadata.name, or a target and a patch",
+		},
+		{
+			name: "empty target",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("target: {}\npatch: []\n")},
+			},
+			expectedError: "invalid overlay overlays/a.yaml: the target of an overlay must select manifests",
+		},
+		{
+			name: "manifest with a list",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("kind: Pod\nmetadata:\n  name: a\nspec:\n  containers:\n  - name: b\n")},
+			},
+			expectedError: "invalid overlay overlays/a.yaml: merge patches replace lists entirely, use a JSON patch to change /spec/containers",
+		},
+		{
+			name: "merge patch with a list",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("target:\n  kind: Machine\npatch:\n  metadata:\n    labels:\n      a/b: c\n  spec:\n    taints: []\n")},
+			},
+			expectedError: "invalid overlay overlays/a.yaml: merge patches replace lists entirely, use a JSON patch to change /spec/taints",
+		},
+		{
+			name: "invalid operation",
+			files: []*asset.File{
+				{Filename: "overlays/a.yaml", Data: []byte("target:\n  kind: Machine\npatch:\n- op: delete\n  path: /spec\n")},
+			},
+			expectedError: `invalid overlay overlays/a.yaml: invalid operation 0: unsupported op "delete"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			fileFetcher.EXPECT().FetchByPattern("overlays/*.yaml").Return(tc.files, nil)
+			fileFetcher.EXPECT().FetchByPattern("overlays/*.yml").Return(nil, nil)
+			fileFetcher.EXPECT().FetchByPattern("overlays/*.json").Return(nil, nil)
+
+			o := &Overlays{}
+			found, err := o.Load(fileFetcher)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFound, found)
+			if tc.expectedFound {
+				assert.Equal(t, tc.files, o.FileList)
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package overlays
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// overlay is a patch and the manifests it applies to.
+type overlay struct {
+	filename string
+	target   target
+	// merge is a JSON merge patch, as specified by RFC 7386. It is nil for
+	// JSON patches. Merge patches may not contain lists, which RFC 7386
+	// replaces entirely instead of merging them like a strategic merge
+	// patch would.
+	merge map[string]interface{}
+	// operations are the operations of a JSON patch, as specified by
+	// RFC 6902.
+	operations []operation
+}
+
+// target selects manifests. The empty fields select all manifests. File
+// and Name may be glob patterns, as matched by path.Match.
+type target struct {
+	File       string `json:"file,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+
+	value interface{}
+}
+
+// manifest is a generated manifest being patched.
+type manifest struct {
+	file *asset.File
+	doc  interface{}
+}
+
+// parseOverlay parses an overlay file, which is either a manifest with at
+// least a kind and a name, which is merged into the manifest with the same
+// apiVersion, kind, name and namespace, or an explicit target with a patch:
+//
+//	target:
+//	  kind: Machine
+//	  name: "*-master-*"
+//	patch:
+//	- op: replace
+//	  path: /spec/providerSpec/value/instanceType
+//	  value: m5.2xlarge
+//
+// The patch of an explicit target is a JSON patch if it is a list, and a
+// JSON merge patch if it is an object. Merge patches, including manifest
+// overlays, are rejected if they contain lists, because they would silently
+// replace the generated lists; lists are patched with JSON patches.
+func parseOverlay(file *asset.File) (*overlay, error) {
+	doc, err := decode(file.Data)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+
+	o := &overlay{filename: file.Filename}
+	if _, isManifest := fields["kind"]; isManifest {
+		o.merge = fields
+		o.target = identity(fields)
+		if o.target.Kind == "" || o.target.Name == "" {
+			return nil, errors.New("a manifest overlay needs a kind and a metadata.name")
+		}
+		return o, validateMerge(o.merge)
+	}
+
+	var explicit struct {
+		Target *target          `json:"target"`
+		Patch  *json.RawMessage `json:"patch"`
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &explicit); err != nil {
+		return nil, err
+	}
+	if explicit.Target == nil || explicit.Patch == nil {
+		return nil, errors.New("an overlay needs either a kind and a metadata.name, or a target and a patch")
+	}
+	if *explicit.Target == (target{}) {
+		return nil, errors.New("the target of an overlay must select manifests")
+	}
+	for _, pattern := range []string{explicit.Target.File, explicit.Target.Name} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid target pattern %q", pattern)
+		}
+	}
+	o.target = *explicit.Target
+
+	patch, err := decodeJSON(*explicit.Patch)
+	if err != nil {
+		return nil, err
+	}
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		if err := validateMerge(p); err != nil {
+			return nil, err
+		}
+		o.merge = p
+	case []interface{}:
+		if err := json.Unmarshal(*explicit.Patch, &o.operations); err != nil {
+			return nil, errors.Wrap(err, "invalid JSON patch")
+		}
+		for i := range o.operations {
+			if err := o.operations[i].validate(); err != nil {
+				return nil, errors.Wrapf(err, "invalid operation %d", i)
+			}
+		}
+	default:
+		return nil, errors.New("the patch must be a list of JSON patch operations or a JSON merge patch object")
+	}
+	return o, nil
+}
+
+// matches returns true if the overlay applies to the manifest.
+func (o *overlay) matches(m *manifest) bool {
+	if o.target.File != "" {
+		if match, _ := path.Match(o.target.File, filepath.ToSlash(m.file.Filename)); !match {
+			return false
+		}
+	}
+	if o.target.APIVersion == "" && o.target.Kind == "" && o.target.Name == "" && o.target.Namespace == "" {
+		return true
+	}
+
+	fields, ok := m.doc.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	id := identity(fields)
+	if o.target.Name != "" {
+		if match, _ := path.Match(o.target.Name, id.Name); !match {
+			return false
+		}
+	}
+	return (o.target.APIVersion == "" || o.target.APIVersion == id.APIVersion) &&
+		(o.target.Kind == "" || o.target.Kind == id.Kind) &&
+		(o.target.Namespace == "" || o.target.Namespace == id.Namespace)
+}
+
+// apply applies the patch to the manifest.
+func (o *overlay) apply(m *manifest) error {
+	if o.merge != nil {
+		m.doc = mergePatch(m.doc, deepCopy(o.merge))
+		return nil
+	}
+	for i, op := range o.operations {
+		doc, err := op.apply(m.doc)
+		if err != nil {
+			return errors.Wrapf(err, "operation %d (%s %s)", i, op.Op, op.Path)
+		}
+		m.doc = doc
+	}
+	return nil
+}
+
+// identity returns the apiVersion, kind, name and namespace of a manifest.
+func identity(fields map[string]interface{}) target {
+	str := func(v interface{}) string {
+		s, _ := v.(string)
+		return s
+	}
+	id := target{
+		APIVersion: str(fields["apiVersion"]),
+		Kind:       str(fields["kind"]),
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		id.Name = str(metadata["name"])
+		id.Namespace = str(metadata["namespace"])
+	}
+	return id
+}
+
+// decode decodes YAML or JSON data, keeping numbers as they are written.
+func decode(data []byte) (interface{}, error) {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(raw)
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// encode encodes the manifest in the format of its file.
+func (m *manifest) encode() ([]byte, error) {
+	raw, err := json.Marshal(m.doc)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(m.file.Filename) == ".json" {
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, raw, "", "  "); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return yaml.JSONToYAML(raw)
+}
+
+// mergePatch applies a JSON merge patch to the document, as specified by
+// RFC 7386. Unlike a strategic merge patch, lists are replaced entirely.
+func mergePatch(doc, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		d = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+		d[k] = mergePatch(d[k], v)
+	}
+	return d
+}
+
+// validateMerge returns an error if the merge patch contains a list.
+func validateMerge(patch map[string]interface{}) error {
+	if p := listField(patch, ""); p != "" {
+		return errors.Errorf("merge patches replace lists entirely, use a JSON patch to change %s", p)
+	}
+	return nil
+}
+
+// listField returns the JSON pointer of the first list in the value, in the
+// order of the keys, or an empty string if there are none.
+func listField(v interface{}, prefix string) string {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			token := strings.Replace(strings.Replace(k, "~", "~0", -1), "/", "~1", -1)
+			if p := listField(value[k], prefix+"/"+token); p != "" {
+				return p
+			}
+		}
+	case []interface{}:
+		return prefix
+	}
+	return ""
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(value))
+		for i, e := range value {
+			c[i] = deepCopy(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+func (op *operation) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.Errorf("%s needs a value", op.Op)
+		}
+		value, err := decodeJSON(op.Value)
+		if err != nil {
+			return err
+		}
+		op.value = value
+	case "remove":
+	case "move", "copy":
+		if _, err := pointer(op.From); err != nil {
+			return errors.Wrap(err, "invalid from")
+		}
+	default:
+		return errors.Errorf("unsupported op %q", op.Op)
+	}
+	_, err := pointer(op.Path)
+	return errors.Wrap(err, "invalid path")
+}
+
+// apply applies the operation to the document, as specified by RFC 6902.
+func (op *operation) apply(doc interface{}) (interface{}, error) {
+	tokens, _ := pointer(op.Path)
+	switch op.Op {
+	case "add":
+		return add(doc, tokens, deepCopy(op.value))
+	case "remove":
+		return remove(doc, tokens)
+	case "replace":
+		if _, err := get(doc, tokens); err != nil {
+			return nil, err
+		}
+		doc, err := remove(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, tokens, deepCopy(op.value))
+	case "move", "copy":
+		from, _ := pointer(op.From)
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		value = deepCopy(value)
+		if op.Op == "move" {
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		}
+		return add(doc, tokens, value)
+	case "test":
+		value, err := get(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, op.value) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, errors.Errorf("unsupported op %q", op.Op)
+	}
+}
+
+// pointer returns the reference tokens of a JSON pointer, as specified by
+// RFC 6901.
+func pointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, errors.Errorf("JSON pointer %q does not start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func index(token string, length int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= length || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, errors.Errorf("invalid index %q", token)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			value, ok := d[token]
+			if !ok {
+				return nil, errors.Errorf("%q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, len(d))
+			if err != nil {
+				return nil, err
+			}
+			doc = d[i]
+		default:
+			return nil, errors.Errorf("%q not found", token)
+		}
+	}
+	return doc, nil
+}
+
+func add(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token, last := tokens[0], len(tokens) == 1
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		if last {
+			d[token] = value
+			return d, nil
+		}
+		child, ok := d[token]
+		if !ok {
+			return nil, errors.Errorf("%q not found", token)
+		}
+		child, err := add(child, tokens[1:], value)
+		d[token] = child
+		return d, err
+	case []interface{}:
+		if last {
+			if token == "-" {
+				return append(d, value), nil
+			}
+			i, err := index(token, len(d)+1)
+			if err != nil {
+				return nil, err
+			}
+			d = append(d, nil)
+			copy(d[i+1:], d[i:])
+			d[i] = value
+			return d, nil
+		}
+		i, err := index(token, len(d))
+		if err != nil {
+			return nil, err
+		}
+		child, err := add(d[i], tokens[1:], value)
+		d[i] = child
+		return d, err
+	default:
+		return nil, errors.Errorf("%q not found", token)
+	}
+}
+
+func remove(doc interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	token, last := tokens[0], len(tokens) == 1
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		child, ok := d[token]
+		if !ok {
+			return nil, errors.Errorf("%q not found", token)
+		}
+		if last {
+			delete(d, token)
+			return d, nil
+		}
+		child, err := remove(child, tokens[1:])
+		d[token] = child
+		return d, err
+	case []interface{}:
+		i, err := index(token, len(d))
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return append(d[:i], d[i+1:]...), nil
+		}
+		child, err := remove(d[i], tokens[1:])
+		d[i] = child
+		return d, err
+	default:
+		return nil, errors.Errorf("%q not found", token)
+	}
+}
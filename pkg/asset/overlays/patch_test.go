@@ -0,0 +1,179 @@
+package overlays
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// The examples of RFC 7386.
+	cases := []struct {
+		doc, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.patch, func(t *testing.T) {
+			doc, err := decodeJSON([]byte(tc.doc))
+			assert.NoError(t, err)
+			patch, err := decodeJSON([]byte(tc.patch))
+			assert.NoError(t, err)
+			actual, err := json.Marshal(mergePatch(doc, patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		err      string
+	}{
+		{
+			name:     "add member",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:     "add array element",
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:     "append array element",
+			doc:      `{"foo":["bar"]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":{"a":1}}]`,
+			expected: `{"foo":["bar",{"a":1}]}`,
+		},
+		{
+			name:     "remove",
+			doc:      `{"baz":"qux","foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:     "replace",
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:     "move",
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:     "copy",
+			doc:      `{"foo":{"bar":1}}`,
+			patch:    `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			expected: `{"baz":{"bar":2},"foo":{"bar":1}}`,
+		},
+		{
+			name:     "test",
+			doc:      `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:     "escaped pointer",
+			doc:      `{"metadata":{"annotations":{"a/b":"c","d~e":"f"}}}`,
+			patch:    `[{"op":"replace","path":"/metadata/annotations/a~1b","value":"g"},{"op":"remove","path":"/metadata/annotations/d~0e"}]`,
+			expected: `{"metadata":{"annotations":{"a/b":"g"}}}`,
+		},
+		{
+			name:  "failed test",
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   "operation 0 (test /baz): test failed",
+		},
+		{
+			name:  "replace missing member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"qux"}]`,
+			err:   `operation 0 (replace /baz): "baz" not found`,
+		},
+		{
+			name:  "add to missing parent",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   `operation 0 (add /baz/bat): "baz" not found`,
+		},
+		{
+			name:  "index out of bounds",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/2","value":"qux"}]`,
+			err:   `operation 0 (add /foo/2): invalid index "2"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var operations []operation
+			if !assert.NoError(t, json.Unmarshal([]byte(tc.patch), &operations)) {
+				return
+			}
+			for i := range operations {
+				if !assert.NoError(t, operations[i].validate()) {
+					return
+				}
+			}
+			doc, err := decodeJSON([]byte(tc.doc))
+			assert.NoError(t, err)
+			m := &manifest{doc: doc}
+			err = (&overlay{operations: operations}).apply(m)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			actual, err := json.Marshal(m.doc)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func TestInvalidOperations(t *testing.T) {
+	cases := []struct {
+		operation string
+		err       string
+	}{
+		{`{"op":"delete","path":"/a"}`, `unsupported op "delete"`},
+		{`{"op":"add","path":"/a"}`, "add needs a value"},
+		{`{"op":"remove","path":"a"}`, `invalid path: JSON pointer "a" does not start with /`},
+		{`{"op":"move","from":"a","path":"/a"}`, `invalid from: JSON pointer "a" does not start with /`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.operation, func(t *testing.T) {
+			var op operation
+			if !assert.NoError(t, json.Unmarshal([]byte(tc.operation), &op)) {
+				return
+			}
+			assert.EqualError(t, op.validate(), tc.err)
+		})
+	}
+}
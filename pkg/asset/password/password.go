@@ -16,7 +16,7 @@ var (
 
 // KubeadminPassword is the asset for the kubeadmin user password
 type KubeadminPassword struct {
-	Password     string
+	Password     string `secret:"true"`
 	PasswordHash []byte
 	File         *asset.File
 }
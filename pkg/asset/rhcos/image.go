@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/rhcos"
 	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	"github.com/openshift/installer/pkg/types/none"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -21,7 +22,7 @@ import (
 
 // Image is location of RHCOS image.
 // This stores the location of the image based on the platform.
-// eg. on AWS this contains ami-id, on Livirt this can be the URI for QEMU image etc.
+// eg. on AWS this contains ami-id, on Livirt this can be the URI for QEMU image, on Azure the URL of a VHD etc.
 type Image string
 
 var _ asset.Asset = (*Image)(nil)
@@ -57,11 +58,18 @@ func (i *Image) Generate(p asset.Parents) error {
 	switch config.Platform.Name() {
 	case aws.Name:
 		osimage, err = rhcos.AMI(ctx, config.Platform.AWS.Region)
+	case azure.Name:
+		osimage, err = rhcos.VHD(ctx)
 	case libvirt.Name:
 		osimage, err = rhcos.QEMU(ctx)
 	case openstack.Name:
 		osimage = "rhcos"
-	case none.Name, vsphere.Name:
+	case vsphere.Name:
+		// only installer-provisioned clusters import the OVA
+		if config.Platform.VSphere.Cluster != "" {
+			osimage, err = rhcos.OVA(ctx)
+		}
+	case none.Name:
 	default:
 		return errors.New("invalid Platform")
 	}
@@ -0,0 +1,228 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vincent-petithory/dataurl"
+)
+
+// Redacted replaces the secret values in redacted text.
+const Redacted = "REDACTED"
+
+// minSecretLength is the length under which secret values are not
+// redacted, since they would match too much unrelated text.
+const minSecretLength = 8
+
+// SecretValues returns the values of the fields of the given asset that are
+// tagged with `secret:"true"`, such as private keys and passwords. The tag
+// applies to string and []byte fields, and to all of the string and []byte
+// values nested in fields of other types.
+func SecretValues(a interface{}) []string {
+	var values []string
+	collectSecretValues(reflect.ValueOf(a), false, &values)
+	return values
+}
+
+func collectSecretValues(v reflect.Value, secret bool, values *[]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectSecretValues(v.Elem(), secret, values)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			collectSecretValues(v.Field(i), secret || f.Tag.Get("secret") == "true", values)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if secret && v.Len() > 0 {
+				*values = append(*values, string(v.Bytes()))
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectSecretValues(v.Index(i), secret, values)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			collectSecretValues(v.MapIndex(k), secret, values)
+		}
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			*values = append(*values, v.String())
+		}
+	}
+}
+
+// Redactor redacts secret values from text. The values are also redacted
+// when they are JSON-escaped or base64-encoded. A Redactor is safe for
+// concurrent use, and a nil Redactor has no secret values.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  map[string]bool
+	replacer *strings.Replacer
+}
+
+// NewRedactor returns a Redactor without any secret values.
+func NewRedactor() *Redactor {
+	return &Redactor{secrets: map[string]bool{}}
+}
+
+// Add adds secret values to be redacted.
+func (r *Redactor) Add(values ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added := false
+	for _, value := range values {
+		for _, v := range encodings(value) {
+			if len(v) < minSecretLength || r.secrets[v] {
+				continue
+			}
+			r.secrets[v] = true
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+
+	// Replace the longest values first, so that values containing others
+	// are replaced entirely.
+	secrets := make([]string, 0, len(r.secrets))
+	for s := range r.secrets {
+		secrets = append(secrets, s)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		if len(secrets[i]) != len(secrets[j]) {
+			return len(secrets[i]) > len(secrets[j])
+		}
+		return secrets[i] < secrets[j]
+	})
+	oldnew := make([]string, 0, 2*len(secrets))
+	for _, s := range secrets {
+		oldnew = append(oldnew, s, Redacted)
+	}
+	r.replacer = strings.NewReplacer(oldnew...)
+}
+
+// encodings returns the forms of the value that are redacted.
+func encodings(value string) []string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(value)
+	escaped := strings.TrimSpace(buf.String())
+	escaped = escaped[1 : len(escaped)-1]
+
+	return []string{
+		value,
+		escaped,
+		base64.StdEncoding.EncodeToString([]byte(value)),
+	}
+}
+
+// Redact returns the text with the secret values replaced by Redacted.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.replacer == nil {
+		return text
+	}
+	return r.replacer.Replace(text)
+}
+
+// RedactJSON returns the JSON document with the secret values redacted.
+// Besides the strings of the document, the secret values are redacted from
+// the decoded contents of base64-encoded strings and data URLs, which is how
+// []byte fields and Ignition files are encoded.
+func (r *Redactor) RedactJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	doc, _ = r.redactValue(doc)
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// ContainsJSON returns true if the JSON document contains any of the
+// secret values, as they would be redacted by RedactJSON.
+func (r *Redactor) ContainsJSON(data []byte) (bool, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false, err
+	}
+	_, redacted := r.redactValue(doc)
+	return redacted, nil
+}
+
+func (r *Redactor) redactValue(v interface{}) (interface{}, bool) {
+	redacted := false
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			var changed bool
+			value[k], changed = r.redactValue(e)
+			redacted = redacted || changed
+		}
+	case []interface{}:
+		for i, e := range value {
+			var changed bool
+			value[i], changed = r.redactValue(e)
+			redacted = redacted || changed
+		}
+	case string:
+		return r.redactString(value)
+	}
+	return v, redacted
+}
+
+func (r *Redactor) redactString(s string) (string, bool) {
+	if redacted := r.Redact(s); redacted != s {
+		return redacted, true
+	}
+
+	if strings.HasPrefix(s, "data:") {
+		u, err := dataurl.DecodeString(s)
+		if err != nil {
+			return s, false
+		}
+		redacted := r.Redact(string(u.Data))
+		if redacted == string(u.Data) {
+			return s, false
+		}
+		// Keep the media type and encoding of the data URL as they are.
+		prefix := s[:strings.Index(s, ",")+1]
+		if u.Encoding == dataurl.EncodingBase64 {
+			return prefix + base64.StdEncoding.EncodeToString([]byte(redacted)), true
+		}
+		return prefix + dataurl.EscapeString(redacted), true
+	}
+
+	if data, err := base64.StdEncoding.DecodeString(s); err == nil {
+		if redacted := r.Redact(string(data)); redacted != string(data) {
+			return base64.StdEncoding.EncodeToString([]byte(redacted)), true
+		}
+	}
+	return s, false
+}
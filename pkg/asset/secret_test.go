@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vincent-petithory/dataurl"
+)
+
+type secretHolder struct {
+	Name     string
+	Password string            `secret:"true"`
+	Keys     map[string][]byte `secret:"true"`
+	Nested   *secretHolder
+	Files    []*File
+}
+
+func TestSecretValues(t *testing.T) {
+	holder := &secretHolder{
+		Name:     "outer",
+		Password: "outer-password",
+		Keys: map[string][]byte{
+			"a": []byte("key-a"),
+		},
+		Nested: &secretHolder{
+			Name:     "inner",
+			Password: "inner-password",
+		},
+		Files: []*File{{Filename: "file", Data: []byte("data")}},
+	}
+	assert.ElementsMatch(t, []string{"outer-password", "key-a", "inner-password"}, SecretValues(holder))
+	assert.Empty(t, SecretValues(&File{Data: []byte("data")}))
+}
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor()
+	r.Add("short", "multi-line\nsecret-value", "secret-value")
+
+	assert.Equal(t, "password: REDACTED", r.Redact("password: secret-value"))
+	assert.Equal(t, "key: REDACTED", r.Redact("key: multi-line\nsecret-value"), "the longest value should be redacted first")
+	assert.Equal(t, `"REDACTED"`, r.Redact(`"multi-line\nsecret-value"`))
+	assert.Equal(t, "short", r.Redact("short"), "short values should not be redacted")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("secret-value"))
+	assert.Equal(t, "data: REDACTED", r.Redact("data: "+encoded))
+}
+
+func TestRedactJSON(t *testing.T) {
+	r := NewRedactor()
+	r.Add("secret-value")
+
+	doc := `{
+    "Number": 12345678901234567890,
+    "Password": "secret-value",
+    "Bytes": "` + base64.StdEncoding.EncodeToString([]byte("key: secret-value")) + `",
+    "Source": "` + dataurl.EncodeBytes([]byte("key: secret-value")) + `",
+    "Public": "public-value"
+}`
+	contains, err := r.ContainsJSON([]byte(doc))
+	assert.NoError(t, err)
+	assert.True(t, contains)
+
+	redacted, err := r.RedactJSON([]byte(doc))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.JSONEq(t, `{
+    "Number": 12345678901234567890,
+    "Password": "REDACTED",
+    "Bytes": "`+base64.StdEncoding.EncodeToString([]byte("key: REDACTED"))+`",
+    "Source": "`+dataurl.EncodeBytes([]byte("key: REDACTED"))+`",
+    "Public": "public-value"
+}`, string(redacted))
+
+	contains, err = r.ContainsJSON([]byte(`{"Public": "public-value"}`))
+	assert.NoError(t, err)
+	assert.False(t, contains)
+}
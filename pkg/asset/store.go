@@ -6,6 +6,12 @@ type Store interface {
 	// dependencies if necessary.
 	Fetch(Asset) error
 
+	// Load retrieves the state of the given asset from the target
+	// directory or the state file, without generating it or consuming the
+	// assets in the target directory. It returns false if the asset has
+	// not been generated yet.
+	Load(Asset) (bool, error)
+
 	// Destroy removes the asset from all its internal state and also from
 	// disk if possible.
 	Destroy(Asset) error
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	// encryptionStateKey is the key of the state file entry holding the
+	// parameters used to derive the encryption key. It cannot collide with
+	// the keys of the assets, which are Go type names.
+	encryptionStateKey = "$encryption"
+
+	// pbkdf2Iterations is the number of PBKDF2 iterations used to derive the
+	// encryption key of new state files.
+	pbkdf2Iterations = 100000
+)
+
+// Option configures an asset store.
+type Option func(*storeImpl)
+
+// WithEncryptionSecret encrypts the assets holding secret values in the state
+// file, with a key derived from the given secret. The secret is typically a
+// passphrase or the contents of a key file. Assets encrypted in the state
+// file cannot be loaded without the secret they were encrypted with.
+func WithEncryptionSecret(secret []byte) Option {
+	return func(s *storeImpl) {
+		s.encryptionSecret = secret
+	}
+}
+
+// WithRedactor adds the secret values of the assets loaded or generated by
+// the store to the given redactor, so that they can also be redacted from
+// logs.
+func WithRedactor(redactor *asset.Redactor) Option {
+	return func(s *storeImpl) {
+		s.redactor = redactor
+	}
+}
+
+// encryptionParams are the parameters used to derive the encryption key
+// from the encryption secret.
+type encryptionParams struct {
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+}
+
+// encryptedAsset is the state file entry of an encrypted asset.
+type encryptedAsset struct {
+	// Encrypted is the nonce followed by the AES-GCM sealed JSON of the
+	// asset.
+	Encrypted []byte `json:"$encrypted"`
+}
+
+// isEncrypted returns true if the state file entry is an encrypted asset.
+func isEncrypted(raw json.RawMessage) bool {
+	var e encryptedAsset
+	return json.Unmarshal(raw, &e) == nil && e.Encrypted != nil
+}
+
+// encryptionCipher returns the AEAD used to encrypt and decrypt assets. The
+// first call for a state file without encryption parameters generates them.
+func (s *storeImpl) encryptionCipher() (cipher.AEAD, error) {
+	if s.aead != nil {
+		return s.aead, nil
+	}
+	if s.encryptionSecret == nil {
+		return nil, errors.New("the state file holds encrypted assets, but no passphrase or key file was provided")
+	}
+
+	params := &encryptionParams{}
+	if raw, ok := s.stateFileAssets[encryptionStateKey]; ok {
+		if err := json.Unmarshal(raw, params); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the encryption parameters")
+		}
+	} else {
+		params.Salt = make([]byte, 16)
+		if _, err := io.ReadFull(rand.Reader, params.Salt); err != nil {
+			return nil, errors.Wrap(err, "failed to generate salt")
+		}
+		params.Iterations = pbkdf2Iterations
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		if s.stateFileAssets == nil {
+			s.stateFileAssets = map[string]json.RawMessage{}
+		}
+		s.stateFileAssets[encryptionStateKey] = json.RawMessage(raw)
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key(s.encryptionSecret, params.Salt, params.Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s.aead = aead
+	return aead, nil
+}
+
+// encrypt returns the state file entry of the asset with the given key and
+// JSON. The type name of the asset is authenticated with the JSON, so that
+// entries cannot be swapped.
+func (s *storeImpl) encrypt(key string, data []byte) (json.RawMessage, error) {
+	aead, err := s.encryptionCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	raw, err := json.Marshal(encryptedAsset{Encrypted: aead.Seal(nonce, nonce, data, []byte(key))})
+	return json.RawMessage(raw), err
+}
+
+// decrypt returns the JSON of the asset with the given key from its state
+// file entry, which is returned as is if it is not encrypted.
+func (s *storeImpl) decrypt(key string, raw json.RawMessage) ([]byte, error) {
+	var e encryptedAsset
+	if json.Unmarshal(raw, &e) != nil || e.Encrypted == nil {
+		return raw, nil
+	}
+
+	aead, err := s.encryptionCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Encrypted) < aead.NonceSize() {
+		return nil, errors.New("encrypted asset is truncated")
+	}
+	nonce, sealed := e.Encrypted[:aead.NonceSize()], e.Encrypted[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, sealed, []byte(key))
+	if err != nil {
+		return nil, errors.New("failed to decrypt, the passphrase or key file may not be the one the state file was encrypted with")
+	}
+	return data, nil
+}
+
+// stateEntry returns the state file entry of the asset with the given key
+// and JSON, which is encrypted if encryption is enabled and the asset holds
+// secret values.
+func (s *storeImpl) stateEntry(key string, data []byte) (json.RawMessage, error) {
+	if s.encryptionSecret == nil {
+		return json.RawMessage(data), nil
+	}
+	secret, err := s.redactor.ContainsJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if !secret {
+		return json.RawMessage(data), nil
+	}
+	return s.encrypt(key, data)
+}
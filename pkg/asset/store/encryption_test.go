@@ -0,0 +1,189 @@
+package store
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const testSecret = "very-secret-private-key"
+
+var secretGenerations int
+
+type testSecretAsset struct {
+	Key    []byte `secret:"true"`
+	Public string
+}
+
+func (a *testSecretAsset) Name() string {
+	return "secret"
+}
+
+func (a *testSecretAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testSecretAsset) Generate(asset.Parents) error {
+	secretGenerations++
+	a.Key = []byte(testSecret)
+	a.Public = "public"
+	return nil
+}
+
+// testSecretHolderAsset holds the secret of its parent without being
+// tagged as secret, like the Ignition configs do.
+type testSecretHolderAsset struct {
+	Config []byte
+}
+
+func (a *testSecretHolderAsset) Name() string {
+	return "holder"
+}
+
+func (a *testSecretHolderAsset) Dependencies() []asset.Asset {
+	return []asset.Asset{&testSecretAsset{}}
+}
+
+func (a *testSecretHolderAsset) Generate(parents asset.Parents) error {
+	secretGenerations++
+	secret := &testSecretAsset{}
+	parents.Get(secret)
+	a.Config = append([]byte("key: "), secret.Key...)
+	return nil
+}
+
+type testPublicAsset struct {
+	Value string
+}
+
+func (a *testPublicAsset) Name() string {
+	return "public"
+}
+
+func (a *testPublicAsset) Dependencies() []asset.Asset {
+	return []asset.Asset{&testSecretHolderAsset{}}
+}
+
+func (a *testPublicAsset) Generate(asset.Parents) error {
+	a.Value = "nothing to hide"
+	return nil
+}
+
+func TestStoreEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestStoreEncryption")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	secretGenerations = 0
+	s, err := newStore(dir, WithEncryptionSecret([]byte("passphrase")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(&testPublicAsset{})) {
+		return
+	}
+	assert.Equal(t, 2, secretGenerations)
+
+	state, err := ioutil.ReadFile(filepath.Join(dir, stateFileName))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(state), testSecret)
+	assert.NotContains(t, string(state), base64.StdEncoding.EncodeToString([]byte(testSecret)))
+	assert.NotContains(t, string(state), base64.StdEncoding.EncodeToString([]byte("key: "+testSecret)))
+	assert.Contains(t, string(state), "nothing to hide")
+	assert.Contains(t, string(state), encryptionStateKey)
+
+	t.Run("same secret", func(t *testing.T) {
+		secretGenerations = 0
+		s, err := newStore(dir, WithEncryptionSecret([]byte("passphrase")))
+		if !assert.NoError(t, err) {
+			return
+		}
+		holder := &testSecretHolderAsset{}
+		if !assert.NoError(t, s.Fetch(holder)) {
+			return
+		}
+		assert.Equal(t, 0, secretGenerations, "assets should be loaded from the state file")
+		assert.Equal(t, "key: "+testSecret, string(holder.Config))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		s, err := newStore(dir, WithEncryptionSecret([]byte("wrong")))
+		if !assert.NoError(t, err) {
+			return
+		}
+		err = s.Fetch(&testSecretHolderAsset{})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to decrypt")
+		}
+	})
+
+	t.Run("no secret", func(t *testing.T) {
+		s, err := newStore(dir)
+		if !assert.NoError(t, err) {
+			return
+		}
+		err = s.Fetch(&testSecretHolderAsset{})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "no passphrase or key file was provided")
+		}
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		redacted, err := RedactedState(dir, []asset.Asset{&testPublicAsset{}}, WithEncryptionSecret([]byte("passphrase")))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NotContains(t, string(redacted), "$encrypted")
+		assert.NotContains(t, string(redacted), base64.StdEncoding.EncodeToString([]byte(testSecret)))
+		assert.Contains(t, string(redacted), `"Key": "REDACTED"`)
+		assert.Contains(t, string(redacted), base64.StdEncoding.EncodeToString([]byte("key: "+asset.Redacted)))
+		assert.Contains(t, string(redacted), "nothing to hide")
+	})
+
+	t.Run("redacted without secret", func(t *testing.T) {
+		redacted, err := RedactedState(dir, []asset.Asset{&testPublicAsset{}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Contains(t, string(redacted), "$encrypted")
+		assert.Contains(t, string(redacted), "nothing to hide")
+	})
+}
+
+func TestRedactedStateWithoutEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestRedactedStateWithoutEncryption")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := newStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(&testPublicAsset{})) {
+		return
+	}
+	state, err := ioutil.ReadFile(filepath.Join(dir, stateFileName))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(state), base64.StdEncoding.EncodeToString([]byte(testSecret)))
+
+	redacted, err := RedactedState(dir, []asset.Asset{&testPublicAsset{}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(redacted), base64.StdEncoding.EncodeToString([]byte(testSecret)))
+	assert.Contains(t, string(redacted), base64.StdEncoding.EncodeToString([]byte("key: "+asset.Redacted)))
+}
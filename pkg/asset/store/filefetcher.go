@@ -1,46 +1,49 @@
 package store
 
 import (
-	"io/ioutil"
+	"path"
 	"path/filepath"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/storage"
 )
 
 type fileFetcher struct {
-	directory string
+	backend storage.Backend
 }
 
 // FetchByName returns the file with the given name.
 func (f *fileFetcher) FetchByName(name string) (*asset.File, error) {
-	data, err := ioutil.ReadFile(filepath.Join(f.directory, name))
+	data, err := f.backend.Read(filepath.ToSlash(name))
 	if err != nil {
 		return nil, err
 	}
 	return &asset.File{Filename: name, Data: data}, nil
 }
 
-// FetchByPattern returns the files whose name match the given regexp.
+// FetchByPattern returns the files whose name match the given glob pattern.
 func (f *fileFetcher) FetchByPattern(pattern string) (files []*asset.File, err error) {
-	matches, err := filepath.Glob(filepath.Join(f.directory, pattern))
+	names, err := f.backend.List()
 	if err != nil {
 		return nil, err
 	}
 
-	files = make([]*asset.File, 0, len(matches))
-	for _, path := range matches {
-		data, err := ioutil.ReadFile(path)
+	for _, name := range names {
+		match, err := path.Match(filepath.ToSlash(pattern), name)
 		if err != nil {
 			return nil, err
 		}
+		if !match {
+			continue
+		}
 
-		filename, err := filepath.Rel(f.directory, path)
+		data, err := f.backend.Read(name)
 		if err != nil {
 			return nil, err
 		}
 
 		files = append(files, &asset.File{
-			Filename: filename,
+			Filename: filepath.FromSlash(name),
 			Data:     data,
 		})
 	}
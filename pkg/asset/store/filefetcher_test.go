@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/storage"
 )
 
 func TestFetchByName(t *testing.T) {
@@ -59,7 +60,7 @@ func TestFetchByName(t *testing.T) {
 				}
 			}
 
-			f := &fileFetcher{directory: tempDir}
+			f := &fileFetcher{backend: storage.NewDir(tempDir)}
 			file, err := f.FetchByName(tt.input)
 			if err != nil {
 				if os.IsNotExist(err) && tt.expectFile == nil {
@@ -164,7 +165,7 @@ func TestFetchByPattern(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			f := &fileFetcher{directory: tempDir}
+			f := &fileFetcher{backend: storage.NewDir(tempDir)}
 			files, err := f.FetchByPattern(tt.input)
 			if err != nil {
 				t.Fatal(err)
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// RedactedState returns the state file in the given directory with the
+// secret values redacted, so that it can be shared in bug reports. The
+// secret values are those of the given assets, and of their dependencies,
+// found in the state file. Encrypted assets are decrypted and redacted if
+// the store is given the secret they were encrypted with, and are left
+// encrypted otherwise.
+func RedactedState(dir string, assets []asset.Asset, options ...Option) ([]byte, error) {
+	s, err := newStore(dir, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[reflect.Type]bool{}
+	var collect func(a asset.Asset) error
+	collect = func(a asset.Asset) error {
+		if seen[reflect.TypeOf(a)] {
+			return nil
+		}
+		seen[reflect.TypeOf(a)] = true
+		for _, d := range a.Dependencies() {
+			if err := collect(d); err != nil {
+				return err
+			}
+		}
+		raw, ok := s.stateFileAssets[reflect.TypeOf(a).String()]
+		if !ok || (isEncrypted(raw) && s.encryptionSecret == nil) {
+			return nil
+		}
+		stateFileAsset := reflect.New(reflect.TypeOf(a).Elem()).Interface().(asset.Asset)
+		return errors.Wrapf(s.loadAssetFromState(stateFileAsset), "failed to load asset %q from state file", a.Name())
+	}
+	for _, a := range assets {
+		if err := collect(a); err != nil {
+			return nil, err
+		}
+	}
+
+	redacted := make(map[string]json.RawMessage, len(s.stateFileAssets))
+	for key, raw := range s.stateFileAssets {
+		if key == encryptionStateKey || (isEncrypted(raw) && s.encryptionSecret == nil) {
+			redacted[key] = raw
+			continue
+		}
+		data, err := s.decrypt(key, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt %q", key)
+		}
+		data, err = s.redactor.RedactJSON(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to redact %q", key)
+		}
+		redacted[key] = json.RawMessage(data)
+	}
+	return json.MarshalIndent(redacted, "", "    ")
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// generation tracks the generation of a single asset.
+type generation struct {
+	// state is the state of the asset in the store.
+	state *assetState
+	// done is closed once the asset has been fetched.
+	done chan struct{}
+	// err is the error from fetching the asset. It must only be read
+	// after done is closed.
+	err error
+}
+
+// wait blocks until the asset has been fetched and returns any error from
+// fetching it.
+func (g *generation) wait() error {
+	<-g.done
+	return g.err
+}
+
+// copyTo populates the given asset with the fetched asset. It must only be
+// called after the fetch has succeeded.
+func (g *generation) copyTo(a asset.Asset) {
+	if g.state.asset == a {
+		return
+	}
+	reflect.ValueOf(a).Elem().Set(reflect.ValueOf(g.state.asset).Elem())
+}
+
+// scheduler walks the dependency graph of an asset in depth-first order,
+// generating the assets that need to be generated. Assets that implement
+// asset.ConcurrentAsset are handed off to a bounded pool of workers, so that
+// independent subgraphs are generated concurrently. All other assets are
+// generated by the walk itself, in the same order as a serial depth-first
+// walk, once their dependencies have been generated.
+type scheduler struct {
+	store *storeImpl
+	// generations is only accessed by the walk.
+	generations map[reflect.Type]*generation
+	// workers bounds the number of concurrent generations.
+	workers chan struct{}
+	wg      sync.WaitGroup
+}
+
+// schedule fetches the given asset and its dependencies, returning the
+// generation that completes once the asset has been fetched.
+func (s *scheduler) schedule(a asset.Asset, indent string) (*generation, error) {
+	logrus.Debugf("%sFetching %q...", indent, a.Name())
+
+	// Return immediately if the asset has been scheduled before,
+	// this is because we are doing a depth-first-search, it's guaranteed
+	// that we always schedule the parent before children, so we don't need
+	// to worry about invalidating anything in the cache.
+	if g, ok := s.generations[reflect.TypeOf(a)]; ok {
+		logrus.Debugf("%sReusing previously-fetched %q", indent, a.Name())
+		return g, nil
+	}
+
+	assetState, ok := s.store.assets[reflect.TypeOf(a)]
+	if !ok {
+		if _, err := s.store.load(a, ""); err != nil {
+			return nil, err
+		}
+		assetState = s.store.assets[reflect.TypeOf(a)]
+	}
+
+	g := &generation{
+		state: assetState,
+		done:  make(chan struct{}),
+	}
+	s.generations[reflect.TypeOf(a)] = g
+
+	if assetState.source != unfetched {
+		logrus.Debugf("%sReusing previously-fetched %q", indent, a.Name())
+		close(g.done)
+		return g, nil
+	}
+
+	// Re-generate the asset
+	dependencies := a.Dependencies()
+	dependencyGenerations := make([]*generation, len(dependencies))
+	for i, d := range dependencies {
+		dg, err := s.schedule(d, increaseIndent(indent))
+		if err != nil {
+			g.err = errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
+			close(g.done)
+			return nil, g.err
+		}
+		dependencyGenerations[i] = dg
+	}
+
+	_, concurrent := a.(asset.ConcurrentAsset)
+	generate := func() error {
+		parents := make(asset.Parents, len(dependencies))
+		for i, d := range dependencies {
+			if err := dependencyGenerations[i].wait(); err != nil {
+				return errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
+			}
+			dependencyGenerations[i].copyTo(d)
+			parents.Add(d)
+		}
+		if concurrent {
+			s.workers <- struct{}{}
+			defer func() { <-s.workers }()
+		}
+		logrus.Debugf("%sGenerating %q...", indent, a.Name())
+		if err := a.Generate(parents); err != nil {
+			return errors.Wrapf(err, "failed to generate asset %q", a.Name())
+		}
+		assetState.asset = a
+		assetState.source = generatedSource
+		return nil
+	}
+
+	if !concurrent {
+		g.err = generate()
+		close(g.done)
+		return g, g.err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		g.err = generate()
+		close(g.done)
+	}()
+	return g, nil
+}
@@ -1,16 +1,17 @@
 package store
 
 import (
+	"crypto/cipher"
 	"encoding/json"
-	"io/ioutil"
 	"os"
-	"path/filepath"
 	"reflect"
+	"runtime"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/storage"
 )
 
 const (
@@ -46,23 +47,39 @@ type assetState struct {
 
 // storeImpl is the implementation of Store.
 type storeImpl struct {
-	directory       string
+	// backend holds the state file and the files fetched from the target
+	// directory.
+	backend         storage.Backend
 	assets          map[reflect.Type]*assetState
 	stateFileAssets map[string]json.RawMessage
 	fileFetcher     asset.FileFetcher
+	// parallelism is the maximum number of assets generated concurrently.
+	parallelism int
+	// redactor holds the secret values of the assets.
+	redactor *asset.Redactor
+	// encryptionSecret is the secret from which the key used to encrypt the
+	// assets in the state file is derived. Assets are not encrypted if it
+	// is nil.
+	encryptionSecret []byte
+	aead             cipher.AEAD
 }
 
 // NewStore returns an asset store that implements the asset.Store interface.
-func NewStore(dir string) (asset.Store, error) {
-	return newStore(dir)
+func NewStore(dir string, options ...Option) (asset.Store, error) {
+	return newStore(dir, options...)
 }
 
-func newStore(dir string) (*storeImpl, error) {
+func newStore(dir string, options ...Option) (*storeImpl, error) {
 	store := &storeImpl{
-		directory:   dir,
-		fileFetcher: &fileFetcher{directory: dir},
+		backend:     storage.NewDir(dir),
 		assets:      map[reflect.Type]*assetState{},
+		parallelism: runtime.NumCPU(),
+		redactor:    asset.NewRedactor(),
 	}
+	for _, option := range options {
+		option(store)
+	}
+	store.fileFetcher = &fileFetcher{backend: store.backend}
 
 	if err := store.loadStateFile(); err != nil {
 		return nil, err
@@ -85,6 +102,29 @@ func (s *storeImpl) Fetch(a asset.Asset) error {
 	return nil
 }
 
+// Load retrieves the state of the given asset from the target directory,
+// or from the state file if it is not in the target directory. Unlike
+// Fetch, it neither generates the asset nor purges the target directory.
+func (s *storeImpl) Load(a asset.Asset) (bool, error) {
+	if wa, ok := a.(asset.WritableAsset); ok {
+		found, err := wa.Load(s.fileFetcher)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to load asset %q", a.Name())
+		}
+		if found {
+			s.redactor.Add(asset.SecretValues(a)...)
+			return true, nil
+		}
+	}
+	if !s.isAssetInState(a) {
+		return false, nil
+	}
+	if err := s.loadAssetFromState(a); err != nil {
+		return false, errors.Wrapf(err, "failed to load asset %q from state file", a.Name())
+	}
+	return true, nil
+}
+
 // Destroy removes the asset from all its internal state and also from
 // disk if possible.
 func (s *storeImpl) Destroy(a asset.Asset) error {
@@ -100,7 +140,7 @@ func (s *storeImpl) Destroy(a asset.Asset) error {
 	}
 
 	if wa, ok := a.(asset.WritableAsset); ok {
-		if err := asset.DeleteAssetFromDisk(wa, s.directory); err != nil {
+		if err := s.deleteAsset(wa); err != nil {
 			return err
 		}
 	}
@@ -113,23 +153,14 @@ func (s *storeImpl) Destroy(a asset.Asset) error {
 // DestroyState removes the state file from disk
 func (s *storeImpl) DestroyState() error {
 	s.stateFileAssets = nil
-	path := filepath.Join(s.directory, stateFileName)
-	err := os.Remove(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-	return nil
+	return s.backend.Delete(stateFileName)
 }
 
 // loadStateFile retrieves the state from the state file present in the given directory
 // and returns the assets map
 func (s *storeImpl) loadStateFile() error {
-	path := filepath.Join(s.directory, stateFileName)
 	assets := map[string]json.RawMessage{}
-	data, err := ioutil.ReadFile(path)
+	data, err := s.backend.Read(stateFileName)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -138,7 +169,7 @@ func (s *storeImpl) loadStateFile() error {
 	}
 	err = json.Unmarshal(data, &assets)
 	if err != nil {
-		return errors.Wrapf(err, "failed to unmarshal state file %q", path)
+		return errors.Wrapf(err, "failed to unmarshal state file %q", stateFileName)
 	}
 	s.stateFileAssets = assets
 	return nil
@@ -146,11 +177,20 @@ func (s *storeImpl) loadStateFile() error {
 
 // loadAssetFromState renders the asset object arguments from the state file contents.
 func (s *storeImpl) loadAssetFromState(a asset.Asset) error {
-	bytes, ok := s.stateFileAssets[reflect.TypeOf(a).String()]
+	key := reflect.TypeOf(a).String()
+	raw, ok := s.stateFileAssets[key]
 	if !ok {
 		return errors.Errorf("asset %q is not found in the state file", a.Name())
 	}
-	return json.Unmarshal(bytes, a)
+	bytes, err := s.decrypt(key, raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bytes, a); err != nil {
+		return err
+	}
+	s.redactor.Add(asset.SecretValues(a)...)
+	return nil
 }
 
 // isAssetInState tests whether the asset is in the state file.
@@ -164,6 +204,13 @@ func (s *storeImpl) saveStateFile() error {
 	if s.stateFileAssets == nil {
 		s.stateFileAssets = map[string]json.RawMessage{}
 	}
+	// Collect the secret values of all of the assets first, since they may
+	// be held by other assets, like the Ignition configs.
+	for _, v := range s.assets {
+		if v.source != unfetched {
+			s.redactor.Add(asset.SecretValues(v.asset)...)
+		}
+	}
 	for k, v := range s.assets {
 		if v.source == unfetched {
 			continue
@@ -172,62 +219,47 @@ func (s *storeImpl) saveStateFile() error {
 		if err != nil {
 			return err
 		}
-		s.stateFileAssets[k.String()] = json.RawMessage(data)
+		entry, err := s.stateEntry(k.String(), data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt %q", v.asset.Name())
+		}
+		s.stateFileAssets[k.String()] = entry
 	}
 	data, err := json.MarshalIndent(s.stateFileAssets, "", "    ")
 	if err != nil {
 		return err
 	}
 
-	path := filepath.Join(s.directory, stateFileName)
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
-	}
-	if err := ioutil.WriteFile(path, data, 0644); err != nil {
-		return err
-	}
-	return nil
+	return s.backend.Write(stateFileName, data)
 }
 
 // fetch populates the given asset, generating it and its dependencies if
-// necessary, and returns whether or not the asset had to be regenerated and
-// any errors.
+// necessary, and returns any errors. Dependencies that implement
+// asset.ConcurrentAsset are generated in the background, bounded by the
+// store's parallelism, while the remaining assets are generated in
+// depth-first order.
 func (s *storeImpl) fetch(a asset.Asset, indent string) error {
-	logrus.Debugf("%sFetching %q...", indent, a.Name())
-
-	assetState, ok := s.assets[reflect.TypeOf(a)]
-	if !ok {
-		if _, err := s.load(a, ""); err != nil {
-			return err
-		}
-		assetState = s.assets[reflect.TypeOf(a)]
+	parallelism := s.parallelism
+	if parallelism < 1 {
+		parallelism = 1
 	}
-
-	// Return immediately if the asset has been fetched before,
-	// this is because we are doing a depth-first-search, it's guaranteed
-	// that we always fetch the parent before children, so we don't need
-	// to worry about invalidating anything in the cache.
-	if assetState.source != unfetched {
-		logrus.Debugf("%sReusing previously-fetched %q", indent, a.Name())
-		reflect.ValueOf(a).Elem().Set(reflect.ValueOf(assetState.asset).Elem())
-		return nil
+	sched := &scheduler{
+		store:       s,
+		generations: map[reflect.Type]*generation{},
+		workers:     make(chan struct{}, parallelism),
 	}
 
-	// Re-generate the asset
-	dependencies := a.Dependencies()
-	parents := make(asset.Parents, len(dependencies))
-	for _, d := range dependencies {
-		if err := s.fetch(d, increaseIndent(indent)); err != nil {
-			return errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
-		}
-		parents.Add(d)
+	g, err := sched.schedule(a, indent)
+	if err == nil {
+		err = g.wait()
 	}
-	logrus.Debugf("%sGenerating %q...", indent, a.Name())
-	if err := a.Generate(parents); err != nil {
-		return errors.Wrapf(err, "failed to generate asset %q", a.Name())
+	// Wait for all of the background generations, even on failure, so that
+	// none of them are still writing to the store when we return.
+	sched.wg.Wait()
+	if err != nil {
+		return err
 	}
-	assetState.asset = a
-	assetState.source = generatedSource
+	g.copyTo(a)
 	return nil
 }
 
@@ -334,7 +366,7 @@ func (s *storeImpl) load(a asset.Asset, indent string) (*assetState, error) {
 
 // purge deletes the on-disk assets that are consumed already.
 // E.g., install-config.yaml will be deleted after fetching 'manifests'.
-// The target asset is excluded.
+// The target asset and the persistent assets are excluded.
 func (s *storeImpl) purge(excluded asset.WritableAsset) error {
 	for _, assetState := range s.assets {
 		if !assetState.presentOnDisk {
@@ -343,8 +375,11 @@ func (s *storeImpl) purge(excluded asset.WritableAsset) error {
 		if reflect.TypeOf(assetState.asset) == reflect.TypeOf(excluded) {
 			continue
 		}
+		if _, ok := assetState.asset.(asset.PersistentAsset); ok {
+			continue
+		}
 		logrus.Infof("Consuming %q from target directory", assetState.asset.Name())
-		if err := asset.DeleteAssetFromDisk(assetState.asset.(asset.WritableAsset), s.directory); err != nil {
+		if err := s.deleteAsset(assetState.asset.(asset.WritableAsset)); err != nil {
 			return err
 		}
 		assetState.presentOnDisk = false
@@ -352,6 +387,17 @@ func (s *storeImpl) purge(excluded asset.WritableAsset) error {
 	return nil
 }
 
+// deleteAsset removes the files of the asset from the backend.
+func (s *storeImpl) deleteAsset(a asset.WritableAsset) error {
+	logrus.Debugf("Purging asset %q from disk", a.Name())
+	for _, f := range a.Files() {
+		if err := s.backend.Delete(f.Filename); err != nil {
+			return errors.Wrap(err, "failed to remove file")
+		}
+	}
+	return nil
+}
+
 func increaseIndent(indent string) string {
 	return indent + "  "
 }
@@ -3,12 +3,19 @@ package store
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition/fragments"
+	"github.com/openshift/installer/pkg/asset/overlays"
+	"github.com/openshift/installer/pkg/storage"
 )
 
 var (
@@ -18,12 +25,20 @@ var (
 	generationLog []string
 	dependencies  map[reflect.Type][]asset.Asset
 	onDiskAssets  map[reflect.Type]bool
+
+	// generationLock guards generationLog against concurrently-generated
+	// assets.
+	generationLock sync.Mutex
+	// generationBarrier, when set, is called by concurrently-generated
+	// assets before they are added to the generation log.
+	generationBarrier func(asset.Asset) error
 )
 
 func clearAssetBehaviors() {
 	generationLog = []string{}
 	dependencies = map[reflect.Type][]asset.Asset{}
 	onDiskAssets = map[reflect.Type]bool{}
+	generationBarrier = nil
 }
 
 func dependenciesTestStoreAsset(a asset.Asset) []asset.Asset {
@@ -31,6 +46,13 @@ func dependenciesTestStoreAsset(a asset.Asset) []asset.Asset {
 }
 
 func generateTestStoreAsset(a asset.Asset) error {
+	if _, ok := a.(asset.ConcurrentAsset); ok && generationBarrier != nil {
+		if err := generationBarrier(a); err != nil {
+			return err
+		}
+	}
+	generationLock.Lock()
+	defer generationLock.Unlock()
 	generationLog = append(generationLog, a.Name())
 	return nil
 }
@@ -131,6 +153,54 @@ func (a *testStoreAssetD) Load(asset.FileFetcher) (bool, error) {
 	return loadTestStoreAsset(a)
 }
 
+type testStoreAssetE struct{}
+
+func (a *testStoreAssetE) Name() string {
+	return "e"
+}
+
+func (a *testStoreAssetE) Dependencies() []asset.Asset {
+	return dependenciesTestStoreAsset(a)
+}
+
+func (a *testStoreAssetE) Generate(asset.Parents) error {
+	return generateTestStoreAsset(a)
+}
+
+func (a *testStoreAssetE) Concurrent() {}
+
+type testStoreAssetF struct{}
+
+func (a *testStoreAssetF) Name() string {
+	return "f"
+}
+
+func (a *testStoreAssetF) Dependencies() []asset.Asset {
+	return dependenciesTestStoreAsset(a)
+}
+
+func (a *testStoreAssetF) Generate(asset.Parents) error {
+	return generateTestStoreAsset(a)
+}
+
+func (a *testStoreAssetF) Concurrent() {}
+
+type testStoreAssetG struct{}
+
+func (a *testStoreAssetG) Name() string {
+	return "g"
+}
+
+func (a *testStoreAssetG) Dependencies() []asset.Asset {
+	return dependenciesTestStoreAsset(a)
+}
+
+func (a *testStoreAssetG) Generate(asset.Parents) error {
+	return generateTestStoreAsset(a)
+}
+
+func (a *testStoreAssetG) Concurrent() {}
+
 func newTestStoreAsset(name string) asset.Asset {
 	switch name {
 	case "a":
@@ -141,6 +211,12 @@ func newTestStoreAsset(name string) asset.Asset {
 		return &testStoreAssetC{}
 	case "d":
 		return &testStoreAssetD{}
+	case "e":
+		return &testStoreAssetE{}
+	case "f":
+		return &testStoreAssetF{}
+	case "g":
+		return &testStoreAssetG{}
 	default:
 		return nil
 	}
@@ -265,8 +341,8 @@ func TestStoreFetch(t *testing.T) {
 			}
 			defer os.RemoveAll(dir)
 			store := &storeImpl{
-				directory: dir,
-				assets:    map[reflect.Type]*assetState{},
+				backend: storage.NewDir(dir),
+				assets:  map[reflect.Type]*assetState{},
 			}
 			assets := make(map[string]asset.Asset, len(tc.assets))
 			for name := range tc.assets {
@@ -387,3 +463,334 @@ func TestStoreFetchOnDiskAssets(t *testing.T) {
 		})
 	}
 }
+
+// TestStoreFetchConcurrent tests that the Fetch method of StoreImpl generates
+// concurrent assets in parallel while respecting their dependencies.
+func TestStoreFetchConcurrent(t *testing.T) {
+	cases := []struct {
+		name        string
+		assets      map[string][]string
+		target      string
+		parallelism int
+		barrier     []string
+		// expectedOrder lists the assets in each step of the generation.
+		// The order of assets within a step is not defined.
+		expectedOrder [][]string
+	}{
+		{
+			name: "independent concurrent dependencies",
+			assets: map[string][]string{
+				"a": {"e", "f"},
+				"e": {},
+				"f": {},
+			},
+			target:        "a",
+			parallelism:   2,
+			barrier:       []string{"e", "f"},
+			expectedOrder: [][]string{{"e", "f"}, {"a"}},
+		},
+		{
+			name: "shared concurrent dependency",
+			assets: map[string][]string{
+				"a": {"e", "f"},
+				"e": {"g"},
+				"f": {"g"},
+				"g": {},
+			},
+			target:        "a",
+			parallelism:   2,
+			barrier:       []string{"e", "f"},
+			expectedOrder: [][]string{{"g"}, {"e", "f"}, {"a"}},
+		},
+		{
+			name: "concurrent dependencies with a single worker",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {"e", "f"},
+				"e": {"g"},
+				"f": {"g"},
+				"g": {},
+			},
+			target:        "a",
+			parallelism:   1,
+			expectedOrder: [][]string{{"g"}, {"e", "f"}, {"b"}, {"a"}},
+		},
+		{
+			name: "serial asset between concurrent assets",
+			assets: map[string][]string{
+				"a": {"e"},
+				"e": {"b"},
+				"b": {"g"},
+				"g": {},
+			},
+			target:        "a",
+			parallelism:   4,
+			expectedOrder: [][]string{{"g"}, {"b"}, {"e"}, {"a"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearAssetBehaviors()
+			store := &storeImpl{
+				assets:      map[reflect.Type]*assetState{},
+				parallelism: tc.parallelism,
+			}
+			assets := make(map[string]asset.Asset, len(tc.assets))
+			for name := range tc.assets {
+				assets[name] = newTestStoreAsset(name)
+			}
+			for name, deps := range tc.assets {
+				dependenciesOfAsset := make([]asset.Asset, len(deps))
+				for i, d := range deps {
+					dependenciesOfAsset[i] = assets[d]
+				}
+				dependencies[reflect.TypeOf(assets[name])] = dependenciesOfAsset
+			}
+			if len(tc.barrier) > 0 {
+				var barrier sync.WaitGroup
+				barrier.Add(len(tc.barrier))
+				waiting := map[string]bool{}
+				for _, name := range tc.barrier {
+					waiting[name] = true
+				}
+				released := make(chan struct{})
+				go func() {
+					barrier.Wait()
+					close(released)
+				}()
+				generationBarrier = func(a asset.Asset) error {
+					if !waiting[a.Name()] {
+						return nil
+					}
+					barrier.Done()
+					select {
+					case <-released:
+						return nil
+					case <-time.After(10 * time.Second):
+						return errors.Errorf("timed out waiting for %v to be generated concurrently", tc.barrier)
+					}
+				}
+			}
+
+			err := store.fetch(assets[tc.target], "")
+			assert.NoError(t, err, "unexpected error")
+
+			generated := 0
+			for _, step := range tc.expectedOrder {
+				if !assert.True(t, len(generationLog) >= generated+len(step), "missing generations") {
+					return
+				}
+				assert.ElementsMatch(t, step, generationLog[generated:generated+len(step)])
+				generated += len(step)
+			}
+			assert.Equal(t, generated, len(generationLog), "unexpected generations")
+			for name := range tc.assets {
+				assert.Equal(t, generatedSource, store.assets[reflect.TypeOf(assets[name])].source, "asset %q was not generated", name)
+			}
+		})
+	}
+}
+
+// withBackend stores the state file in the given backend, and fetches the
+// files of the target directory from it, instead of the directory passed to
+// newStore.
+func withBackend(backend storage.Backend) Option {
+	return func(s *storeImpl) {
+		s.backend = backend
+	}
+}
+
+func TestStoreWithBackend(t *testing.T) {
+	backend := storage.NewMemory()
+
+	secretGenerations = 0
+	s, err := newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(&testPublicAsset{})) {
+		return
+	}
+	assert.Equal(t, 2, secretGenerations)
+	names, err := backend.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stateFileName}, names)
+
+	secretGenerations = 0
+	s, err = newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(&testPublicAsset{})) {
+		return
+	}
+	assert.Equal(t, 0, secretGenerations, "assets should be loaded from the state file in the backend")
+
+	assert.NoError(t, s.DestroyState())
+	names, err = backend.List()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestStoreLoad(t *testing.T) {
+	backend := storage.NewMemory()
+
+	s, err := newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	found, err := s.Load(&testPublicAsset{})
+	assert.NoError(t, err)
+	assert.False(t, found, "the asset should not be generated by Load")
+
+	if !assert.NoError(t, s.Fetch(&testPublicAsset{})) {
+		return
+	}
+
+	secretGenerations = 0
+	s, err = newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	found, err = s.Load(&testPublicAsset{})
+	assert.NoError(t, err)
+	assert.True(t, found, "the asset should be loaded from the state file")
+	assert.Equal(t, 0, secretGenerations)
+}
+
+// TestStoreDeleteWithBackend tests that the assets consumed or destroyed by
+// a store with a backend are deleted from the backend, not from the
+// working directory.
+func TestStoreDeleteWithBackend(t *testing.T) {
+	clearAssetBehaviors()
+	a := &testStoreAssetA{}
+	b := &testStoreAssetB{}
+	dependencies[reflect.TypeOf(b)] = []asset.Asset{a}
+	onDiskAssets[reflect.TypeOf(a)] = true
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "TestStoreDeleteWithBackend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	for _, name := range []string{"a", "b"} {
+		if err := ioutil.WriteFile(name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backend := storage.NewMemory()
+	for _, name := range []string{"a", "b"} {
+		if err := backend.Write(name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NoError(t, s.Fetch(b)) {
+		return
+	}
+	names, err := backend.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stateFileName, "b"}, names, "a should be consumed from the backend")
+
+	if !assert.NoError(t, s.Destroy(b)) {
+		return
+	}
+	names, err = backend.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stateFileName}, names, "b should be destroyed in the backend")
+
+	for _, name := range []string{"a", "b"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, "%s should be left in the working directory", name)
+	}
+}
+
+type testPersistentAsset struct {
+	testStoreAssetA
+}
+
+func (a *testPersistentAsset) Name() string {
+	return "persistent"
+}
+
+func (a *testPersistentAsset) Dependencies() []asset.Asset {
+	return nil
+}
+
+func (a *testPersistentAsset) Files() []*asset.File {
+	return fileTestStoreAsset(a)
+}
+
+func (a *testPersistentAsset) Load(asset.FileFetcher) (bool, error) {
+	return true, nil
+}
+
+func (a *testPersistentAsset) Persistent() {}
+
+// TestStorePurgeKeepsPersistentAssets tests that Fetch consumes the on-disk
+// dependencies of the target, except for the persistent ones.
+func TestStorePurgeKeepsPersistentAssets(t *testing.T) {
+	clearAssetBehaviors()
+	a := &testStoreAssetA{}
+	b := &testStoreAssetB{}
+	dependencies[reflect.TypeOf(a)] = []asset.Asset{b, &testPersistentAsset{}}
+	onDiskAssets[reflect.TypeOf(b)] = true
+
+	backend := storage.NewMemory()
+	for _, name := range []string{"b", "persistent"} {
+		if err := backend.Write(name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(a)) {
+		return
+	}
+	names, err := backend.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stateFileName, "persistent"}, names)
+}
+
+// TestStorePurgeKeepsUserInputs tests that the overlays and the Ignition
+// fragments are left in the target directory by Fetch.
+func TestStorePurgeKeepsUserInputs(t *testing.T) {
+	clearAssetBehaviors()
+	a := &testStoreAssetA{}
+	dependencies[reflect.TypeOf(a)] = []asset.Asset{&overlays.Overlays{}, &fragments.Fragments{}}
+
+	backend := storage.NewMemory()
+	for name, data := range map[string]string{
+		"ignition/master/chrony.ign": `{"ignition":{"version":"2.2.0"}}`,
+		"overlays/chrony.yaml":       "kind: ConfigMap\nmetadata:\n  name: chrony\n",
+	} {
+		if err := backend.Write(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := newStore("", withBackend(backend))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.Fetch(a)) {
+		return
+	}
+	names, err := backend.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stateFileName, "ignition/master/chrony.ign", "overlays/chrony.yaml"}, names)
+}
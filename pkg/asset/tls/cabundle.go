@@ -54,3 +54,6 @@ func (b *CertBundle) Files() []*asset.File {
 func (b *CertBundle) Load(asset.FileFetcher) (bool, error) {
 	return false, nil
 }
+
+// Concurrent marks bundle assets as safe for concurrent generation.
+func (b *CertBundle) Concurrent() {}
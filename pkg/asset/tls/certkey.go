@@ -26,7 +26,7 @@ type CertKeyInterface interface {
 // CertKey contains the private key and the cert.
 type CertKey struct {
 	CertRaw  []byte
-	KeyRaw   []byte
+	KeyRaw   []byte `secret:"true"`
 	FileList []*asset.File
 }
 
@@ -68,6 +68,9 @@ func (c *CertKey) Load(asset.FileFetcher) (bool, error) {
 	return false, nil
 }
 
+// Concurrent marks cert/key assets as safe for concurrent generation.
+func (c *CertKey) Concurrent() {}
+
 // AppendParentChoice dictates whether the parent's cert is to be added to the
 // cert.
 type AppendParentChoice bool
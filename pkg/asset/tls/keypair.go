@@ -15,7 +15,7 @@ type KeyPairInterface interface {
 
 // KeyPair contains a private key and a public key.
 type KeyPair struct {
-	Pvt      []byte
+	Pvt      []byte `secret:"true"`
 	Pub      []byte
 	FileList []*asset.File
 }
@@ -63,3 +63,6 @@ func (k *KeyPair) Private() []byte {
 func (k *KeyPair) Files() []*asset.File {
 	return k.FileList
 }
+
+// Concurrent marks key pair assets as safe for concurrent generation.
+func (k *KeyPair) Concurrent() {}
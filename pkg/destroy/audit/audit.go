@@ -0,0 +1,123 @@
+// Package audit records the resources deleted by the destroyers, as JSON
+// lines, to prove what the installer removed.
+//
+// The destroyers log their deletions with Deleted, through a logger given
+// the platform, region and filter of the resource with WithContext.  A Hook
+// added to the logger writes the records.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// contextField holds the Context of the logger.  The hook removes it
+	// from the entries, so it does not clutter the other logs.
+	contextField = "audit"
+
+	// resourceField holds the deleted resource.
+	resourceField = "resource"
+)
+
+// Context is what the destroyer knows about the resources it deletes
+// through a logger.
+type Context struct {
+	// Platform is the platform of the cluster (e.g. "aws").
+	Platform string
+
+	// Region is the region of the resources, if any.
+	Region string
+
+	// Filter is the filter which matched the resources, if any.
+	Filter map[string]string
+}
+
+// Record is a deleted resource.
+type Record struct {
+	Time     time.Time         `json:"time"`
+	Platform string            `json:"platform"`
+	Type     string            `json:"type"`
+	ID       string            `json:"id"`
+	Region   string            `json:"region,omitempty"`
+	Filter   map[string]string `json:"filter,omitempty"`
+}
+
+// resource is the value of resourceField.
+type resource struct {
+	resourceType string
+	id           string
+}
+
+func (r resource) String() string {
+	return fmt.Sprintf("%s %s", r.resourceType, r.id)
+}
+
+// WithContext returns a logger whose deletions are recorded with the given
+// context.
+func WithContext(logger logrus.FieldLogger, context Context) logrus.FieldLogger {
+	return logger.WithField(contextField, context)
+}
+
+// Deleted logs that the resource with the given type, like "ec2:subnet",
+// and ID, like a name or an ARN, was deleted.
+func Deleted(logger logrus.FieldLogger, resourceType string, id string) {
+	logger.WithField(resourceField, resource{resourceType: resourceType, id: id}).Info("Deleted")
+}
+
+// Hook writes the deletions logged with Deleted as JSON lines.  It must
+// fire before the hooks writing the logs, so they do not show the context.
+type Hook struct {
+	w io.Writer
+}
+
+// NewHook returns a hook writing the records to w.
+func NewHook(w io.Writer) *Hook {
+	return &Hook{w: w}
+}
+
+// Levels returns all levels, since the context is removed from all
+// entries.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire removes the context from the entry and, for deletions, writes their
+// record.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	context, hasContext := entry.Data[contextField].(Context)
+	if hasContext {
+		// the data is shared with the logger, so replace it instead of
+		// deleting the field
+		data := make(logrus.Fields, len(entry.Data)-1)
+		for key, value := range entry.Data {
+			if key != contextField {
+				data[key] = value
+			}
+		}
+		entry.Data = data
+	}
+
+	deleted, ok := entry.Data[resourceField].(resource)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(&Record{
+		Time:     entry.Time.UTC(),
+		Platform: context.Platform,
+		Type:     deleted.resourceType,
+		ID:       deleted.id,
+		Region:   context.Region,
+		Filter:   context.Filter,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(data, '\n'))
+	return err
+}
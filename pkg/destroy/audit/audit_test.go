@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook(t *testing.T) {
+	var logs, records bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &logs
+	logger.Formatter = &logrus.TextFormatter{DisableTimestamp: true}
+	logger.Level = logrus.DebugLevel
+	logger.AddHook(NewHook(&records))
+
+	contextLogger := WithContext(logger.WithField("arn", "arn:aws:ec2:us-east-1:123:vpc/vpc-a"), Context{
+		Platform: "aws",
+		Region:   "us-east-1",
+		Filter:   map[string]string{"kubernetes.io/cluster/test-abcde": "owned"},
+	})
+	contextLogger.Debug("Detached")
+	Deleted(contextLogger.WithField("gateway", "nat-a"), "ec2:natgateway", "nat-a")
+	Deleted(contextLogger, "ec2:vpc", "vpc-a")
+	Deleted(logger, "domain", "test-abcde-master-0")
+
+	assert.Equal(t, `level=debug msg=Detached arn="arn:aws:ec2:us-east-1:123:vpc/vpc-a"
+level=info msg=Deleted arn="arn:aws:ec2:us-east-1:123:vpc/vpc-a" gateway=nat-a resource="ec2:natgateway nat-a"
+level=info msg=Deleted arn="arn:aws:ec2:us-east-1:123:vpc/vpc-a" resource="ec2:vpc vpc-a"
+level=info msg=Deleted resource="domain test-abcde-master-0"
+`, logs.String())
+
+	lines := strings.Split(strings.TrimSpace(records.String()), "\n")
+	if !assert.Len(t, lines, 3) {
+		return
+	}
+	expected := []Record{
+		{Platform: "aws", Type: "ec2:natgateway", ID: "nat-a", Region: "us-east-1", Filter: map[string]string{"kubernetes.io/cluster/test-abcde": "owned"}},
+		{Platform: "aws", Type: "ec2:vpc", ID: "vpc-a", Region: "us-east-1", Filter: map[string]string{"kubernetes.io/cluster/test-abcde": "owned"}},
+		{Type: "domain", ID: "test-abcde-master-0"},
+	}
+	for i, line := range lines {
+		var record Record
+		if assert.NoError(t, json.Unmarshal([]byte(line), &record)) {
+			assert.False(t, record.Time.IsZero())
+			record.Time = expected[i].Time
+			assert.Equal(t, expected[i], record)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -22,13 +24,26 @@ import (
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	"github.com/openshift/installer/pkg/destroy/audit"
+	"github.com/openshift/installer/pkg/destroy/stuck"
 	"github.com/openshift/installer/pkg/version"
 )
 
 var (
 	exists = struct{}{}
+
+	// throttleBackoff is the backoff of the deletions throttled by AWS.
+	throttleBackoff = wait.Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.5,
+		Steps:    6,
+	}
 )
 
+// defaultParallelism is the default of ClusterUninstaller.Parallelism.
+const defaultParallelism = 10
+
 // Filter holds the key/value pairs for the tags we will be matching against.
 //
 // A resource matches the filter if all of the key/value pairs are in its tags.
@@ -56,6 +71,10 @@ type ClusterUninstaller struct {
 	Logger    logrus.FieldLogger
 	Region    string
 	ClusterID string
+
+	// Parallelism is the number of resources deleted at once.  It
+	// defaults to defaultParallelism.
+	Parallelism int
 }
 
 func (o *ClusterUninstaller) validate() error {
@@ -67,38 +86,27 @@ func (o *ClusterUninstaller) validate() error {
 
 // Run is the entrypoint to start the uninstall process
 func (o *ClusterUninstaller) Run() error {
+	return o.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the uninstall process until all of the resources are
+// deleted or the context is done.  In the latter case, it returns a
+// *stuck.Error with the resources which failed to be deleted.
+func (o *ClusterUninstaller) RunWithContext(ctx context.Context) error {
 	err := o.validate()
 	if err != nil {
 		return err
 	}
 
-	awsConfig := &aws.Config{Region: aws.String(o.Region)}
-
-	// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
-	awsSession, err := session.NewSession(awsConfig)
+	awsSession, err := o.session()
 	if err != nil {
 		return err
 	}
-	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
-		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
-		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
-	})
 
-	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
-		resourcegroupstaggingapi.New(awsSession),
-	}
-	tagClientNames := map[*resourcegroupstaggingapi.ResourceGroupsTaggingAPI]string{
-		tagClients[0]: o.Region,
-	}
-	if o.Region != "us-east-1" {
-		tagClient := resourcegroupstaggingapi.New(
-			awsSession, aws.NewConfig().WithRegion("us-east-1"),
-		)
-		tagClients = append(tagClients, tagClient)
-		tagClientNames[tagClient] = "us-east-1"
-	}
+	tagClients, tagClientNames := o.tagClients(awsSession)
 
 	deleted := map[string]struct{}{}
+	failures := stuck.NewTracker(o.Logger)
 	iamClient := iam.New(awsSession)
 	iamRoleSearch := &iamRoleSearch{
 		client:  iamClient,
@@ -111,36 +119,46 @@ func (o *ClusterUninstaller) Run() error {
 		logger:  o.Logger,
 	}
 
-	err = wait.PollImmediateInfinite(
+	parallelism := o.Parallelism
+	if parallelism == 0 {
+		parallelism = defaultParallelism
+	}
+
+	err = wait.PollImmediateUntil(
 		time.Second*10,
 		func() (done bool, err error) {
 			var loopError error
+			found := map[string]*resource{}
+			add := func(arnString string, filter Filter) error {
+				if _, ok := deleted[arnString]; ok {
+					return nil
+				}
+				if _, ok := found[arnString]; ok {
+					return nil
+				}
+				parsed, err := arn.Parse(arnString)
+				if err != nil {
+					return err
+				}
+				found[arnString] = &resource{arn: arnString, resourceType: resourceType(parsed), region: parsed.Region, filter: filter}
+				return nil
+			}
+
 			nextTagClients := tagClients[:0]
 			for _, tagClient := range tagClients {
 				matched := false
 				for _, filter := range o.Filters {
-					o.Logger.Debugf("search for and delete matching resources by tag in %s matching %#+v", tagClientNames[tagClient], filter)
-					tagFilters := make([]*resourcegroupstaggingapi.TagFilter, 0, len(filter))
-					for key, value := range filter {
-						tagFilters = append(tagFilters, &resourcegroupstaggingapi.TagFilter{
-							Key:    aws.String(key),
-							Values: []*string{aws.String(value)},
-						})
-					}
+					o.Logger.Debugf("search for matching resources by tag in %s matching %#+v", tagClientNames[tagClient], filter)
 					err = tagClient.GetResourcesPages(
-						&resourcegroupstaggingapi.GetResourcesInput{TagFilters: tagFilters},
+						&resourcegroupstaggingapi.GetResourcesInput{TagFilters: tagFiltersForFilter(filter)},
 						func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
 							for _, resource := range results.ResourceTagMappingList {
 								arn := *resource.ResourceARN
 								if _, ok := deleted[arn]; !ok {
 									matched = true
-									err := deleteARN(awsSession, arn, filter, o.Logger)
-									if err != nil {
-										err = errors.Wrapf(err, "deleting %s", arn)
-										o.Logger.Debug(err)
-										continue
+									if err := add(arn, filter); err != nil {
+										o.Logger.Debug(errors.Wrapf(err, "parsing %s", arn))
 									}
-									deleted[arn] = exists
 								}
 							}
 
@@ -178,25 +196,59 @@ func (o *ClusterUninstaller) Run() error {
 			}
 			arns = append(arns, userARNs...)
 
-			if len(arns) > 0 {
-				o.Logger.Debug("delete IAM roles and users")
-			}
 			for _, arn := range arns {
-				if _, ok := deleted[arn]; !ok {
-					err = deleteARN(awsSession, arn, nil, o.Logger)
-					if err != nil {
-						err = errors.Wrapf(err, "deleting %s", arn)
-						o.Logger.Debug(err)
-						loopError = err
-						continue
-					}
-					deleted[arn] = exists
+				if err := add(arn, nil); err != nil {
+					o.Logger.Debug(errors.Wrapf(err, "parsing %s", arn))
 				}
 			}
 
+			if len(found) == 0 {
+				return len(tagClients) == 0 && loopError == nil, nil
+			}
+
+			resources := make([]*resource, 0, len(found))
+			for _, r := range found {
+				resources = append(resources, r)
+			}
+			o.Logger.Debugf("deleting %d resources with up to %d at once", len(resources), parallelism)
+
+			var progress int32
+			deletedARNs, failed, blocked := deleteGraph(ctx, resources, parallelism, func(r *resource) error {
+				logger := audit.WithContext(o.Logger.WithField("arn", r.arn), audit.Context{
+					Platform: "aws",
+					Region:   r.region,
+					Filter:   r.filter,
+				})
+				err := deleteWithBackoff(awsSession, r, logger)
+				if err != nil {
+					failures.Failed(r.resourceType, r.arn, err)
+					return err
+				}
+				failures.Deleted(r.resourceType, r.arn)
+				logger.Debugf("Deleted %d of %d", atomic.AddInt32(&progress, 1), len(resources))
+				return nil
+			})
+			for _, arn := range deletedARNs {
+				deleted[arn] = exists
+			}
+			for arn, err := range failed {
+				o.Logger.Debug(errors.Wrapf(err, "deleting %s", arn))
+			}
+			for arn, err := range blocked {
+				o.Logger.Debug(errors.Wrapf(err, "not deleting %s", arn))
+			}
+			if remaining := len(failed) + len(blocked); remaining > 0 {
+				o.Logger.Infof("Deleted %d of %d resources found, retrying the remaining %d", len(deletedARNs), len(resources), remaining)
+				loopError = errors.Errorf("%d resources remaining", remaining)
+			}
+
 			return len(tagClients) == 0 && loopError == nil, nil
 		},
+		ctx.Done(),
 	)
+	if err == wait.ErrWaitTimeout && ctx.Err() != nil {
+		return failures.Error(ctx.Err())
+	}
 	if err != nil {
 		return err
 	}
@@ -209,6 +261,44 @@ func (o *ClusterUninstaller) Run() error {
 	return nil
 }
 
+// session returns the session used to delete the resources in the cluster
+// region.
+func (o *ClusterUninstaller) session() (*session.Session, error) {
+	awsConfig := &aws.Config{Region: aws.String(o.Region)}
+
+	// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
+	awsSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
+		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
+	})
+	return awsSession, nil
+}
+
+// tagClients returns the clients searching for tagged resources in the
+// cluster region and, since some global resources like Route 53 zones are
+// only found there, in us-east-1. The names of the clients are their
+// regions.
+func (o *ClusterUninstaller) tagClients(awsSession *session.Session) ([]*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, map[*resourcegroupstaggingapi.ResourceGroupsTaggingAPI]string) {
+	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
+		resourcegroupstaggingapi.New(awsSession),
+	}
+	tagClientNames := map[*resourcegroupstaggingapi.ResourceGroupsTaggingAPI]string{
+		tagClients[0]: o.Region,
+	}
+	if o.Region != "us-east-1" {
+		tagClient := resourcegroupstaggingapi.New(
+			awsSession, aws.NewConfig().WithRegion("us-east-1"),
+		)
+		tagClients = append(tagClients, tagClient)
+		tagClientNames[tagClient] = "us-east-1"
+	}
+	return tagClients, tagClientNames
+}
+
 func splitSlash(name string, input string) (base string, suffix string, err error) {
 	segments := strings.SplitN(input, "/", 2)
 	if len(segments) != 2 {
@@ -249,6 +339,17 @@ func tagsForFilter(filter Filter) []*ec2.Tag {
 	return tags
 }
 
+func tagFiltersForFilter(filter Filter) []*resourcegroupstaggingapi.TagFilter {
+	tagFilters := make([]*resourcegroupstaggingapi.TagFilter, 0, len(filter))
+	for key, value := range filter {
+		tagFilters = append(tagFilters, &resourcegroupstaggingapi.TagFilter{
+			Key:    aws.String(key),
+			Values: []*string{aws.String(value)},
+		})
+	}
+	return tagFilters
+}
+
 type iamRoleSearch struct {
 	client    *iam.IAM
 	filters   []Filter
@@ -445,6 +546,24 @@ func findPublicRoute53(client *route53.Route53, dnsName string, logger logrus.Fi
 	return "", nil
 }
 
+// deleteWithBackoff deletes the resource, backing off and trying again
+// while AWS throttles the requests.
+func deleteWithBackoff(session *session.Session, r *resource, logger logrus.FieldLogger) error {
+	var lastErr error
+	err := wait.ExponentialBackoff(throttleBackoff, func() (bool, error) {
+		lastErr = deleteARN(session, r.arn, r.filter, logger)
+		if lastErr != nil && request.IsErrorThrottle(errors.Cause(lastErr)) {
+			logger.Debug("Throttled, backing off")
+			return false, nil
+		}
+		return true, lastErr
+	})
+	if err == wait.ErrWaitTimeout {
+		return lastErr
+	}
+	return err
+}
+
 func deleteARN(session *session.Session, arnString string, filter Filter, logger logrus.FieldLogger) error {
 	logger = logger.WithField("arn", arnString)
 
@@ -521,7 +640,7 @@ func deleteEC2DHCPOptions(client *ec2.EC2, id string, logger logrus.FieldLogger)
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:dhcp-options", id)
 	return nil
 }
 
@@ -563,7 +682,7 @@ func deleteEC2Image(client *ec2.EC2, id string, filter Filter, logger logrus.Fie
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:image", id)
 	return nil
 }
 
@@ -578,7 +697,7 @@ func deleteEC2ElasticIP(client *ec2.EC2, id string, logger logrus.FieldLogger) e
 		return err
 	}
 
-	logger.Info("Released")
+	audit.Deleted(logger, "ec2:elastic-ip", id)
 	return nil
 }
 
@@ -618,7 +737,7 @@ func deleteEC2Instance(ec2Client *ec2.EC2, iamClient *iam.IAM, id string, logger
 				return err
 			}
 
-			logger.Info("Deleted")
+			audit.Deleted(logger, "ec2:instance", *instance.InstanceId)
 		}
 	}
 	return nil
@@ -633,12 +752,13 @@ func deleteEC2Instance(ec2Client *ec2.EC2, iamClient *iam.IAM, id string, logger
 // This code is a place to find specific objects like this which might be dangling.
 func (o *ClusterUninstaller) deleteUntaggedResources(awsSession *session.Session) error {
 	iamClient := iam.New(awsSession)
+	logger := audit.WithContext(o.Logger, audit.Context{Platform: "aws"})
 	masterProfile := fmt.Sprintf("%s-master-profile", o.ClusterID)
-	if err := deleteIAMInstanceProfileByName(iamClient, &masterProfile, o.Logger); err != nil {
+	if err := deleteIAMInstanceProfileByName(iamClient, &masterProfile, logger); err != nil {
 		return err
 	}
 	workerProfile := fmt.Sprintf("%s-worker-profile", o.ClusterID)
-	if err := deleteIAMInstanceProfileByName(iamClient, &workerProfile, o.Logger); err != nil {
+	if err := deleteIAMInstanceProfileByName(iamClient, &workerProfile, logger); err != nil {
 		return err
 	}
 
@@ -678,7 +798,7 @@ func deleteEC2InternetGateway(client *ec2.EC2, id string, logger logrus.FieldLog
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:internet-gateway", id)
 	return nil
 }
 
@@ -693,7 +813,7 @@ func deleteEC2NATGateway(client *ec2.EC2, id string, logger logrus.FieldLogger)
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:natgateway", id)
 	return nil
 }
 
@@ -780,7 +900,7 @@ func deleteEC2RouteTableObject(client *ec2.EC2, table *ec2.RouteTable, logger lo
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:route-table", *table.RouteTableId)
 	return nil
 }
 
@@ -861,7 +981,7 @@ func deleteEC2SecurityGroup(client *ec2.EC2, id string, logger logrus.FieldLogge
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:security-group", id)
 	return nil
 }
 
@@ -876,7 +996,7 @@ func deleteEC2Snapshot(client *ec2.EC2, id string, logger logrus.FieldLogger) er
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:snapshot", id)
 	return nil
 }
 
@@ -891,7 +1011,7 @@ func deleteEC2NetworkInterface(client *ec2.EC2, id string, logger logrus.FieldLo
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:network-interface", id)
 	return nil
 }
 
@@ -938,7 +1058,7 @@ func deleteEC2Subnet(client *ec2.EC2, id string, logger logrus.FieldLogger) erro
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:subnet", id)
 	return nil
 }
 
@@ -953,7 +1073,7 @@ func deleteEC2Volume(client *ec2.EC2, id string, logger logrus.FieldLogger) erro
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:volume", id)
 	return nil
 }
 
@@ -989,7 +1109,7 @@ func deleteEC2VPC(ec2Client *ec2.EC2, elbClient *elb.ELB, elbv2Client *elbv2.ELB
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:vpc", id)
 	return nil
 }
 
@@ -1001,7 +1121,7 @@ func deleteEC2VPCEndpoint(client *ec2.EC2, id string, logger logrus.FieldLogger)
 		return errors.Wrapf(err, "cannot delete VPC endpoint %s", id)
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "ec2:vpc-endpoint", id)
 	return nil
 }
 
@@ -1070,7 +1190,7 @@ func deleteElasticLoadBalancerClassic(client *elb.ELB, name string, logger logru
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "elasticloadbalancing:loadbalancer", name)
 	return nil
 }
 
@@ -1118,7 +1238,7 @@ func deleteElasticLoadBalancerTargetGroup(client *elbv2.ELBV2, arn arn.ARN, logg
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "elasticloadbalancing:targetgroup", arn.String())
 	return nil
 }
 
@@ -1173,7 +1293,7 @@ func deleteElasticLoadBalancerV2(client *elbv2.ELBV2, arn arn.ARN, logger logrus
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "elasticloadbalancing:loadbalancer", arn.String())
 	return nil
 }
 
@@ -1251,7 +1371,7 @@ func deleteIAMInstanceProfileByName(client *iam.IAM, name *string, logger logrus
 		}
 		return err
 	}
-	logger.WithField("InstanceProfileName", *name).Info("Deleted")
+	audit.Deleted(logger.WithField("InstanceProfileName", *name), "iam:instance-profile", *name)
 	return err
 }
 
@@ -1320,8 +1440,9 @@ func deleteIAMRole(client *iam.IAM, roleARN arn.ARN, logger logrus.FieldLogger)
 						logger.Debug(lastError)
 					}
 					lastError = errors.Wrapf(err, "deleting IAM role policy %s", *policy)
+					continue
 				}
-				logger.WithField("policy", *policy).Info("Deleted")
+				audit.Deleted(logger.WithField("policy", *policy), "iam:role-policy", fmt.Sprintf("%s/%s", name, *policy))
 			}
 
 			return !lastPage
@@ -1373,7 +1494,7 @@ func deleteIAMRole(client *iam.IAM, roleARN arn.ARN, logger logrus.FieldLogger)
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "iam:role", name)
 	return nil
 }
 
@@ -1392,8 +1513,9 @@ func deleteIAMUser(client *iam.IAM, id string, logger logrus.FieldLogger) error
 						logger.Debug(lastError)
 					}
 					lastError = errors.Wrapf(err, "deleting IAM user policy %s", *policy)
+					continue
 				}
-				logger.WithField("policy", *policy).Info("Deleted")
+				audit.Deleted(logger.WithField("policy", *policy), "iam:user-policy", fmt.Sprintf("%s/%s", id, *policy))
 			}
 
 			return !lastPage
@@ -1441,7 +1563,7 @@ func deleteIAMUser(client *iam.IAM, id string, logger logrus.FieldLogger) error
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "iam:user", id)
 	return nil
 }
 
@@ -1534,7 +1656,7 @@ func deleteRoute53(session *session.Session, arn arn.ARN, logger logrus.FieldLog
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "route53:hostedzone", id)
 	return nil
 }
 
@@ -1555,7 +1677,7 @@ func deleteRoute53RecordSet(client *route53.Route53, zoneID string, recordSet *r
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "route53:record-set", fmt.Sprintf("%s %s %s", zoneID, *recordSet.Type, *recordSet.Name))
 	return nil
 }
 
@@ -1578,6 +1700,6 @@ func deleteS3(session *session.Session, arn arn.ARN, logger logrus.FieldLogger)
 		return err
 	}
 
-	logger.Info("Deleted")
+	audit.Deleted(logger, "s3:bucket", arn.Resource)
 	return nil
 }
@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// dependencies maps resource types, as returned by resourceType, to the
+// types of the resources which must be gone before resources of that type
+// can be deleted.  For example, a subnet cannot be deleted while instances
+// or load balancers still have network interfaces in it.
+var dependencies = map[string][]string{
+	"ec2:dhcp-options":      {"ec2:vpc"},
+	"ec2:elastic-ip":        {"ec2:instance", "ec2:natgateway"},
+	"ec2:internet-gateway":  {"ec2:elastic-ip", "ec2:instance", "ec2:natgateway"},
+	"ec2:network-interface": {"ec2:instance", "ec2:natgateway", "elasticloadbalancing:loadbalancer"},
+	"ec2:security-group":    {"ec2:instance", "ec2:network-interface", "elasticloadbalancing:loadbalancer"},
+	"ec2:snapshot":          {"ec2:image"},
+	"ec2:subnet":            {"ec2:instance", "ec2:natgateway", "ec2:network-interface", "elasticloadbalancing:loadbalancer"},
+	"ec2:volume":            {"ec2:instance"},
+	"ec2:vpc": {
+		"ec2:instance",
+		"ec2:internet-gateway",
+		"ec2:natgateway",
+		"ec2:network-interface",
+		"ec2:route-table",
+		"ec2:security-group",
+		"ec2:subnet",
+		"elasticloadbalancing:loadbalancer",
+		"elasticloadbalancing:targetgroup",
+	},
+	"elasticloadbalancing:targetgroup": {"elasticloadbalancing:loadbalancer"},
+	"iam:instance-profile":             {"ec2:instance"},
+	"iam:role":                         {"ec2:instance"},
+}
+
+// resource is a node of the deletion graph.
+type resource struct {
+	arn          string
+	resourceType string
+
+	// region is the region of the resource, or empty for the global
+	// resources like IAM roles.
+	region string
+
+	// filter is the filter which matched the resource, if any.
+	filter Filter
+}
+
+// result is the outcome of deleting a resource.
+type result struct {
+	resource *resource
+	err      error
+}
+
+// deleteGraph deletes the resources, running up to parallelism deletions
+// at once.  A resource is only deleted once no resource of the types it
+// depends on is left, so the resources whose dependencies failed to be
+// deleted are not attempted.  No deletion is started once the context is
+// done.  deleteGraph returns the ARNs of the deleted resources, the errors
+// of the failed deletions and the reasons the other resources were not
+// attempted, keyed by ARN.
+func deleteGraph(ctx context.Context, resources []*resource, parallelism int, deleteResource func(*resource) error) (deleted []string, failed map[string]error, blocked map[string]error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	remaining := map[string]int{}
+	for _, r := range resources {
+		remaining[r.resourceType]++
+	}
+
+	ready := func(r *resource) bool {
+		for _, dependency := range dependencies[r.resourceType] {
+			if remaining[dependency] > 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	failed = map[string]error{}
+	pending := append([]*resource(nil), resources...)
+	results := make(chan result)
+	running := 0
+	for {
+		next := pending[:0]
+		for _, r := range pending {
+			if running < parallelism && ctx.Err() == nil && ready(r) {
+				running++
+				go func(r *resource) {
+					results <- result{resource: r, err: deleteResource(r)}
+				}(r)
+				continue
+			}
+			next = append(next, r)
+		}
+		pending = next
+
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		if res.err != nil {
+			failed[res.resource.arn] = res.err
+			continue
+		}
+		deleted = append(deleted, res.resource.arn)
+		remaining[res.resource.resourceType]--
+	}
+
+	blocked = map[string]error{}
+	for _, r := range pending {
+		if ctx.Err() != nil {
+			blocked[r.arn] = ctx.Err()
+			continue
+		}
+		blocked[r.arn] = errors.Errorf("waiting for the deletion of its dependencies (%s)", strings.Join(blockers(r, remaining), ", "))
+	}
+	return deleted, failed, blocked
+}
+
+// blockers returns the types of the remaining resources which r depends
+// on.
+func blockers(r *resource, remaining map[string]int) []string {
+	types := []string{}
+	for _, dependency := range dependencies[r.resourceType] {
+		if remaining[dependency] > 0 {
+			types = append(types, dependency)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
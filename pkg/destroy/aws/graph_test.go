@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependenciesAcyclic(t *testing.T) {
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var visit func(resourceType string, path []string)
+	visit = func(resourceType string, path []string) {
+		if visiting[resourceType] {
+			t.Fatalf("dependency cycle: %v", append(path, resourceType))
+		}
+		if visited[resourceType] {
+			return
+		}
+		visiting[resourceType] = true
+		for _, dependency := range dependencies[resourceType] {
+			visit(dependency, append(path, resourceType))
+		}
+		visiting[resourceType] = false
+		visited[resourceType] = true
+	}
+	for resourceType := range dependencies {
+		visit(resourceType, nil)
+	}
+}
+
+func TestDeleteGraph(t *testing.T) {
+	cases := []struct {
+		name      string
+		resources []*resource
+		fail      map[string]bool
+		deleted   []string
+		failed    []string
+		blocked   []string
+	}{
+		{
+			name: "ordered",
+			resources: []*resource{
+				{arn: "vpc", resourceType: "ec2:vpc"},
+				{arn: "subnet-a", resourceType: "ec2:subnet"},
+				{arn: "subnet-b", resourceType: "ec2:subnet"},
+				{arn: "instance-a", resourceType: "ec2:instance"},
+				{arn: "instance-b", resourceType: "ec2:instance"},
+				{arn: "zone", resourceType: "route53:hostedzone"},
+			},
+			deleted: []string{"instance-a", "instance-b", "subnet-a", "subnet-b", "vpc", "zone"},
+		},
+		{
+			name: "failed dependency",
+			resources: []*resource{
+				{arn: "vpc", resourceType: "ec2:vpc"},
+				{arn: "subnet", resourceType: "ec2:subnet"},
+				{arn: "instance", resourceType: "ec2:instance"},
+				{arn: "bucket", resourceType: "s3:bucket"},
+			},
+			fail:    map[string]bool{"subnet": true},
+			deleted: []string{"bucket", "instance"},
+			failed:  []string{"subnet"},
+			blocked: []string{"vpc"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var lock sync.Mutex
+			done := map[string]bool{}
+			deleted, failed, blocked := deleteGraph(context.Background(), tc.resources, 2, func(r *resource) error {
+				lock.Lock()
+				defer lock.Unlock()
+				for _, other := range tc.resources {
+					for _, dependency := range dependencies[r.resourceType] {
+						if other.resourceType == dependency && !done[other.arn] {
+							t.Errorf("%s deleted before %s", r.arn, other.arn)
+						}
+					}
+				}
+				if tc.fail[r.arn] {
+					return errors.New("injected failure")
+				}
+				done[r.arn] = true
+				return nil
+			})
+			assert.ElementsMatch(t, tc.deleted, deleted)
+			failedARNs := []string{}
+			for arn := range failed {
+				failedARNs = append(failedARNs, arn)
+			}
+			assert.ElementsMatch(t, tc.failed, failedARNs)
+			blockedARNs := []string{}
+			for arn := range blocked {
+				blockedARNs = append(blockedARNs, arn)
+			}
+			assert.ElementsMatch(t, tc.blocked, blockedARNs)
+		})
+	}
+}
+
+func TestDeleteGraphParallelism(t *testing.T) {
+	resources := []*resource{}
+	for _, arn := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		resources = append(resources, &resource{arn: arn, resourceType: "s3:bucket"})
+	}
+
+	var lock sync.Mutex
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	go func() {
+		for range resources {
+			release <- struct{}{}
+		}
+	}()
+	deleted, failed, blocked := deleteGraph(context.Background(), resources, 3, func(r *resource) error {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		<-release
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return nil
+	})
+	assert.Len(t, deleted, len(resources))
+	assert.Empty(t, failed)
+	assert.Empty(t, blocked)
+	assert.True(t, maxRunning <= 3, "ran %d deletions at once", maxRunning)
+}
+
+func TestDeleteGraphCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	resources := []*resource{
+		{arn: "instance", resourceType: "ec2:instance"},
+		{arn: "subnet", resourceType: "ec2:subnet"},
+	}
+	deleted, failed, blocked := deleteGraph(ctx, resources, 1, func(r *resource) error {
+		cancel()
+		return nil
+	})
+	assert.Equal(t, []string{"instance"}, deleted)
+	assert.Empty(t, failed)
+	assert.Equal(t, map[string]error{"subnet": context.Canceled}, blocked)
+}
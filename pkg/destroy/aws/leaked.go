@@ -0,0 +1,320 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// clusterTagPrefix prefixes the key of the tag holding the
+	// infrastructure ID of the cluster owning a resource.
+	clusterTagPrefix = "kubernetes.io/cluster/"
+
+	// ExpirationTag is the key of the optional tag holding the RFC 3339
+	// time after which a cluster is leaked, regardless of its age.
+	ExpirationTag = "expirationDate"
+)
+
+// LeakedCluster is a cluster found by a Janitor.
+type LeakedCluster struct {
+	// InfraID is the infrastructure ID of the cluster.
+	InfraID string
+
+	// Resources are the ARNs of the resources owned by the cluster.
+	Resources []string
+
+	// Created is the creation time of the oldest instance, NAT gateway or
+	// load balancer of the cluster.  It is zero when the cluster has none
+	// of them left.
+	Created time.Time
+
+	// Expiration is the latest expiration set on the resources of the
+	// cluster.  It is zero when none of them has one.
+	Expiration time.Time
+
+	// Leaked is whether the cluster qualifies for destruction.
+	Leaked bool
+
+	// Reason explains why the cluster is leaked or kept.
+	Reason string
+
+	// Err is the error destroying the cluster, if any.
+	Err error
+}
+
+// Janitor destroys the clusters leaked in a region.  A cluster is leaked
+// once its expiration passed or, without an expiration, once it is older
+// than MaxAge.  The clusters of unknown age without expiration are kept.
+type Janitor struct {
+	Region string
+	Logger logrus.FieldLogger
+
+	// MaxAge is the age after which clusters without an expiration are
+	// leaked.
+	MaxAge time.Duration
+
+	// Parallelism is the number of clusters destroyed at once.
+	Parallelism int
+
+	// DryRun only reports the leaked clusters, without destroying them.
+	DryRun bool
+
+	// Timeout is the maximum duration of the destruction of each leaked
+	// cluster.  Zero means no limit.
+	Timeout time.Duration
+}
+
+// Run finds the clusters in the region, and destroys the leaked ones.  It
+// returns all of the clusters found, sorted by infrastructure ID, with the
+// outcome of their destruction.
+func (j *Janitor) Run() ([]*LeakedCluster, error) {
+	uninstaller := &ClusterUninstaller{Region: j.Region, Logger: j.Logger}
+	awsSession, err := uninstaller.session()
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := []*resourcegroupstaggingapi.ResourceTagMapping{}
+	tagClients, tagClientNames := uninstaller.tagClients(awsSession)
+	for _, tagClient := range tagClients {
+		input := &resourcegroupstaggingapi.GetResourcesInput{}
+		if tagClientNames[tagClient] != j.Region {
+			// us-east-1 only holds the global Route 53 resources of the
+			// clusters of other regions
+			input.ResourceTypeFilters = []*string{aws.String("route53")}
+		}
+		j.Logger.Debugf("search for cluster resources in %s", tagClientNames[tagClient])
+		err = tagClient.GetResourcesPages(
+			input,
+			func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+				mappings = append(mappings, results.ResourceTagMappingList...)
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get resources in %s", tagClientNames[tagClient])
+		}
+	}
+
+	clusters := inRegion(groupByInfraID(mappings), j.Region)
+	ec2Client := ec2.New(awsSession)
+	elbv2Client := elbv2.New(awsSession)
+	for _, cluster := range clusters {
+		cluster.Created, err = clusterCreated(ec2Client, elbv2Client, cluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get the age of %s", cluster.InfraID)
+		}
+	}
+
+	now := time.Now()
+	leaked := []*LeakedCluster{}
+	for _, cluster := range clusters {
+		cluster.Leaked, cluster.Reason = j.leaked(cluster, now)
+		j.Logger.WithField("cluster", cluster.InfraID).Debug(cluster.Reason)
+		if cluster.Leaked {
+			leaked = append(leaked, cluster)
+		}
+	}
+	if j.DryRun {
+		return clusters, nil
+	}
+
+	parallelism := j.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for _, cluster := range leaked {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(cluster *LeakedCluster) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			logger := j.Logger.WithField("cluster", cluster.InfraID)
+			logger.Infof("Destroying leaked cluster: %s", cluster.Reason)
+			ctx := context.Background()
+			if j.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+				defer cancel()
+			}
+			cluster.Err = (&ClusterUninstaller{
+				Filters:   []Filter{{clusterTagPrefix + cluster.InfraID: "owned"}},
+				Region:    j.Region,
+				Logger:    logger,
+				ClusterID: cluster.InfraID,
+			}).RunWithContext(ctx)
+		}(cluster)
+	}
+	wg.Wait()
+
+	return clusters, nil
+}
+
+// leaked returns whether the cluster is leaked at the given time, and why.
+func (j *Janitor) leaked(cluster *LeakedCluster, now time.Time) (bool, string) {
+	if !cluster.Expiration.IsZero() {
+		if now.After(cluster.Expiration) {
+			return true, fmt.Sprintf("expired at %s", cluster.Expiration.Format(time.RFC3339))
+		}
+		return false, fmt.Sprintf("expires at %s", cluster.Expiration.Format(time.RFC3339))
+	}
+	if cluster.Created.IsZero() {
+		return false, "unknown age and no expiration"
+	}
+	age := now.Sub(cluster.Created).Round(time.Minute)
+	if age > j.MaxAge {
+		return true, fmt.Sprintf("%s old, more than %s", age, j.MaxAge)
+	}
+	return false, fmt.Sprintf("%s old, at most %s", age, j.MaxAge)
+}
+
+// groupByInfraID groups the resources owned by clusters by the
+// infrastructure ID of their cluster, sorted by infrastructure ID.
+func groupByInfraID(mappings []*resourcegroupstaggingapi.ResourceTagMapping) []*LeakedCluster {
+	clusters := map[string]*LeakedCluster{}
+	for _, mapping := range mappings {
+		var expiration time.Time
+		infraIDs := []string{}
+		for _, tag := range mapping.Tags {
+			switch {
+			case strings.HasPrefix(*tag.Key, clusterTagPrefix) && *tag.Value == "owned":
+				infraIDs = append(infraIDs, strings.TrimPrefix(*tag.Key, clusterTagPrefix))
+			case *tag.Key == ExpirationTag:
+				// ignore malformed expirations, the age still applies
+				expiration, _ = time.Parse(time.RFC3339, *tag.Value)
+			}
+		}
+
+		for _, infraID := range infraIDs {
+			cluster, ok := clusters[infraID]
+			if !ok {
+				cluster = &LeakedCluster{InfraID: infraID}
+				clusters[infraID] = cluster
+			}
+			cluster.Resources = append(cluster.Resources, *mapping.ResourceARN)
+			if expiration.After(cluster.Expiration) {
+				cluster.Expiration = expiration
+			}
+		}
+	}
+
+	sorted := make([]*LeakedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		sort.Strings(cluster.Resources)
+		sorted = append(sorted, cluster)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].InfraID < sorted[j].InfraID })
+	return sorted
+}
+
+// inRegion returns the clusters with at least one resource in the region.
+// The other clusters only own global resources, like the Route 53 zones of
+// the clusters of other regions, and are left to the janitors of their
+// regions.
+func inRegion(clusters []*LeakedCluster, region string) []*LeakedCluster {
+	kept := make([]*LeakedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		for _, resource := range cluster.Resources {
+			if parsed, err := arn.Parse(resource); err == nil && parsed.Region == region {
+				kept = append(kept, cluster)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// clusterCreated returns the creation time of the oldest instance, NAT
+// gateway or load balancer of the cluster, or the zero time if it has none.
+// VPCs and most other resources do not record their creation time, but the
+// NAT gateways and load balancers live as long as the VPC of the cluster, so
+// they still date the clusters whose instances are gone.
+func clusterCreated(ec2Client *ec2.EC2, elbv2Client *elbv2.ELBV2, cluster *LeakedCluster) (time.Time, error) {
+	var created time.Time
+	observe := func(t *time.Time) {
+		if t != nil && (created.IsZero() || t.Before(created)) {
+			created = *t
+		}
+	}
+	tagFilter := []*ec2.Filter{{
+		Name:   aws.String("tag:" + clusterTagPrefix + cluster.InfraID),
+		Values: []*string{aws.String("owned")},
+	}}
+
+	err := ec2Client.DescribeInstancesPages(
+		&ec2.DescribeInstancesInput{Filters: tagFilter},
+		func(results *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range results.Reservations {
+				for _, instance := range reservation.Instances {
+					observe(instance.LaunchTime)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return created, errors.Wrap(err, "describing instances")
+	}
+
+	err = ec2Client.DescribeNatGatewaysPages(
+		&ec2.DescribeNatGatewaysInput{Filter: tagFilter},
+		func(results *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			for _, gateway := range results.NatGateways {
+				observe(gateway.CreateTime)
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return created, errors.Wrap(err, "describing NAT gateways")
+	}
+
+	for _, lbARN := range loadBalancerV2ARNs(cluster.Resources) {
+		results, err := elbv2Client.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+			LoadBalancerArns: []*string{aws.String(lbARN)},
+		})
+		if err != nil {
+			if err, ok := err.(awserr.Error); ok && err.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+				// the tagging API still lists recently deleted resources
+				continue
+			}
+			return created, errors.Wrapf(err, "describing load balancer %s", lbARN)
+		}
+		for _, lb := range results.LoadBalancers {
+			observe(lb.CreatedTime)
+		}
+	}
+	return created, nil
+}
+
+// loadBalancerV2ARNs returns the ARNs of the application and network load
+// balancers among the given resource ARNs.  The classic load balancers are
+// skipped, since the elbv2 API does not describe them.
+func loadBalancerV2ARNs(resources []string) []string {
+	arns := []string{}
+	for _, resource := range resources {
+		parsed, err := arn.Parse(resource)
+		if err != nil || parsed.Service != "elasticloadbalancing" {
+			continue
+		}
+		if strings.HasPrefix(parsed.Resource, "loadbalancer/app/") || strings.HasPrefix(parsed.Resource, "loadbalancer/net/") {
+			arns = append(arns, resource)
+		}
+	}
+	return arns
+}
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func mapping(arn string, tags map[string]string) *resourcegroupstaggingapi.ResourceTagMapping {
+	m := &resourcegroupstaggingapi.ResourceTagMapping{ResourceARN: aws.String(arn)}
+	for key, value := range tags {
+		m.Tags = append(m.Tags, &resourcegroupstaggingapi.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return m
+}
+
+func TestGroupByInfraID(t *testing.T) {
+	clusters := groupByInfraID([]*resourcegroupstaggingapi.ResourceTagMapping{
+		mapping("arn:aws:ec2:us-east-1:123:vpc/vpc-b", map[string]string{
+			"kubernetes.io/cluster/b-xyz": "owned",
+			"expirationDate":              "2019-03-01T10:00:00Z",
+		}),
+		mapping("arn:aws:ec2:us-east-1:123:instance/i-a", map[string]string{
+			"kubernetes.io/cluster/a-xyz": "owned",
+			"Name":                        "a-xyz-master-0",
+		}),
+		mapping("arn:aws:ec2:us-east-1:123:subnet/subnet-b", map[string]string{
+			"kubernetes.io/cluster/b-xyz": "owned",
+			"expirationDate":              "2019-03-02T10:00:00Z",
+		}),
+		mapping("arn:aws:ec2:us-east-1:123:subnet/subnet-shared", map[string]string{
+			"kubernetes.io/cluster/a-xyz": "shared",
+		}),
+		mapping("arn:aws:s3:::unrelated", map[string]string{
+			"team": "installer",
+		}),
+	})
+
+	assert.Equal(t, []*LeakedCluster{
+		{
+			InfraID:   "a-xyz",
+			Resources: []string{"arn:aws:ec2:us-east-1:123:instance/i-a"},
+		},
+		{
+			InfraID:    "b-xyz",
+			Resources:  []string{"arn:aws:ec2:us-east-1:123:subnet/subnet-b", "arn:aws:ec2:us-east-1:123:vpc/vpc-b"},
+			Expiration: time.Date(2019, 3, 2, 10, 0, 0, 0, time.UTC),
+		},
+	}, clusters)
+}
+
+func TestInRegion(t *testing.T) {
+	clusters := inRegion([]*LeakedCluster{
+		{
+			InfraID:   "east-xyz",
+			Resources: []string{"arn:aws:ec2:us-east-1:123:vpc/vpc-a", "arn:aws:route53:::hostedzone/Z1"},
+		},
+		{
+			InfraID:   "west-xyz",
+			Resources: []string{"arn:aws:route53:::hostedzone/Z2"},
+		},
+		{
+			InfraID:   "other-xyz",
+			Resources: []string{"arn:aws:ec2:us-west-2:123:vpc/vpc-b"},
+		},
+	}, "us-east-1")
+
+	assert.Equal(t, []*LeakedCluster{
+		{
+			InfraID:   "east-xyz",
+			Resources: []string{"arn:aws:ec2:us-east-1:123:vpc/vpc-a", "arn:aws:route53:::hostedzone/Z1"},
+		},
+	}, clusters)
+}
+
+func TestJanitorLeaked(t *testing.T) {
+	now := time.Date(2019, 3, 10, 12, 0, 0, 0, time.UTC)
+	janitor := &Janitor{MaxAge: 24 * time.Hour}
+	cases := []struct {
+		name    string
+		cluster *LeakedCluster
+		leaked  bool
+		reason  string
+	}{
+		{
+			name:    "expired",
+			cluster: &LeakedCluster{Created: now.Add(-time.Hour), Expiration: now.Add(-time.Minute)},
+			leaked:  true,
+			reason:  "expired at 2019-03-10T11:59:00Z",
+		},
+		{
+			name:    "not expired",
+			cluster: &LeakedCluster{Created: now.Add(-48 * time.Hour), Expiration: now.Add(time.Hour)},
+			reason:  "expires at 2019-03-10T13:00:00Z",
+		},
+		{
+			name:    "old",
+			cluster: &LeakedCluster{Created: now.Add(-25 * time.Hour)},
+			leaked:  true,
+			reason:  "25h0m0s old, more than 24h0m0s",
+		},
+		{
+			name:    "young",
+			cluster: &LeakedCluster{Created: now.Add(-2 * time.Hour)},
+			reason:  "2h0m0s old, at most 24h0m0s",
+		},
+		{
+			name:    "unknown age",
+			cluster: &LeakedCluster{},
+			reason:  "unknown age and no expiration",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			leaked, reason := janitor.leaked(tc.cluster, now)
+			assert.Equal(t, tc.leaked, leaked)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestLoadBalancerV2ARNs(t *testing.T) {
+	assert.Equal(t, []string{
+		"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/net/a-xyz-int/1",
+		"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/a-xyz-app/2",
+	}, loadBalancerV2ARNs([]string{
+		"arn:aws:ec2:us-east-1:123:vpc/vpc-a",
+		"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/net/a-xyz-int/1",
+		"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/a-xyz-classic",
+		"arn:aws:elasticloadbalancing:us-east-1:123:targetgroup/a-xyz-aint/3",
+		"arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/a-xyz-app/2",
+		"not-an-arn",
+	}))
+}
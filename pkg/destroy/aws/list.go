@@ -0,0 +1,355 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/destroy/inventory"
+)
+
+// List returns the resources Run would delete, without deleting anything.
+// It walks the same searches as Run: the resources tagged in the cluster
+// region and in us-east-1, the tagged IAM roles and users, the untaggable
+// instance profiles, and the resources Run deletes along with the ones it
+// finds, like the load balancers and NAT gateways of the VPCs or the
+// records of the shared public zone.
+func (o *ClusterUninstaller) List() (*inventory.Inventory, error) {
+	err := o.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession, err := o.session()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := inventory.New()
+	tagClients, tagClientNames := o.tagClients(awsSession)
+	for _, tagClient := range tagClients {
+		for _, filter := range o.Filters {
+			o.Logger.Debugf("search for matching resources by tag in %s matching %#+v", tagClientNames[tagClient], filter)
+			var lastError error
+			err = tagClient.GetResourcesPages(
+				&resourcegroupstaggingapi.GetResourcesInput{TagFilters: tagFiltersForFilter(filter)},
+				func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+					for _, resource := range results.ResourceTagMappingList {
+						if err := listARN(awsSession, *resource.ResourceARN, resources, o.Logger); err != nil {
+							lastError = errors.Wrapf(err, "listing %s", *resource.ResourceARN)
+							return false
+						}
+					}
+					return !lastPage
+				},
+			)
+			if lastError != nil {
+				return nil, lastError
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "get tagged resources")
+			}
+		}
+	}
+
+	iamClient := iam.New(awsSession)
+	o.Logger.Debug("search for IAM roles")
+	arns, err := (&iamRoleSearch{client: iamClient, filters: o.Filters, logger: o.Logger}).arns()
+	if err != nil {
+		return nil, err
+	}
+	o.Logger.Debug("search for IAM users")
+	userARNs, err := (&iamUserSearch{client: iamClient, filters: o.Filters, logger: o.Logger}).arns()
+	if err != nil {
+		return nil, err
+	}
+	for _, arn := range append(arns, userARNs...) {
+		if err := listARN(awsSession, arn, resources, o.Logger); err != nil {
+			return nil, errors.Wrapf(err, "listing %s", arn)
+		}
+	}
+
+	o.Logger.Debug("search for untaggable resources")
+	for _, role := range []string{"master", "worker"} {
+		name := fmt.Sprintf("%s-%s-profile", o.ClusterID, role)
+		response, err := iamClient.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+		if err != nil {
+			if err.(awserr.Error).Code() == iam.ErrCodeNoSuchEntityException {
+				continue
+			}
+			return nil, errors.Wrapf(err, "get instance profile %s", name)
+		}
+		resources.Add("iam:instance-profile", *response.InstanceProfile.Arn)
+	}
+
+	return resources, nil
+}
+
+// resourceType returns the type of the resource with the given ARN, like
+// "ec2:instance".
+func resourceType(parsed arn.ARN) string {
+	if parsed.Service == "s3" {
+		return "s3:bucket"
+	}
+	return fmt.Sprintf("%s:%s", parsed.Service, strings.SplitN(parsed.Resource, "/", 2)[0])
+}
+
+// listARN adds the resource with the given ARN to the inventory, with the
+// resources deleteARN deletes along with it.
+func listARN(session *session.Session, arnString string, resources *inventory.Inventory, logger logrus.FieldLogger) error {
+	parsed, err := arn.Parse(arnString)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Service == "ec2" {
+		resourceType, id, err := splitSlash("resource", parsed.Resource)
+		if err != nil {
+			return err
+		}
+		switch resourceType {
+		case "instance":
+			return listEC2Instance(ec2.New(session), parsed, id, resources)
+		case "image":
+			if err := listEC2ImageSnapshots(ec2.New(session), parsed, id, resources); err != nil {
+				return err
+			}
+		case "vpc":
+			if err := listEC2VPCResources(session, parsed, id, resources); err != nil {
+				return err
+			}
+		}
+	}
+	if parsed.Service == "iam" && strings.HasPrefix(parsed.Resource, "role/") {
+		if err := listIAMRoleInstanceProfiles(iam.New(session), strings.TrimPrefix(parsed.Resource, "role/"), resources); err != nil {
+			return err
+		}
+	}
+	if parsed.Service == "route53" && strings.HasPrefix(parsed.Resource, "hostedzone/") {
+		if err := listRoute53SharedRecordSets(route53.New(session), strings.TrimPrefix(parsed.Resource, "hostedzone/"), resources, logger); err != nil {
+			return err
+		}
+	}
+
+	resources.Add(resourceType(parsed), arnString)
+	return nil
+}
+
+// listEC2Instance lists the instance, unless it is already terminated, and
+// its instance profile.
+func listEC2Instance(client *ec2.EC2, instanceARN arn.ARN, id string, resources *inventory.Inventory) error {
+	response, err := client.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		if err.(awserr.Error).Code() == "InvalidInstanceID.NotFound" {
+			return nil
+		}
+		return err
+	}
+
+	for _, reservation := range response.Reservations {
+		for _, instance := range reservation.Instances {
+			if *instance.State.Name == "terminated" {
+				continue
+			}
+			if instance.IamInstanceProfile != nil {
+				resources.Add("iam:instance-profile", *instance.IamInstanceProfile.Arn)
+			}
+			resources.Add(resourceType(instanceARN), instanceARN.String())
+		}
+	}
+	return nil
+}
+
+// listEC2ImageSnapshots lists the snapshots backing the image, which are
+// tagged and deleted with it.
+func listEC2ImageSnapshots(client *ec2.EC2, imageARN arn.ARN, id string, resources *inventory.Inventory) error {
+	response, err := client.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{&id},
+	})
+	if err != nil {
+		if err.(awserr.Error).Code() == "InvalidAMIID.NotFound" {
+			return nil
+		}
+		return err
+	}
+	for _, image := range response.Images {
+		for _, bdm := range image.BlockDeviceMappings {
+			if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
+				resources.Add("ec2:snapshot", ec2ARN(imageARN, "snapshot", *bdm.Ebs.SnapshotId))
+			}
+		}
+	}
+	return nil
+}
+
+// listEC2VPCResources lists the resources of the VPC that are deleted with
+// it, since they are not always tagged.
+func listEC2VPCResources(session *session.Session, vpcARN arn.ARN, vpc string, resources *inventory.Inventory) error {
+	err := elb.New(session).DescribeLoadBalancersPages(
+		&elb.DescribeLoadBalancersInput{},
+		func(results *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range results.LoadBalancerDescriptions {
+				if lb.VPCId != nil && *lb.VPCId == vpc {
+					lbARN := vpcARN
+					lbARN.Service = "elasticloadbalancing"
+					lbARN.Resource = "loadbalancer/" + *lb.LoadBalancerName
+					resources.Add("elasticloadbalancing:loadbalancer", lbARN.String())
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "describing classic load balancers")
+	}
+
+	err = elbv2.New(session).DescribeLoadBalancersPages(
+		&elbv2.DescribeLoadBalancersInput{},
+		func(results *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range results.LoadBalancers {
+				if lb.VpcId != nil && *lb.VpcId == vpc {
+					resources.Add("elasticloadbalancing:loadbalancer", *lb.LoadBalancerArn)
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "describing load balancers")
+	}
+
+	client := ec2.New(session)
+	vpcFilter := []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{aws.String(vpc)}}}
+
+	err = client.DescribeNatGatewaysPages(
+		&ec2.DescribeNatGatewaysInput{Filter: vpcFilter},
+		func(results *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			for _, gateway := range results.NatGateways {
+				resources.Add("ec2:natgateway", ec2ARN(vpcARN, "natgateway", *gateway.NatGatewayId))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "describing NAT gateways")
+	}
+
+	err = client.DescribeNetworkInterfacesPages(
+		&ec2.DescribeNetworkInterfacesInput{Filters: vpcFilter},
+		func(results *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, networkInterface := range results.NetworkInterfaces {
+				resources.Add("ec2:network-interface", ec2ARN(vpcARN, "network-interface", *networkInterface.NetworkInterfaceId))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "describing network interfaces")
+	}
+
+	err = client.DescribeRouteTablesPages(
+		&ec2.DescribeRouteTablesInput{Filters: vpcFilter},
+		func(results *ec2.DescribeRouteTablesOutput, lastPage bool) bool {
+			for _, table := range results.RouteTables {
+				resources.Add("ec2:route-table", ec2ARN(vpcARN, "route-table", *table.RouteTableId))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "describing route tables")
+	}
+
+	response, err := client.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{Filters: vpcFilter})
+	if err != nil {
+		return errors.Wrap(err, "describing VPC endpoints")
+	}
+	for _, endpoint := range response.VpcEndpoints {
+		resources.Add("ec2:vpc-endpoint", ec2ARN(vpcARN, "vpc-endpoint", *endpoint.VpcEndpointId))
+	}
+	return nil
+}
+
+// ec2ARN returns the ARN of the EC2 resource with the given type and ID, in
+// the partition, region and account of the given ARN.
+func ec2ARN(base arn.ARN, resourceType string, id string) string {
+	base.Service = "ec2"
+	base.Resource = fmt.Sprintf("%s/%s", resourceType, id)
+	return base.String()
+}
+
+// listIAMRoleInstanceProfiles lists the instance profiles of the role, which
+// are deleted with it.
+func listIAMRoleInstanceProfiles(client *iam.IAM, name string, resources *inventory.Inventory) error {
+	err := client.ListInstanceProfilesForRolePages(
+		&iam.ListInstanceProfilesForRoleInput{RoleName: &name},
+		func(results *iam.ListInstanceProfilesForRoleOutput, lastPage bool) bool {
+			for _, profile := range results.InstanceProfiles {
+				resources.Add("iam:instance-profile", *profile.Arn)
+			}
+			return !lastPage
+		},
+	)
+	return errors.Wrap(err, "listing IAM instance profiles")
+}
+
+// listRoute53SharedRecordSets lists the records of the shared public zone
+// which are deleted with the private zone of the cluster, since they have
+// the same type and name as one of its records.
+func listRoute53SharedRecordSets(client *route53.Route53, id string, resources *inventory.Inventory, logger logrus.FieldLogger) error {
+	sharedZoneID, err := getSharedHostedZone(client, id, logger)
+	if err != nil {
+		return err
+	}
+	if sharedZoneID == "" {
+		return nil
+	}
+
+	recordSetKey := func(recordSet *route53.ResourceRecordSet) string {
+		return fmt.Sprintf("%s %s", *recordSet.Type, *recordSet.Name)
+	}
+
+	private := map[string]struct{}{}
+	err = client.ListResourceRecordSetsPages(
+		&route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(id)},
+		func(results *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			for _, recordSet := range results.ResourceRecordSets {
+				if *recordSet.Type == "SOA" || *recordSet.Type == "NS" {
+					continue
+				}
+				private[recordSetKey(recordSet)] = exists
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return client.ListResourceRecordSetsPages(
+		&route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(sharedZoneID)},
+		func(results *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			for _, recordSet := range results.ResourceRecordSets {
+				key := recordSetKey(recordSet)
+				if _, ok := private[key]; ok {
+					resources.Add("route53:record-set", fmt.Sprintf("%s %s", sharedZoneID, key))
+				}
+			}
+			return !lastPage
+		},
+	)
+}
@@ -0,0 +1,332 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/audit"
+	"github.com/openshift/installer/pkg/destroy/inventory"
+	"github.com/openshift/installer/pkg/destroy/stuck"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// resourcesAPIVersion is the API version of Microsoft.Resources.
+	resourcesAPIVersion = "2018-05-01"
+
+	// dnsAPIVersion is the API version of Microsoft.Network/dnszones.
+	dnsAPIVersion = "2018-05-01"
+)
+
+// ClusterUninstaller holds the various options for the cluster we want to delete.
+type ClusterUninstaller struct {
+	// Credentials are the credentials of the service principal.  They
+	// are loaded with azureconfig.LoadCredentials when unset.
+	Credentials *azureconfig.Credentials
+
+	// InfraID is the ID of the cluster resources.  The resource groups
+	// tagged kubernetes.io_cluster.<InfraID>=owned are deleted.
+	InfraID string
+
+	// ResourceGroupName is the name of the resource group holding the
+	// cluster resources.
+	ResourceGroupName string
+
+	// BaseDomainResourceGroupName is the name of the resource group
+	// holding the DNS zone of the base domain.  The records of the
+	// cluster are only removed from the zone when it is set.
+	BaseDomainResourceGroupName string
+
+	// ClusterDomain is the domain of the cluster, like
+	// "mycluster.example.com".
+	ClusterDomain string
+
+	// Region is the region of the cluster, recorded in the audit log.
+	Region string
+
+	// ResourceManagerEndpoint and ActiveDirectoryEndpoint default to the
+	// endpoints of the public cloud.
+	ResourceManagerEndpoint string
+	ActiveDirectoryEndpoint string
+
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	Logger logrus.FieldLogger
+}
+
+// New returns an Azure destroyer from ClusterMetadata.
+func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (destroy.Destroyer, error) {
+	return &ClusterUninstaller{
+		InfraID:                     metadata.InfraID,
+		ResourceGroupName:           metadata.ClusterPlatformMetadata.Azure.ResourceGroupName,
+		BaseDomainResourceGroupName: metadata.ClusterPlatformMetadata.Azure.BaseDomainResourceGroupName,
+		ClusterDomain:               metadata.ClusterPlatformMetadata.Azure.ClusterDomain,
+		Region:                      metadata.ClusterPlatformMetadata.Azure.Region,
+		Logger:                      logger,
+	}, nil
+}
+
+// Run is the entrypoint to start the uninstall process.
+func (o *ClusterUninstaller) Run() error {
+	return o.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the uninstall process until all of the resources are
+// deleted or the context is done.  In the latter case, it returns a
+// *stuck.Error with the resources which failed to be deleted.
+func (o *ClusterUninstaller) RunWithContext(ctx context.Context) error {
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+	failures := stuck.NewTracker(o.Logger)
+	logger := audit.WithContext(o.Logger, audit.Context{
+		Platform: "azure",
+		Region:   o.Region,
+		Filter:   o.tagFilter(),
+	})
+
+	return failures.Retry(ctx, func() (bool, error) {
+		return o.deletePass(ctx, client, logger, failures)
+	})
+}
+
+// deletePass removes the records of the cluster from the base-domain zone,
+// then deletes the resource groups of the cluster.  It returns true when
+// all of them are gone, and an error only when retrying is not worth it.
+func (o *ClusterUninstaller) deletePass(ctx context.Context, client *client, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
+	done := true
+
+	recordSets, err := o.recordSets(ctx, client)
+	if err != nil {
+		if isAuthError(err) {
+			return false, err
+		}
+		logger.Debugf("list record sets: %v", err)
+		done = false
+	}
+	for _, id := range recordSets {
+		err := client.delete(ctx, id, url.Values{"api-version": {dnsAPIVersion}})
+		if err != nil && !isNotFound(err) {
+			logger.WithField("record set", id).Debugf("delete: %v", err)
+			failures.Failed("dns:record-set", id, err)
+			done = false
+			continue
+		}
+		failures.Deleted("dns:record-set", id)
+		audit.Deleted(logger, "dns:record-set", id)
+	}
+
+	resourceGroups, err := o.resourceGroups(ctx, client)
+	if err != nil {
+		if isAuthError(err) {
+			return false, err
+		}
+		logger.Debugf("list resource groups: %v", err)
+		done = false
+	}
+	for _, name := range resourceGroups {
+		logger.WithField("resource group", name).Info("Deleting")
+		err := client.delete(ctx, o.resourceGroupPath(client, name), url.Values{"api-version": {resourcesAPIVersion}})
+		if err == nil || isNotFound(err) {
+			failures.Deleted("resource-group", name)
+			if err == nil {
+				audit.Deleted(logger, "resource-group", name)
+			}
+			continue
+		}
+		logger.WithField("resource group", name).Debugf("delete: %v", err)
+		failures.Failed("resource-group", name, err)
+		done = false
+	}
+
+	return done, nil
+}
+
+// List returns the record sets and resource groups of the cluster, with
+// the resources in the resource groups.
+func (o *ClusterUninstaller) List() (*inventory.Inventory, error) {
+	ctx := context.Background()
+	client, err := o.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := inventory.New()
+	recordSets, err := o.recordSets(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range recordSets {
+		resources.Add("dns:record-set", id)
+	}
+
+	resourceGroups, err := o.resourceGroups(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range resourceGroups {
+		resources.Add("resource-group", name)
+		err := client.list(ctx, o.resourceGroupPath(client, name)+"/resources", url.Values{"api-version": {resourcesAPIVersion}}, func(value json.RawMessage) error {
+			var resource struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(value, &resource); err != nil {
+				return err
+			}
+			resources.Add(resource.Type, resource.ID)
+			return nil
+		})
+		if err != nil && !isNotFound(err) {
+			return nil, errors.Wrapf(err, "list the resources of %s", name)
+		}
+	}
+	return resources, nil
+}
+
+func (o *ClusterUninstaller) client() (*client, error) {
+	credentials := o.Credentials
+	if credentials == nil {
+		var err error
+		credentials, err = azureconfig.LoadCredentials()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newClient(credentials, o.ResourceManagerEndpoint, o.ActiveDirectoryEndpoint, o.HTTPClient), nil
+}
+
+// tagFilter returns the tag of the resource groups owned by the cluster.
+func (o *ClusterUninstaller) tagFilter() map[string]string {
+	if o.InfraID == "" {
+		return nil
+	}
+	return map[string]string{fmt.Sprintf("kubernetes.io_cluster.%s", o.InfraID): "owned"}
+}
+
+func (o *ClusterUninstaller) resourceGroupPath(client *client, name string) string {
+	return fmt.Sprintf("subscriptions/%s/resourcegroups/%s", url.PathEscape(client.credentials.SubscriptionID), url.PathEscape(name))
+}
+
+// resourceGroups returns the names of the resource groups of the cluster:
+// the one named ResourceGroupName and the ones tagged as owned by the
+// cluster.
+func (o *ClusterUninstaller) resourceGroups(ctx context.Context, client *client) ([]string, error) {
+	names := []string{}
+	seen := map[string]bool{}
+	if o.ResourceGroupName != "" {
+		response, err := client.do(ctx, "HEAD", o.resourceGroupPath(client, o.ResourceGroupName), url.Values{"api-version": {resourcesAPIVersion}})
+		if err == nil {
+			drain(response)
+			names = append(names, o.ResourceGroupName)
+			seen[strings.ToLower(o.ResourceGroupName)] = true
+		} else if !isNotFound(err) {
+			return nil, errors.Wrapf(err, "get resource group %s", o.ResourceGroupName)
+		}
+	}
+
+	for key, value := range o.tagFilter() {
+		query := url.Values{
+			"api-version": {resourcesAPIVersion},
+			"$filter":     {fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", key, value)},
+		}
+		err := client.list(ctx, fmt.Sprintf("subscriptions/%s/resourcegroups", url.PathEscape(client.credentials.SubscriptionID)), query, func(value json.RawMessage) error {
+			var resourceGroup struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(value, &resourceGroup); err != nil {
+				return err
+			}
+			// resource group names are case-insensitive
+			if !seen[strings.ToLower(resourceGroup.Name)] {
+				seen[strings.ToLower(resourceGroup.Name)] = true
+				names = append(names, resourceGroup.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "list resource groups")
+		}
+	}
+	return names, nil
+}
+
+// recordSets returns the IDs of the record sets of the cluster domain in
+// the zones of the base-domain resource group.  The SOA and NS records at
+// the apex of a zone are left, since Azure does not allow deleting them.
+func (o *ClusterUninstaller) recordSets(ctx context.Context, client *client) ([]string, error) {
+	if o.BaseDomainResourceGroupName == "" || o.ClusterDomain == "" {
+		return nil, nil
+	}
+	clusterDomain := strings.ToLower(strings.TrimSuffix(o.ClusterDomain, "."))
+	query := url.Values{"api-version": {dnsAPIVersion}}
+
+	zones := []string{}
+	zonesPath := fmt.Sprintf("subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnszones", url.PathEscape(client.credentials.SubscriptionID), url.PathEscape(o.BaseDomainResourceGroupName))
+	err := client.list(ctx, zonesPath, query, func(value json.RawMessage) error {
+		var zone struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(value, &zone); err != nil {
+			return err
+		}
+		if inDomain(clusterDomain, strings.ToLower(zone.Name)) {
+			zones = append(zones, zone.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list DNS zones")
+	}
+
+	ids := []string{}
+	for _, zone := range zones {
+		err := client.list(ctx, zone+"/recordsets", query, func(value json.RawMessage) error {
+			var recordSet struct {
+				ID         string `json:"id"`
+				Name       string `json:"name"`
+				Type       string `json:"type"`
+				Properties struct {
+					FQDN string `json:"fqdn"`
+				} `json:"properties"`
+			}
+			if err := json.Unmarshal(value, &recordSet); err != nil {
+				return err
+			}
+			fqdn := strings.ToLower(strings.TrimSuffix(recordSet.Properties.FQDN, "."))
+			if !inDomain(fqdn, clusterDomain) {
+				return nil
+			}
+			switch path.Base(recordSet.Type) {
+			case "SOA", "NS":
+				if recordSet.Name == "@" {
+					return nil
+				}
+			}
+			ids = append(ids, recordSet.ID)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "list the record sets of %s", path.Base(zone))
+		}
+	}
+	return ids, nil
+}
+
+// inDomain returns true if name is domain or one of its subdomains.
+func inDomain(name string, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
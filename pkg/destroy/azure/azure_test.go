@@ -0,0 +1,230 @@
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+)
+
+const (
+	subscription = "00000000-0000-0000-0000-000000000000"
+	zoneID       = "/subscriptions/" + subscription + "/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com"
+)
+
+// fakeARM is a fake of the Azure Active Directory and Resource Manager
+// endpoints used by the destroyer.
+type fakeARM struct {
+	t      *testing.T
+	server *httptest.Server
+
+	lock           sync.Mutex
+	recordSets     map[string]string
+	resourceGroups map[string]map[string]string
+	polls          map[string]int
+	deleted        []string
+}
+
+func newFakeARM(t *testing.T) *fakeARM {
+	fake := &fakeARM{
+		t: t,
+		recordSets: map[string]string{
+			zoneID + "/SOA/@":                   "example.com.",
+			zoneID + "/NS/@":                    "example.com.",
+			zoneID + "/A/www":                   "www.example.com.",
+			zoneID + "/A/api.mycluster":         "api.mycluster.example.com.",
+			zoneID + "/A/*.apps.mycluster":      "*.apps.mycluster.example.com.",
+			zoneID + "/A/api.myclusterfriend":   "api.myclusterfriend.example.com.",
+			zoneID + "/CNAME/api-int.mycluster": "api-int.mycluster.example.com.",
+		},
+		resourceGroups: map[string]map[string]string{
+			"mycluster-abcde-rg":    nil,
+			"mycluster-abcde-extra": {"kubernetes.io_cluster.mycluster-abcde": "owned"},
+			"dns-rg":                nil,
+			"other-rg":              {"kubernetes.io_cluster.other-fghij": "owned"},
+		},
+		polls: map[string]int{},
+	}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	return fake
+}
+
+func (f *fakeARM) serve(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if r.URL.Path == "/tenant/oauth2/token" {
+		r.ParseForm()
+		if r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client", "error_description": "bad secret"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token": "token", "expires_in": "3599"}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	rgs := "/subscriptions/" + subscription + "/resourcegroups"
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/subscriptions/"+subscription+"/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones":
+		f.writeList(w, []interface{}{
+			map[string]string{"id": zoneID, "name": "example.com"},
+			map[string]string{"id": zoneID + ".other", "name": "example.com.other"},
+		}, "")
+	case r.Method == "GET" && r.URL.Path == zoneID+"/recordsets":
+		ids := []string{}
+		for id := range f.recordSets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		// split the record sets across two pages
+		page, nextLink := ids[:len(ids)/2], f.server.URL+zoneID+"/recordsets?api-version=2018-05-01&page=2"
+		if r.URL.Query().Get("page") == "2" {
+			page, nextLink = ids[len(ids)/2:], ""
+		}
+		values := []interface{}{}
+		for _, id := range page {
+			typeAndName := strings.SplitN(strings.TrimPrefix(id, zoneID+"/"), "/", 2)
+			values = append(values, map[string]interface{}{
+				"id":         id,
+				"name":       typeAndName[1],
+				"type":       "Microsoft.Network/dnszones/" + typeAndName[0],
+				"properties": map[string]string{"fqdn": f.recordSets[id]},
+			})
+		}
+		f.writeList(w, values, nextLink)
+	case r.Method == "DELETE" && f.recordSets[r.URL.Path] != "":
+		delete(f.recordSets, r.URL.Path)
+		f.deleted = append(f.deleted, r.URL.Path)
+	case r.Method == "GET" && r.URL.Path == rgs:
+		assert.Equal(f.t, "tagName eq 'kubernetes.io_cluster.mycluster-abcde' and tagValue eq 'owned'", r.URL.Query().Get("$filter"))
+		values := []interface{}{}
+		for name, tags := range f.resourceGroups {
+			if tags["kubernetes.io_cluster.mycluster-abcde"] == "owned" {
+				values = append(values, map[string]string{"name": name})
+			}
+		}
+		f.writeList(w, values, "")
+	case r.Method == "HEAD" && len(r.URL.Path) > len(rgs):
+		if _, ok := f.resourceGroups[r.URL.Path[len(rgs)+1:]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE" && len(r.URL.Path) > len(rgs):
+		name := r.URL.Path[len(rgs)+1:]
+		if _, ok := f.resourceGroups[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error": {"code": "ResourceGroupNotFound", "message": "Resource group '%s' could not be found."}}`, name)
+			return
+		}
+		w.Header().Set("Location", f.server.URL+"/operations/"+name)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == "GET" && len(r.URL.Path) > len("/operations/"):
+		name := r.URL.Path[len("/operations/"):]
+		// the deletion takes two polls
+		f.polls[name]++
+		if f.polls[name] < 2 {
+			w.Header().Set("Location", f.server.URL+"/operations/"+name)
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		delete(f.resourceGroups, name)
+		f.deleted = append(f.deleted, name)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeARM) writeList(w http.ResponseWriter, values []interface{}, nextLink string) {
+	json.NewEncoder(w).Encode(map[string]interface{}{"value": values, "nextLink": nextLink})
+}
+
+func (f *fakeARM) uninstaller(logs *bytes.Buffer) *ClusterUninstaller {
+	logger := logrus.New()
+	logger.Out = logs
+	return &ClusterUninstaller{
+		Credentials: &azureconfig.Credentials{
+			SubscriptionID: subscription,
+			TenantID:       "tenant",
+			ClientID:       "client",
+			ClientSecret:   "secret",
+		},
+		InfraID:                     "mycluster-abcde",
+		ResourceGroupName:           "mycluster-abcde-rg",
+		BaseDomainResourceGroupName: "dns-rg",
+		ClusterDomain:               "mycluster.example.com",
+		ResourceManagerEndpoint:     f.server.URL,
+		ActiveDirectoryEndpoint:     f.server.URL,
+		Logger:                      logger,
+	}
+}
+
+func TestRun(t *testing.T) {
+	fake := newFakeARM(t)
+	defer fake.server.Close()
+
+	var logs bytes.Buffer
+	err := fake.uninstaller(&logs).Run()
+	if !assert.NoError(t, err, logs.String()) {
+		return
+	}
+
+	sort.Strings(fake.deleted)
+	assert.Equal(t, []string{
+		zoneID + "/A/*.apps.mycluster",
+		zoneID + "/A/api.mycluster",
+		zoneID + "/CNAME/api-int.mycluster",
+		"mycluster-abcde-extra",
+		"mycluster-abcde-rg",
+	}, fake.deleted)
+	assert.Contains(t, fake.recordSets, zoneID+"/A/api.myclusterfriend")
+	assert.Contains(t, fake.resourceGroups, "other-rg")
+}
+
+func TestRunWithoutResources(t *testing.T) {
+	fake := newFakeARM(t)
+	defer fake.server.Close()
+	fake.recordSets = map[string]string{}
+	fake.resourceGroups = map[string]map[string]string{}
+
+	var logs bytes.Buffer
+	assert.NoError(t, fake.uninstaller(&logs).Run())
+	assert.Empty(t, fake.deleted)
+}
+
+func TestRunUnauthorized(t *testing.T) {
+	fake := newFakeARM(t)
+	defer fake.server.Close()
+
+	var logs bytes.Buffer
+	uninstaller := fake.uninstaller(&logs)
+	uninstaller.Credentials.ClientSecret = "wrong"
+	err := uninstaller.Run()
+	assert.EqualError(t, err, "list DNS zones: failed to authenticate with Azure Active Directory: invalid_client: bad secret")
+	assert.Empty(t, fake.deleted)
+}
+
+func TestInDomain(t *testing.T) {
+	assert.True(t, inDomain("mycluster.example.com", "mycluster.example.com"))
+	assert.True(t, inDomain("api.mycluster.example.com", "mycluster.example.com"))
+	assert.False(t, inDomain("api.myclusterfriend.example.com", "mycluster.example.com"))
+	assert.False(t, inDomain("example.com", "mycluster.example.com"))
+}
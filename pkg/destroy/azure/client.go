@@ -0,0 +1,279 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+)
+
+const (
+	// DefaultResourceManagerEndpoint is the endpoint of the Azure Resource
+	// Manager in the public cloud.
+	DefaultResourceManagerEndpoint = "https://management.azure.com/"
+
+	// DefaultActiveDirectoryEndpoint is the endpoint of Azure Active
+	// Directory in the public cloud.
+	DefaultActiveDirectoryEndpoint = "https://login.microsoftonline.com/"
+
+	// defaultPollInterval is the wait between two polls of a long-running
+	// operation which does not set Retry-After.
+	defaultPollInterval = 10 * time.Second
+)
+
+// client is a minimal client of the Azure Resource Manager REST API.
+type client struct {
+	credentials             *azureconfig.Credentials
+	resourceManagerEndpoint string
+	activeDirectoryEndpoint string
+	httpClient              *http.Client
+
+	lock    sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// responseError is an error returned by the Azure Resource Manager.
+type responseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *responseError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// isNotFound returns true if the error is a 404 from the Azure Resource
+// Manager.
+func isNotFound(err error) bool {
+	responseErr, ok := errors.Cause(err).(*responseError)
+	return ok && responseErr.StatusCode == http.StatusNotFound
+}
+
+// authError is returned when the client fails to get a token.  It is not
+// worth retrying.
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string {
+	return fmt.Sprintf("failed to authenticate with Azure Active Directory: %v", e.err)
+}
+
+// isAuthError returns true if the error is an *authError.
+func isAuthError(err error) bool {
+	_, ok := errors.Cause(err).(*authError)
+	return ok
+}
+
+func newClient(credentials *azureconfig.Credentials, resourceManagerEndpoint, activeDirectoryEndpoint string, httpClient *http.Client) *client {
+	if resourceManagerEndpoint == "" {
+		resourceManagerEndpoint = DefaultResourceManagerEndpoint
+	}
+	if activeDirectoryEndpoint == "" {
+		activeDirectoryEndpoint = DefaultActiveDirectoryEndpoint
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &client{
+		credentials:             credentials,
+		resourceManagerEndpoint: strings.TrimSuffix(resourceManagerEndpoint, "/") + "/",
+		activeDirectoryEndpoint: strings.TrimSuffix(activeDirectoryEndpoint, "/") + "/",
+		httpClient:              httpClient,
+	}
+}
+
+// authorize returns a token for the Azure Resource Manager, getting a new
+// one with the client credentials when the previous one is about to expire.
+func (c *client) authorize(ctx context.Context) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.token != "" && time.Now().Add(time.Minute).Before(c.expires) {
+		return c.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.credentials.ClientID},
+		"client_secret": {c.credentials.ClientSecret},
+		"resource":      {c.resourceManagerEndpoint},
+	}
+	endpoint := fmt.Sprintf("%s%s/oauth2/token", c.activeDirectoryEndpoint, url.PathEscape(c.credentials.TenantID))
+	request, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", &authError{err: err}
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		// a network error is worth retrying
+		return "", err
+	}
+	defer response.Body.Close()
+
+	var token struct {
+		AccessToken      string          `json:"access_token"`
+		ExpiresIn        json.RawMessage `json:"expires_in"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", &authError{err: errors.Wrapf(err, "HTTP %d", response.StatusCode)}
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", &authError{err: errors.Errorf("%s: %s", token.Error, token.ErrorDescription)}
+	}
+
+	// Azure Active Directory v1 returns expires_in as a string
+	expiresIn, err := strconv.Atoi(strings.Trim(string(token.ExpiresIn), `"`))
+	if err != nil {
+		return "", &authError{err: errors.Wrap(err, "parse expires_in")}
+	}
+	c.token = token.AccessToken
+	c.expires = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return c.token, nil
+}
+
+// do sends a request to the Azure Resource Manager.  The path is either
+// relative to the endpoint, or a full URL like the nextLink of a list or
+// the Location of a long-running operation.  Responses other than 2xx are
+// returned as *responseError.
+func (c *client) do(ctx context.Context, method string, path string, query url.Values) (*http.Response, error) {
+	token, err := c.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		endpoint = c.resourceManagerEndpoint + strings.TrimPrefix(path, "/")
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer response.Body.Close()
+		responseErr := &responseError{StatusCode: response.StatusCode}
+		var body struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&body); err == nil {
+			responseErr.Code = body.Error.Code
+			responseErr.Message = body.Error.Message
+		}
+		return nil, responseErr
+	}
+	return response, nil
+}
+
+// list calls fn with each of the values listed at path, following the
+// nextLink of each page.
+func (c *client) list(ctx context.Context, path string, query url.Values, fn func(value json.RawMessage) error) error {
+	for path != "" {
+		response, err := c.do(ctx, "GET", path, query)
+		if err != nil {
+			return err
+		}
+
+		var page struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&page)
+		response.Body.Close()
+		if err != nil {
+			return errors.Wrapf(err, "parse %s", path)
+		}
+
+		for _, value := range page.Value {
+			if err := fn(value); err != nil {
+				return err
+			}
+		}
+
+		// the nextLink already holds the query
+		path, query = page.NextLink, nil
+	}
+	return nil
+}
+
+// delete deletes the resource at path, polling the Location of the
+// long-running operation until it completes when the deletion is
+// asynchronous.
+func (c *client) delete(ctx context.Context, path string, query url.Values) error {
+	response, err := c.do(ctx, "DELETE", path, query)
+	if err != nil {
+		return err
+	}
+
+	for response.StatusCode == http.StatusAccepted {
+		location := response.Header.Get("Location")
+		wait := retryAfter(response)
+		drain(response)
+		if location == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		// the Location returns 202 until the operation succeeds
+		response, err = c.do(ctx, "GET", location, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	drain(response)
+	return nil
+}
+
+// retryAfter returns the wait before polling a long-running operation.
+func retryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(response.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return defaultPollInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func drain(response *http.Response) {
+	io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+}
@@ -0,0 +1,2 @@
+// Package azure provides a cluster-destroyer for Azure clusters.
+package azure
@@ -0,0 +1,10 @@
+// Package azure provides a cluster-destroyer for Azure clusters.
+package azure
+
+import (
+	"github.com/openshift/installer/pkg/destroy"
+)
+
+func init() {
+	destroy.Registry["azure"] = New
+}
@@ -9,8 +9,12 @@ import (
 	"strings"
 
 	"github.com/openshift/installer/pkg/asset/cluster"
+	vsphereasset "github.com/openshift/installer/pkg/asset/cluster/vsphere"
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/vsphere"
 	"github.com/pkg/errors"
 )
 
@@ -29,6 +33,26 @@ func Destroy(dir string) (err error) {
 	tfPlatformVarsFileName := fmt.Sprintf(cluster.TfPlatformVarsFileName, platform)
 	copyNames := []string{terraform.StateFileName, cluster.TfVarsFileName, tfPlatformVarsFileName}
 
+	if platform == azure.Name {
+		credentials, err := azureconfig.LoadCredentials()
+		if err != nil {
+			return err
+		}
+		if err := credentials.SetTerraformEnvironment(); err != nil {
+			return err
+		}
+	}
+
+	if platform == vsphere.Name {
+		password, err := vsphereasset.LoadPassword(metadata.ClusterPlatformMetadata.VSphere)
+		if err != nil {
+			return err
+		}
+		if err := vsphereasset.SetTerraformEnvironment(metadata.ClusterPlatformMetadata.VSphere, password); err != nil {
+			return err
+		}
+	}
+
 	if platform == libvirt.Name {
 		err = ioutil.WriteFile(filepath.Join(dir, "disable-bootstrap.tfvars"), []byte(`{
   "bootstrap_dns": false
@@ -1,10 +1,13 @@
 package destroy
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/destroy/inventory"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -14,6 +17,19 @@ type Destroyer interface {
 	Run() error
 }
 
+// ContextDestroyer is implemented by the destroyers which can give up when
+// a context is done, like when a deadline is exceeded.  They return a
+// *stuck.Error listing the resources which failed to be deleted.
+type ContextDestroyer interface {
+	RunWithContext(ctx context.Context) error
+}
+
+// Lister is implemented by the destroyers which can list the
+// resources Run would delete, without deleting anything.
+type Lister interface {
+	List() (*inventory.Inventory, error)
+}
+
 // NewFunc is an interface for creating platform-specific destroyers.
 type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error)
 
@@ -26,7 +42,11 @@ func New(logger logrus.FieldLogger, rootDir string) (Destroyer, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewFromMetadata(logger, metadata)
+}
 
+// NewFromMetadata returns a Destroyer for the cluster described by metadata.
+func NewFromMetadata(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error) {
 	platform := metadata.Platform()
 	if platform == "" {
 		return nil, errors.New("no platform configured in metadata")
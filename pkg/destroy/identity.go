@@ -0,0 +1,97 @@
+package destroy
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+	libvirtdefaults "github.com/openshift/installer/pkg/types/libvirt/defaults"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+// Identity identifies the resources of a cluster by the tags and names the
+// installer gives them.  It allows destroying clusters whose metadata.json
+// was lost.
+type Identity struct {
+	// Platform is the platform of the cluster (e.g. "aws").
+	Platform string
+
+	// InfraID is the ID of the cluster resources, which prefixes their
+	// names and is set in their tags.
+	InfraID string
+
+	// ClusterID is the UUID of the cluster.  It is optional, and only
+	// used on AWS to match the resources tagged with openshiftClusterID
+	// as well.
+	ClusterID string
+
+	// Region is the region of the cluster on AWS and OpenStack.
+	Region string
+
+	// Cloud is the name of the OpenStack cloud in clouds.yaml.
+	Cloud string
+
+	// LibvirtURI is the URI of the libvirt daemon.  It defaults to the
+	// default URI of the libvirt platform.
+	LibvirtURI string
+}
+
+// Metadata returns the cluster metadata matching the same resources as the
+// metadata.json the installer would have written for the cluster.
+func (i *Identity) Metadata() (*types.ClusterMetadata, error) {
+	if i.InfraID == "" {
+		return nil, errors.New("the infrastructure ID is required")
+	}
+
+	metadata := &types.ClusterMetadata{
+		ClusterID: i.ClusterID,
+		InfraID:   i.InfraID,
+	}
+
+	switch i.Platform {
+	case awstypes.Name:
+		if i.Region == "" {
+			return nil, errors.New("the region is required on AWS")
+		}
+		identifier := []map[string]string{{
+			fmt.Sprintf("kubernetes.io/cluster/%s", i.InfraID): "owned",
+		}}
+		if i.ClusterID != "" {
+			identifier = append(identifier, map[string]string{
+				"openshiftClusterID": i.ClusterID,
+			})
+		}
+		metadata.ClusterPlatformMetadata.AWS = &awstypes.Metadata{
+			Region:     i.Region,
+			Identifier: identifier,
+		}
+	case libvirttypes.Name:
+		uri := i.LibvirtURI
+		if uri == "" {
+			uri = libvirtdefaults.DefaultURI
+		}
+		metadata.ClusterPlatformMetadata.Libvirt = &libvirttypes.Metadata{
+			URI: uri,
+		}
+	case openstacktypes.Name:
+		if i.Cloud == "" {
+			return nil, errors.New("the cloud is required on OpenStack")
+		}
+		metadata.ClusterPlatformMetadata.OpenStack = &openstacktypes.Metadata{
+			Region: i.Region,
+			Cloud:  i.Cloud,
+			Identifier: map[string]string{
+				"openshiftClusterID": i.InfraID,
+			},
+		}
+	case "":
+		return nil, errors.New("the platform is required")
+	default:
+		return nil, errors.Errorf("destroying by tag is not supported on %q", i.Platform)
+	}
+
+	return metadata, nil
+}
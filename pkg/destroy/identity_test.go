@@ -0,0 +1,101 @@
+package destroy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+func TestIdentityMetadata(t *testing.T) {
+	cases := []struct {
+		name     string
+		identity Identity
+		expected *types.ClusterMetadata
+		err      string
+	}{
+		{
+			name:     "aws",
+			identity: Identity{Platform: "aws", InfraID: "test-abcde", Region: "us-east-1"},
+			expected: &types.ClusterMetadata{
+				InfraID: "test-abcde",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{
+						Region:     "us-east-1",
+						Identifier: []map[string]string{{"kubernetes.io/cluster/test-abcde": "owned"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "aws with cluster ID",
+			identity: Identity{Platform: "aws", InfraID: "test-abcde", ClusterID: "uuid", Region: "us-east-1"},
+			expected: &types.ClusterMetadata{
+				InfraID:   "test-abcde",
+				ClusterID: "uuid",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{
+						Region: "us-east-1",
+						Identifier: []map[string]string{
+							{"kubernetes.io/cluster/test-abcde": "owned"},
+							{"openshiftClusterID": "uuid"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "aws without region",
+			identity: Identity{Platform: "aws", InfraID: "test-abcde"},
+			err:      "the region is required on AWS",
+		},
+		{
+			name:     "openstack",
+			identity: Identity{Platform: "openstack", InfraID: "test-abcde", Cloud: "openstack"},
+			expected: &types.ClusterMetadata{
+				InfraID: "test-abcde",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					OpenStack: &openstacktypes.Metadata{
+						Cloud:      "openstack",
+						Identifier: map[string]string{"openshiftClusterID": "test-abcde"},
+					},
+				},
+			},
+		},
+		{
+			name:     "libvirt",
+			identity: Identity{Platform: "libvirt", InfraID: "test-abcde"},
+			expected: &types.ClusterMetadata{
+				InfraID: "test-abcde",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					Libvirt: &libvirttypes.Metadata{URI: "qemu+tcp://192.168.122.1/system"},
+				},
+			},
+		},
+		{
+			name:     "missing infra ID",
+			identity: Identity{Platform: "aws", Region: "us-east-1"},
+			err:      "the infrastructure ID is required",
+		},
+		{
+			name:     "unsupported platform",
+			identity: Identity{Platform: "none", InfraID: "test-abcde"},
+			err:      `destroying by tag is not supported on "none"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata, err := tc.identity.Metadata()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, metadata)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package inventory lists the resources a destroyer would delete.
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Inventory holds resources, grouped by type. Adding a resource more than
+// once has no effect.
+type Inventory struct {
+	resources map[string]map[string]struct{}
+}
+
+// New returns an empty inventory.
+func New() *Inventory {
+	return &Inventory{resources: map[string]map[string]struct{}{}}
+}
+
+// Add adds the resource with the given type and ID, like an ARN or a name.
+func (i *Inventory) Add(resourceType string, id string) {
+	ids, ok := i.resources[resourceType]
+	if !ok {
+		ids = map[string]struct{}{}
+		i.resources[resourceType] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+// Types returns the sorted types of the resources.
+func (i *Inventory) Types() []string {
+	types := make([]string, 0, len(i.resources))
+	for t := range i.resources {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IDs returns the sorted IDs of the resources of the given type.
+func (i *Inventory) IDs(resourceType string) []string {
+	ids := make([]string, 0, len(i.resources[resourceType]))
+	for id := range i.resources[resourceType] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Len returns the number of resources.
+func (i *Inventory) Len() int {
+	n := 0
+	for _, ids := range i.resources {
+		n += len(ids)
+	}
+	return n
+}
+
+// Write writes the resources, grouped by type, to w.
+func (i *Inventory) Write(w io.Writer) error {
+	for _, t := range i.Types() {
+		ids := i.IDs(t)
+		if _, err := fmt.Fprintf(w, "%s (%d)\n", t, len(ids)); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, err := fmt.Fprintf(w, "  %s\n", id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package inventory
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory(t *testing.T) {
+	inventory := New()
+	assert.Equal(t, 0, inventory.Len())
+
+	inventory.Add("ec2:vpc", "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1")
+	inventory.Add("ec2:instance", "arn:aws:ec2:us-east-1:123456789012:instance/i-2")
+	inventory.Add("ec2:instance", "arn:aws:ec2:us-east-1:123456789012:instance/i-1")
+	inventory.Add("ec2:instance", "arn:aws:ec2:us-east-1:123456789012:instance/i-2")
+
+	assert.Equal(t, 3, inventory.Len())
+	assert.Equal(t, []string{"ec2:instance", "ec2:vpc"}, inventory.Types())
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, inventory.Write(buf))
+	assert.Equal(t, `ec2:instance (2)
+  arn:aws:ec2:us-east-1:123456789012:instance/i-1
+  arn:aws:ec2:us-east-1:123456789012:instance/i-2
+ec2:vpc (1)
+  arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1
+`, buf.String())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/audit"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -54,12 +55,13 @@ func (o *ClusterUninstaller) Run() error {
 		return errors.Wrap(err, "failed to connect to Libvirt daemon")
 	}
 
+	logger := audit.WithContext(o.Logger, audit.Context{Platform: "libvirt"})
 	for _, del := range []deleteFunc{
 		deleteDomains,
 		deleteNetwork,
 		deleteVolumes,
 	} {
-		err = del(conn, o.Filter, o.Logger)
+		err = del(conn, o.Filter, logger)
 		if err != nil {
 			return err
 		}
@@ -117,7 +119,7 @@ func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger lo
 		if err := domain.Undefine(); err != nil {
 			return false, errors.Wrapf(err, "undefine domain %q", dName)
 		}
-		logger.WithField("domain", dName).Info("Deleted domain")
+		audit.Deleted(logger.WithField("domain", dName), "domain", dName)
 	}
 
 	return nothingToDelete, nil
@@ -164,7 +166,7 @@ func deleteVolumes(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 			if err := vol.Delete(0); err != nil {
 				return errors.Wrapf(err, "delete volume %q from %q", vName, tpool)
 			}
-			logger.WithField("volume", vName).Info("Deleted volume")
+			audit.Deleted(logger.WithField("volume", vName), "volume", vName)
 		}
 	default:
 		// blow away entire pool.
@@ -175,7 +177,7 @@ func deleteVolumes(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 		if err := pool.Undefine(); err != nil {
 			return errors.Wrapf(err, "undefine pool %q", tpool)
 		}
-		logger.WithField("pool", tpool).Info("Deleted pool")
+		audit.Deleted(logger.WithField("pool", tpool), "pool", tpool)
 	}
 
 	return nil
@@ -206,7 +208,7 @@ func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 		if err := network.Undefine(); err != nil {
 			return errors.Wrapf(err, "undefine network %q", nName)
 		}
-		logger.WithField("network", nName).Info("Deleted network")
+		audit.Deleted(logger.WithField("network", nName), "network", nName)
 	}
 	return nil
 }
@@ -0,0 +1,103 @@
+// +build libvirt
+
+package libvirt
+
+import (
+	libvirt "github.com/libvirt/libvirt-go"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/inventory"
+)
+
+// List returns the resources Run would delete, without deleting anything.
+func (o *ClusterUninstaller) List() (*inventory.Inventory, error) {
+	conn, err := libvirt.NewConnect(o.LibvirtURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to Libvirt daemon")
+	}
+
+	resources := inventory.New()
+	for _, list := range []func(*libvirt.Connect, filterFunc, *inventory.Inventory) error{
+		listDomains,
+		listNetworks,
+		listVolumes,
+	} {
+		if err := list(conn, o.Filter, resources); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func listDomains(conn *libvirt.Connect, filter filterFunc, resources *inventory.Inventory) error {
+	domains, err := conn.ListAllDomains(0)
+	if err != nil {
+		return errors.Wrap(err, "list domains")
+	}
+
+	for _, domain := range domains {
+		defer domain.Free()
+		dName, err := domain.GetName()
+		if err != nil {
+			return errors.Wrap(err, "get domain name")
+		}
+		if filter(dName) {
+			resources.Add("domain", dName)
+		}
+	}
+	return nil
+}
+
+func listNetworks(conn *libvirt.Connect, filter filterFunc, resources *inventory.Inventory) error {
+	networks, err := conn.ListNetworks()
+	if err != nil {
+		return errors.Wrap(err, "list networks")
+	}
+
+	for _, nName := range networks {
+		if filter(nName) {
+			resources.Add("network", nName)
+		}
+	}
+	return nil
+}
+
+// listVolumes lists the pool deleteVolumes would blow away, or the
+// matching volumes of the default pool when there is no such pool.
+func listVolumes(conn *libvirt.Connect, filter filterFunc, resources *inventory.Inventory) error {
+	pools, err := conn.ListStoragePools()
+	if err != nil {
+		return errors.Wrap(err, "list storage pools")
+	}
+
+	for _, pname := range pools {
+		if filter(pname) {
+			resources.Add("pool", pname)
+			return nil
+		}
+	}
+
+	tpool := "default"
+	pool, err := conn.LookupStoragePoolByName(tpool)
+	if err != nil {
+		return errors.Wrapf(err, "get storage pool %q", tpool)
+	}
+	defer pool.Free()
+
+	vols, err := pool.ListAllStorageVolumes(0)
+	if err != nil {
+		return errors.Wrapf(err, "list volumes in %q", tpool)
+	}
+
+	for _, vol := range vols {
+		defer vol.Free()
+		vName, err := vol.GetName()
+		if err != nil {
+			return errors.Wrapf(err, "get volume names in %q", tpool)
+		}
+		if filter(vName) {
+			resources.Add("volume", vName)
+		}
+	}
+	return nil
+}
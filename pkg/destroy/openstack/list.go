@@ -0,0 +1,256 @@
+package openstack
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	sg "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/inventory"
+)
+
+// List returns the resources Run would delete, without deleting anything.
+func (o *ClusterUninstaller) List() (*inventory.Inventory, error) {
+	opts := &clientconfig.ClientOpts{
+		Cloud: o.Cloud,
+	}
+
+	resources := inventory.New()
+	for _, list := range []func(*clientconfig.ClientOpts, Filter, *inventory.Inventory) error{
+		listServers,
+		listTrunks,
+		listPorts,
+		listSecurityGroups,
+		listRouters,
+		listSubnets,
+		listNetworks,
+		listContainers,
+	} {
+		if err := list(opts, o.Filter, resources); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func listServers(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("compute", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := servers.List(conn, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing servers")
+	}
+
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing servers")
+	}
+
+	serverObjects := []ObjectWithTags{}
+	for _, server := range allServers {
+		serverObjects = append(
+			serverObjects, ObjectWithTags{
+				ID:   server.ID,
+				Tags: server.Metadata})
+	}
+
+	for _, server := range filterObjects(serverObjects, filter) {
+		resources.Add("server", server.ID)
+	}
+	return nil
+}
+
+func listTrunks(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := trunks.List(conn, trunks.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing trunks")
+	}
+
+	allTrunks, err := trunks.ExtractTrunks(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing trunks")
+	}
+	for _, trunk := range allTrunks {
+		resources.Add("trunk", trunk.ID)
+	}
+	return nil
+}
+
+func listPorts(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := ports.List(conn, ports.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing ports")
+	}
+
+	allPorts, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing ports")
+	}
+	for _, port := range allPorts {
+		if err := listFloatingIPs(conn, port.ID, resources); err != nil {
+			return err
+		}
+		resources.Add("port", port.ID)
+	}
+	return nil
+}
+
+// listFloatingIPs lists the floating IPs of the port, which are deleted
+// with it.
+func listFloatingIPs(conn *gophercloud.ServiceClient, portID string, resources *inventory.Inventory) error {
+	allPages, err := floatingips.List(conn, floatingips.ListOpts{PortID: portID}).AllPages()
+	if err != nil {
+		return errors.Wrapf(err, "listing floating IPs of port %s", portID)
+	}
+
+	allFIPs, err := floatingips.ExtractFloatingIPs(allPages)
+	if err != nil {
+		return errors.Wrapf(err, "listing floating IPs of port %s", portID)
+	}
+	for _, fip := range allFIPs {
+		resources.Add("floating IP", fip.ID)
+	}
+	return nil
+}
+
+func listSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := sg.List(conn, sg.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing security groups")
+	}
+
+	allGroups, err := sg.ExtractGroups(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing security groups")
+	}
+	for _, group := range allGroups {
+		resources.Add("security group", group.ID)
+	}
+	return nil
+}
+
+func listRouters(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := routers.List(conn, routers.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing routers")
+	}
+
+	allRouters, err := routers.ExtractRouters(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing routers")
+	}
+	for _, router := range allRouters {
+		resources.Add("router", router.ID)
+	}
+	return nil
+}
+
+func listSubnets(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := subnets.List(conn, subnets.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing subnets")
+	}
+
+	allSubnets, err := subnets.ExtractSubnets(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing subnets")
+	}
+	for _, subnet := range allSubnets {
+		resources.Add("subnet", subnet.ID)
+	}
+	return nil
+}
+
+func listNetworks(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := networks.List(conn, networks.ListOpts{TagsAny: tagsAny(filter)}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing networks")
+	}
+
+	allNetworks, err := networks.ExtractNetworks(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing networks")
+	}
+	for _, network := range allNetworks {
+		resources.Add("network", network.ID)
+	}
+	return nil
+}
+
+func listContainers(opts *clientconfig.ClientOpts, filter Filter, resources *inventory.Inventory) error {
+	conn, err := clientconfig.NewServiceClient("object-store", opts)
+	if err != nil {
+		return err
+	}
+
+	allPages, err := containers.List(conn, containers.ListOpts{Full: false}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "listing containers")
+	}
+
+	allContainers, err := containers.ExtractNames(allPages)
+	if err != nil {
+		return errors.Wrap(err, "listing containers")
+	}
+	for _, container := range allContainers {
+		metadata, err := containers.Get(conn, container, nil).ExtractMetadata()
+		if err != nil {
+			return errors.Wrapf(err, "get container %s", container)
+		}
+		for key, val := range filter {
+			// Swift mangles the case, see deleteContainers.
+			if metadata[strings.Title(strings.ToLower(key))] == val {
+				resources.Add("container", container)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func tagsAny(filter Filter) string {
+	return strings.Join(filterTags(filter), ",")
+}
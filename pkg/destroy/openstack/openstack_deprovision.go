@@ -2,11 +2,13 @@
 package openstack
 
 import (
+	"context"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/audit"
+	"github.com/openshift/installer/pkg/destroy/stuck"
 	"github.com/openshift/installer/pkg/types"
 
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
@@ -20,8 +22,8 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
 	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // Filter holds the key/value pairs for the tags we will be matching
@@ -41,13 +43,16 @@ type ObjectWithTags struct {
 
 // deleteFunc type is the interface a function needs to implement to be called as a goroutine.
 // The (bool, error) return type mimics wait.ExponentialBackoff where the bool indicates successful
-// completion, and the error is for unrecoverable errors.
-type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error)
+// completion, and the error is for unrecoverable errors.  The resources which fail to be deleted
+// are recorded in the tracker.
+type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error)
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
 	// Cloud is the cloud name as set in clouds.yml
 	Cloud string
+	// Region is the region of the cloud, recorded in the audit log
+	Region string
 	// Filter contains the openshiftClusterID to filter tags
 	Filter Filter
 	Logger logrus.FieldLogger
@@ -55,49 +60,66 @@ type ClusterUninstaller struct {
 
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() error {
+	return o.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the uninstall process until all of the resources are
+// deleted or the context is done.  In the latter case, it returns a
+// *stuck.Error with the resources which failed to be deleted.
+func (o *ClusterUninstaller) RunWithContext(ctx context.Context) error {
 	deleteFuncs := map[string]deleteFunc{}
 	populateDeleteFuncs(deleteFuncs)
-	returnChannel := make(chan string)
+	returnChannel := make(chan error)
+	failures := stuck.NewTracker(o.Logger)
 
 	opts := &clientconfig.ClientOpts{
 		Cloud: o.Cloud,
 	}
 
+	logger := audit.WithContext(o.Logger, audit.Context{
+		Platform: "openstack",
+		Region:   o.Region,
+		Filter:   o.Filter,
+	})
+
 	// launch goroutines
 	for name, function := range deleteFuncs {
-		go deleteRunner(name, function, opts, o.Filter, o.Logger, returnChannel)
+		go deleteRunner(ctx, name, function, opts, o.Filter, logger, failures, returnChannel)
 	}
 
 	// wait for them to finish
+	var err error
 	for i := 0; i < len(deleteFuncs); i++ {
 		select {
 		case res := <-returnChannel:
-			o.Logger.Debugf("goroutine %v complete", res)
+			if res != nil && err == nil {
+				err = res
+			}
 		}
 	}
 
-	return nil
-}
-
-func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, channel chan string) {
-	backoffSettings := wait.Backoff{
-		Duration: time.Second * 10,
-		Factor:   1.3,
-		Steps:    100,
+	if err != nil && ctx.Err() != nil {
+		return failures.Error(ctx.Err())
 	}
+	return err
+}
 
-	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return dFunction(opts, filter, logger)
+func deleteRunner(ctx context.Context, deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker, channel chan error) {
+	err := failures.Retry(ctx, func() (bool, error) {
+		return dFunction(opts, filter, logger, failures)
 	})
-
+	if _, ok := err.(*stuck.Error); ok {
+		channel <- errors.Wrapf(ctx.Err(), "%s", deleteFuncName)
+		return
+	}
 	if err != nil {
-		logger.Fatalf("Unrecoverable error/timed out: %v", err)
-		os.Exit(1)
+		channel <- errors.Wrapf(err, "unrecoverable error in %s", deleteFuncName)
+		return
 	}
 
 	// record that the goroutine has run to completion
-	channel <- deleteFuncName
-	return
+	logger.Debugf("goroutine %v complete", deleteFuncName)
+	channel <- nil
 }
 
 // populateDeleteFuncs is the list of functions that will be launched as
@@ -158,7 +180,7 @@ func filterTags(filters Filter) []string {
 	return tags
 }
 
-func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack servers")
 	defer logger.Debugf("Exiting deleting openstack servers")
 
@@ -205,11 +227,12 @@ func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			logger.Fatalf("%v", err)
 			os.Exit(1)
 		}
+		audit.Deleted(logger, "server", server.ID)
 	}
 	return len(filteredServers) == 0, nil
 }
 
-func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack ports")
 	defer logger.Debugf("Exiting deleting openstack ports")
 
@@ -255,19 +278,23 @@ func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fie
 				logger.Fatalf("%v", err)
 				os.Exit(1)
 			}
+			audit.Deleted(logger, "floating IP", fip.ID)
 		}
 
 		logger.Debugf("Deleting Port: %+v", port.ID)
 		err = ports.Delete(conn, port.ID).ExtractErr()
 		if err != nil {
 			// This can fail when port is still in use so return/retry
+			failures.Failed("port", port.ID, err)
 			return false, nil
 		}
+		failures.Deleted("port", port.ID)
+		audit.Deleted(logger, "port", port.ID)
 	}
 	return len(allPorts) == 0, nil
 }
 
-func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack security-groups")
 	defer logger.Debugf("Exiting deleting openstack security-groups")
 
@@ -297,13 +324,16 @@ func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger l
 		err = sg.Delete(conn, group.ID).ExtractErr()
 		if err != nil {
 			// This can fail when sg is still in use by servers
+			failures.Failed("security group", group.ID, err)
 			return false, nil
 		}
+		failures.Deleted("security group", group.ID)
+		audit.Deleted(logger, "security group", group.ID)
 	}
 	return len(allGroups) == 0, nil
 }
 
-func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack routers")
 	defer logger.Debugf("Exiting deleting openstack routers")
 
@@ -353,6 +383,7 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 				_, err = routers.RemoveInterface(conn, router.ID, removeOpts).Extract()
 				if err != nil {
 					// This can fail when subnet is still in use
+					failures.Failed("router", router.ID, errors.Wrapf(err, "removing subnet %s", IP.SubnetID))
 					return false, nil
 				}
 			}
@@ -363,11 +394,13 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			logger.Fatalf("%v", err)
 			os.Exit(1)
 		}
+		failures.Deleted("router", router.ID)
+		audit.Deleted(logger, "router", router.ID)
 	}
 	return len(allRouters) == 0, nil
 }
 
-func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack subnets")
 	defer logger.Debugf("Exiting deleting openstack subnets")
 
@@ -397,13 +430,16 @@ func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 		err = subnets.Delete(conn, subnet.ID).ExtractErr()
 		if err != nil {
 			// This can fail when subnet is still in use
+			failures.Failed("subnet", subnet.ID, err)
 			return false, nil
 		}
+		failures.Deleted("subnet", subnet.ID)
+		audit.Deleted(logger, "subnet", subnet.ID)
 	}
 	return len(allSubnets) == 0, nil
 }
 
-func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack networks")
 	defer logger.Debugf("Exiting deleting openstack networks")
 
@@ -433,13 +469,16 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 		err = networks.Delete(conn, network.ID).ExtractErr()
 		if err != nil {
 			// This can fail when network is still in use
+			failures.Failed("network", network.ID, err)
 			return false, nil
 		}
+		failures.Deleted("network", network.ID)
+		audit.Deleted(logger, "network", network.ID)
 	}
 	return len(allNetworks) == 0, nil
 }
 
-func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack containers")
 	defer logger.Debugf("Exiting deleting openstack containers")
 
@@ -497,6 +536,7 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 					logger.Fatalf("%v", err)
 					os.Exit(1)
 				}
+				audit.Deleted(logger, "container", container)
 				// If a metadata key matched, we're done so break from the loop
 				break
 			}
@@ -505,7 +545,7 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 	return true, nil
 }
 
-func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
 	logger.Debug("Deleting openstack trunks")
 	defer logger.Debugf("Exiting deleting openstack trunks")
 
@@ -535,8 +575,11 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 		err = trunks.Delete(conn, trunk.ID).ExtractErr()
 		if err != nil {
 			// This can fail when the trunk is still in use so return/retry
+			failures.Failed("trunk", trunk.ID, err)
 			return false, nil
 		}
+		failures.Deleted("trunk", trunk.ID)
+		audit.Deleted(logger, "trunk", trunk.ID)
 	}
 	return len(allTrunks) == 0, nil
 }
@@ -545,6 +588,7 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (destroy.Destroyer, error) {
 	return &ClusterUninstaller{
 		Cloud:  metadata.ClusterPlatformMetadata.OpenStack.Cloud,
+		Region: metadata.ClusterPlatformMetadata.OpenStack.Region,
 		Filter: metadata.ClusterPlatformMetadata.OpenStack.Identifier,
 		Logger: logger,
 	}, nil
@@ -0,0 +1,41 @@
+package stuck
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// backoff is the wait after the first failed pass.
+	backoff = 10 * time.Second
+
+	// maxBackoff is the longest wait between two passes.
+	maxBackoff = 5 * time.Minute
+)
+
+// Retry calls pass until it returns true or an error, waiting 30% longer
+// after each pass which is not done, up to five minutes.  It gives up as
+// soon as the context is done, returning the error of the tracker with the
+// resources which failed to be deleted.
+func (t *Tracker) Retry(ctx context.Context, pass func() (bool, error)) error {
+	duration := backoff
+	for {
+		done, err := pass()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return t.Error(ctx.Err())
+		case <-time.After(duration):
+		}
+		duration = time.Duration(float64(duration) * 1.3)
+		if duration > maxBackoff {
+			duration = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package stuck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	defer func(b, m time.Duration) { backoff, maxBackoff = b, m }(backoff, maxBackoff)
+	backoff, maxBackoff = time.Millisecond, 2*time.Millisecond
+
+	cases := []struct {
+		name     string
+		results  []error
+		cancel   bool
+		expected string
+		passes   int
+	}{
+		{
+			name:   "done",
+			passes: 3,
+		},
+		{
+			name:     "unrecoverable",
+			results:  []error{nil, errors.New("forbidden")},
+			expected: "forbidden",
+			passes:   2,
+		},
+		{
+			name:     "context done",
+			cancel:   true,
+			expected: "context canceled: 1 resources failed to be deleted",
+			passes:   1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tracker := NewTracker(logrus.New())
+			passes := 0
+			err := tracker.Retry(ctx, func() (bool, error) {
+				passes++
+				if passes <= len(tc.results) && tc.results[passes-1] != nil {
+					return false, tc.results[passes-1]
+				}
+				if tc.cancel {
+					tracker.Failed("vm", "a", errors.New("busy"))
+					cancel()
+					return false, nil
+				}
+				return passes == 3, nil
+			})
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+			assert.Equal(t, tc.passes, passes)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package stuck tracks the resources a destroyer repeatedly fails to
+// delete, to report them when it gives up.
+package stuck
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WarnAttempts is the number of failed attempts after which a resource is
+// reported as stuck in the logs.
+const WarnAttempts = 3
+
+// Resource is a resource which failed to be deleted.
+type Resource struct {
+	// Type is the type of the resource, like "ec2:network-interface".
+	Type string
+
+	// ID identifies the resource, like an ARN or a name.
+	ID string
+
+	// Attempts is the number of failed attempts to delete the resource.
+	Attempts int
+
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Tracker records the failed attempts to delete resources.  It is safe
+// for concurrent use.
+type Tracker struct {
+	logger logrus.FieldLogger
+
+	lock      sync.Mutex
+	resources map[string]*Resource
+}
+
+// NewTracker returns a tracker warning through the given logger about the
+// resources which keep failing to be deleted.
+func NewTracker(logger logrus.FieldLogger) *Tracker {
+	return &Tracker{logger: logger, resources: map[string]*Resource{}}
+}
+
+// Failed records a failed attempt to delete the resource.
+func (t *Tracker) Failed(resourceType string, id string, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := resourceType + "/" + id
+	resource, ok := t.resources[key]
+	if !ok {
+		resource = &Resource{Type: resourceType, ID: id}
+		t.resources[key] = resource
+	}
+	resource.Attempts++
+	resource.Err = err
+	if resource.Attempts == WarnAttempts {
+		t.logger.WithField(resourceType, id).Warnf("Failed to delete %d times, retrying: %v", resource.Attempts, err)
+	}
+}
+
+// Deleted records that the resource was deleted after all.
+func (t *Tracker) Deleted(resourceType string, id string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.resources, resourceType+"/"+id)
+}
+
+// Resources returns the resources which failed to be deleted and were not
+// deleted since, sorted by type and ID.
+func (t *Tracker) Resources() []Resource {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	resources := make([]Resource, 0, len(t.resources))
+	for _, resource := range t.resources {
+		resources = append(resources, *resource)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].ID < resources[j].ID
+	})
+	return resources
+}
+
+// Error returns the error of a destroyer giving up for the given reason,
+// with the resources which failed to be deleted.
+func (t *Tracker) Error(reason error) error {
+	return &Error{Reason: reason, Resources: t.Resources()}
+}
+
+// Error is returned by the destroyers giving up before deleting all of
+// the resources, like when their deadline is exceeded.
+type Error struct {
+	// Reason is the reason the destroyer gave up.
+	Reason error
+
+	// Resources are the resources which failed to be deleted.
+	Resources []Resource
+}
+
+func (e *Error) Error() string {
+	if len(e.Resources) == 0 {
+		return e.Reason.Error()
+	}
+	return fmt.Sprintf("%v: %d resources failed to be deleted", e.Reason, len(e.Resources))
+}
@@ -0,0 +1,41 @@
+package stuck
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &logs
+	logger.Formatter = &logrus.TextFormatter{DisableTimestamp: true}
+	tracker := NewTracker(logger)
+
+	tracker.Failed("ec2:subnet", "subnet-a", errors.New("DependencyViolation"))
+	inUse := errors.New("InvalidNetworkInterface.InUse")
+	tracker.Failed("ec2:network-interface", "eni-a", inUse)
+	tracker.Failed("ec2:subnet", "subnet-a", errors.New("DependencyViolation again"))
+	tracker.Failed("ec2:vpc", "vpc-a", errors.New("DependencyViolation"))
+	tracker.Deleted("ec2:vpc", "vpc-a")
+	assert.Empty(t, logs.String())
+	stillInUse := errors.New("DependencyViolation still")
+	tracker.Failed("ec2:subnet", "subnet-a", stillInUse)
+	assert.Equal(t, "level=warning msg=\"Failed to delete 3 times, retrying: DependencyViolation still\" ec2:subnet=subnet-a\n", logs.String())
+
+	err := tracker.Error(errors.New("context deadline exceeded"))
+	assert.EqualError(t, err, "context deadline exceeded: 2 resources failed to be deleted")
+	assert.Equal(t, []Resource{
+		{Type: "ec2:network-interface", ID: "eni-a", Attempts: 1, Err: inUse},
+		{Type: "ec2:subnet", ID: "subnet-a", Attempts: 3, Err: stillInUse},
+	}, err.(*Error).Resources)
+}
+
+func TestErrorWithoutResources(t *testing.T) {
+	err := NewTracker(logrus.New()).Error(errors.New("context deadline exceeded"))
+	assert.EqualError(t, err, "context deadline exceeded")
+}
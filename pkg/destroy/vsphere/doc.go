@@ -0,0 +1,2 @@
+// Package vsphere provides a cluster-destroyer for vSphere clusters.
+package vsphere
@@ -0,0 +1,10 @@
+// Package vsphere provides a cluster-destroyer for vSphere clusters.
+package vsphere
+
+import (
+	"github.com/openshift/installer/pkg/destroy"
+)
+
+func init() {
+	destroy.Registry["vsphere"] = New
+}
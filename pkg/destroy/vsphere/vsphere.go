@@ -0,0 +1,359 @@
+package vsphere
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	vsphereasset "github.com/openshift/installer/pkg/asset/cluster/vsphere"
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/destroy/audit"
+	"github.com/openshift/installer/pkg/destroy/inventory"
+	"github.com/openshift/installer/pkg/destroy/stuck"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ClusterUninstaller holds the various options for the cluster we want to delete.
+type ClusterUninstaller struct {
+	// VCenter is the domain name or the IP address of the vCenter, or the
+	// URL of its SDK endpoint.
+	VCenter  string
+	Username string
+	Password string
+
+	// InfraID is the ID of the cluster resources.  The virtual machines
+	// and folders with the tag named InfraID, in the category named
+	// openshift-<InfraID>, are deleted, then the tag and the category.
+	InfraID string
+
+	// Datacenter and Folder locate the VM folder of the cluster, which
+	// is deleted when it is empty after deleting the virtual machines.
+	Datacenter string
+	Folder     string
+
+	Logger logrus.FieldLogger
+}
+
+// New returns a vSphere destroyer from ClusterMetadata.  The password of
+// the vCenter user is not in the metadata, so it is read from the
+// VSPHERE_PASSWORD environment variable or asked for.
+func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (destroy.Destroyer, error) {
+	password, err := vsphereasset.LoadPassword(metadata.ClusterPlatformMetadata.VSphere)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterUninstaller{
+		VCenter:    metadata.ClusterPlatformMetadata.VSphere.VCenter,
+		Username:   metadata.ClusterPlatformMetadata.VSphere.Username,
+		Password:   password,
+		InfraID:    metadata.InfraID,
+		Datacenter: metadata.ClusterPlatformMetadata.VSphere.Datacenter,
+		Folder:     metadata.ClusterPlatformMetadata.VSphere.Folder,
+		Logger:     logger,
+	}, nil
+}
+
+// Run is the entrypoint to start the uninstall process.
+func (o *ClusterUninstaller) Run() error {
+	return o.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the uninstall process until all of the resources are
+// deleted or the context is done.  In the latter case, it returns a
+// *stuck.Error with the resources which failed to be deleted.
+func (o *ClusterUninstaller) RunWithContext(ctx context.Context) error {
+	failures := stuck.NewTracker(o.Logger)
+	logger := audit.WithContext(o.Logger, audit.Context{
+		Platform: "vsphere",
+		Filter:   map[string]string{vsphereasset.TagCategoryName(o.InfraID): o.InfraID},
+	})
+
+	var client *vsphereasset.Client
+	defer func() {
+		if client != nil {
+			client.Logout(context.Background())
+		}
+	}()
+	return failures.Retry(ctx, func() (bool, error) {
+		if client == nil {
+			var err error
+			client, err = vsphereasset.NewClient(ctx, o.VCenter, o.Username, o.Password)
+			if err != nil {
+				if vsphereasset.IsInvalidLogin(err) {
+					return false, err
+				}
+				logger.Debug(err)
+				return false, nil
+			}
+		}
+		return o.deletePass(ctx, client, logger, failures)
+	})
+}
+
+// deletePass deletes the tagged virtual machines, then the tagged folders
+// and the cluster folder when they are empty, and finally the tag and its
+// category.  It returns true when all of them are gone, and an error only
+// when retrying is not worth it.
+func (o *ClusterUninstaller) deletePass(ctx context.Context, client *vsphereasset.Client, logger logrus.FieldLogger, failures *stuck.Tracker) (bool, error) {
+	tagManager := tags.NewManager(client.REST)
+	categoryID, tagID, objects, err := o.taggedObjects(ctx, tagManager)
+	if err != nil {
+		logger.Debugf("list tagged objects: %v", err)
+		return false, nil
+	}
+
+	done := true
+	folders := []string{}
+	for _, ref := range objects {
+		switch ref.Type {
+		case "VirtualMachine":
+			if err := deleteVirtualMachine(ctx, object.NewVirtualMachine(client.SOAP.Client, ref)); err != nil {
+				logger.WithField("virtual machine", ref.Value).Debugf("delete: %v", err)
+				failures.Failed("vsphere:virtual-machine", ref.Value, err)
+				done = false
+				continue
+			}
+			failures.Deleted("vsphere:virtual-machine", ref.Value)
+			audit.Deleted(logger, "vsphere:virtual-machine", ref.Value)
+		case "Folder":
+			folders = append(folders, ref.Value)
+		default:
+			logger.Debugf("leaving %s %s, only virtual machines and folders are deleted", ref.Type, ref.Value)
+		}
+	}
+
+	// wait for the virtual machines to be gone before emptying folders
+	if !done {
+		return false, nil
+	}
+
+	folder, err := o.clusterFolder(ctx, client)
+	if err != nil {
+		logger.Debugf("find folder %s: %v", o.Folder, err)
+		return false, nil
+	}
+	if folder != "" && !contains(folders, folder) {
+		folders = append(folders, folder)
+	}
+	for _, folder := range folders {
+		ref := vimtypes.ManagedObjectReference{Type: "Folder", Value: folder}
+		err := deleteFolder(ctx, object.NewFolder(client.SOAP.Client, ref))
+		if errors.Cause(err) == errFolderNotEmpty {
+			logger.WithField("folder", folder).Warn("Leaving the folder, which holds resources outside of the cluster")
+			continue
+		}
+		if err != nil {
+			logger.WithField("folder", folder).Debugf("delete: %v", err)
+			failures.Failed("vsphere:folder", folder, err)
+			done = false
+			continue
+		}
+		failures.Deleted("vsphere:folder", folder)
+		audit.Deleted(logger, "vsphere:folder", folder)
+	}
+
+	// keep the tag until the objects attached to it are gone, to find
+	// them again on the next pass
+	if !done {
+		return false, nil
+	}
+	if tagID != "" {
+		if err := tagManager.DeleteTag(ctx, &tags.Tag{ID: tagID}); err != nil {
+			logger.WithField("tag", tagID).Debugf("delete: %v", err)
+			failures.Failed("vsphere:tag", tagID, err)
+			return false, nil
+		}
+		failures.Deleted("vsphere:tag", tagID)
+		audit.Deleted(logger, "vsphere:tag", tagID)
+	}
+	if categoryID != "" {
+		if err := tagManager.DeleteCategory(ctx, &tags.Category{ID: categoryID}); err != nil {
+			logger.WithField("tag category", categoryID).Debugf("delete: %v", err)
+			failures.Failed("vsphere:tag-category", categoryID, err)
+			return false, nil
+		}
+		failures.Deleted("vsphere:tag-category", categoryID)
+		audit.Deleted(logger, "vsphere:tag-category", categoryID)
+	}
+	return true, nil
+}
+
+// List returns the tagged virtual machines and folders, the cluster folder,
+// and the tag and its category.
+func (o *ClusterUninstaller) List() (*inventory.Inventory, error) {
+	ctx := context.Background()
+	client, err := vsphereasset.NewClient(ctx, o.VCenter, o.Username, o.Password)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Logout(ctx)
+
+	resources := inventory.New()
+	categoryID, tagID, objects, err := o.taggedObjects(ctx, tags.NewManager(client.REST))
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range objects {
+		switch ref.Type {
+		case "VirtualMachine":
+			resources.Add("vsphere:virtual-machine", ref.Value)
+		case "Folder":
+			resources.Add("vsphere:folder", ref.Value)
+		}
+	}
+
+	folder, err := o.clusterFolder(ctx, client)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find folder %s", o.Folder)
+	}
+	if folder != "" && !contains(resources.IDs("vsphere:folder"), folder) {
+		resources.Add("vsphere:folder", folder)
+	}
+	if tagID != "" {
+		resources.Add("vsphere:tag", tagID)
+	}
+	if categoryID != "" {
+		resources.Add("vsphere:tag-category", categoryID)
+	}
+	return resources, nil
+}
+
+// taggedObjects returns the IDs of the tag category and the tag of the
+// cluster, or empty strings when they do not exist, and the objects
+// attached to the tag.
+func (o *ClusterUninstaller) taggedObjects(ctx context.Context, manager *tags.Manager) (categoryID string, tagID string, objects []vimtypes.ManagedObjectReference, err error) {
+	categoryID, tagID, err = vsphereasset.FindTag(ctx, manager, vsphereasset.TagCategoryName(o.InfraID), o.InfraID)
+	if err != nil || tagID == "" {
+		return categoryID, "", nil, err
+	}
+
+	refs, err := manager.ListAttachedObjects(ctx, tagID)
+	if err != nil {
+		return "", "", nil, errors.Wrapf(err, "list the objects tagged %s", o.InfraID)
+	}
+	for _, ref := range refs {
+		objects = append(objects, ref.Reference())
+	}
+	return categoryID, tagID, objects, nil
+}
+
+// clusterFolder returns the ID of the folder of the cluster, or an empty
+// string if it does not exist.
+func (o *ClusterUninstaller) clusterFolder(ctx context.Context, client *vsphereasset.Client) (string, error) {
+	if o.Datacenter == "" || o.Folder == "" {
+		return "", nil
+	}
+
+	finder := find.NewFinder(client.SOAP.Client, false)
+	datacenter, err := finder.Datacenter(ctx, o.Datacenter)
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "find datacenter %s", o.Datacenter)
+	}
+
+	folder, err := finder.Folder(ctx, datacenter.InventoryPath+"/vm/"+strings.Trim(o.Folder, "/"))
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	return folder.Reference().Value, nil
+}
+
+// deleteVirtualMachine powers off and deletes the virtual machine.
+func deleteVirtualMachine(ctx context.Context, vm *object.VirtualMachine) error {
+	powerState, err := vm.PowerState(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if powerState != vimtypes.VirtualMachinePowerStatePoweredOff {
+		task, err := vm.PowerOff(ctx)
+		if err == nil {
+			err = task.Wait(ctx)
+		}
+		if err != nil && !isNotFound(err) {
+			return errors.Wrap(err, "power off")
+		}
+	}
+	task, err := vm.Destroy(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// errFolderNotEmpty is returned when deleting a folder holding virtual
+// machines or other folders, which are left since destroying a folder
+// destroys its contents.
+var errFolderNotEmpty = errors.New("the folder is not empty")
+
+// deleteFolder deletes the folder if it is empty.
+func deleteFolder(ctx context.Context, folder *object.Folder) error {
+	var properties mo.Folder
+	if err := folder.Properties(ctx, folder.Reference(), []string{"childEntity"}, &properties); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(properties.ChildEntity) > 0 {
+		return errFolderNotEmpty
+	}
+
+	task, err := folder.Destroy(ctx)
+	if err == nil {
+		err = task.Wait(ctx)
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isNotFound returns true if the error is about an object which does not
+// exist anymore.
+func isNotFound(err error) bool {
+	var fault interface{}
+	switch {
+	case soap.IsSoapFault(err):
+		fault = soap.ToSoapFault(err).VimFault()
+	case soap.IsVimFault(err):
+		fault = soap.ToVimFault(err)
+	default:
+		if taskErr, ok := err.(task.Error); ok {
+			fault = taskErr.Fault()
+		}
+	}
+	switch fault.(type) {
+	case vimtypes.ManagedObjectNotFound, *vimtypes.ManagedObjectNotFound:
+		return true
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+
+	vsphereasset "github.com/openshift/installer/pkg/asset/cluster/vsphere"
+)
+
+// vCenter is a vcsim vCenter with the virtual machines and folders of the
+// cluster mycluster-abcde in the folder mycluster-abcde, and a virtual
+// machine of the cluster other-fghij in the folder shared.
+type vCenter struct {
+	t      *testing.T
+	model  *simulator.Model
+	server *simulator.Server
+	client *vsphereasset.Client
+	finder *find.Finder
+}
+
+func newVCenter(t *testing.T) *vCenter {
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	model.Service.Listen = &url.URL{User: url.UserPassword("admin", "secret")}
+	server := model.Service.NewServer()
+	model.Service.Handle(vapi.New(server.URL, nil))
+	v := &vCenter{t: t, model: model, server: server}
+
+	ctx := context.Background()
+	client, err := vsphereasset.NewClient(ctx, server.URL.String(), "admin", "secret")
+	if err != nil {
+		v.close()
+		t.Fatal(err)
+	}
+	v.client = client
+	v.finder = find.NewFinder(client.SOAP.Client, false)
+	datacenter, err := v.finder.Datacenter(ctx, "DC0")
+	if err != nil {
+		v.close()
+		t.Fatal(err)
+	}
+	v.finder.SetDatacenter(datacenter)
+	return v
+}
+
+func (v *vCenter) close() {
+	if v.client != nil {
+		v.client.Logout(context.Background())
+	}
+	v.server.Close()
+	v.model.Remove()
+}
+
+// setup creates the folders, moves the virtual machines and tags them.
+func (v *vCenter) setup() {
+	ctx := context.Background()
+	vmFolder, err := v.finder.Folder(ctx, "/DC0/vm")
+	v.check(err)
+	clusterFolder := v.createFolder(vmFolder, "mycluster-abcde")
+	v.createFolder(vmFolder, "mycluster")
+	sharedFolder := v.createFolder(vmFolder, "shared")
+
+	manager := tags.NewManager(v.client.REST)
+	tagID, err := vsphereasset.CreateTag(ctx, manager, "openshift-mycluster-abcde", "mycluster-abcde")
+	v.check(err)
+	otherTagID, err := vsphereasset.CreateTag(ctx, manager, "openshift-other-fghij", "other-fghij")
+	v.check(err)
+
+	for name, folder := range map[string]*object.Folder{
+		"DC0_H0_VM0":     clusterFolder,
+		"DC0_H0_VM1":     clusterFolder,
+		"DC0_C0_RP0_VM0": sharedFolder,
+	} {
+		vm, err := v.finder.VirtualMachine(ctx, name)
+		v.check(err)
+		task, err := folder.MoveInto(ctx, []types.ManagedObjectReference{vm.Reference()})
+		v.check(err)
+		v.check(task.Wait(ctx))
+		if folder == sharedFolder {
+			v.check(manager.AttachTag(ctx, otherTagID, vm))
+		} else {
+			v.check(manager.AttachTag(ctx, tagID, vm))
+		}
+	}
+	vm, err := v.finder.VirtualMachine(ctx, "mycluster-abcde/DC0_H0_VM1")
+	v.check(err)
+	task, err := vm.PowerOff(ctx)
+	v.check(err)
+	v.check(task.Wait(ctx))
+
+	v.check(manager.AttachTag(ctx, tagID, clusterFolder))
+	pool, err := v.finder.ResourcePool(ctx, "/DC0/host/DC0_C0/Resources")
+	v.check(err)
+	v.check(manager.AttachTag(ctx, tagID, pool))
+}
+
+func (v *vCenter) createFolder(parent *object.Folder, name string) *object.Folder {
+	folder, err := parent.CreateFolder(context.Background(), name)
+	v.check(err)
+	return folder
+}
+
+func (v *vCenter) check(err error) {
+	if err != nil {
+		v.close()
+		v.t.Fatal(err)
+	}
+}
+
+// exists returns the inventory paths, relative to the VM folder of the
+// datacenter, of the given virtual machines and folders that exist.
+func (v *vCenter) exists(paths ...string) []string {
+	existing := []string{}
+	for _, path := range paths {
+		elements, err := v.finder.ManagedObjectList(context.Background(), "/DC0/vm/"+path)
+		v.check(err)
+		if len(elements) > 0 {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+// categories returns the names of the tag categories.
+func (v *vCenter) categories() []string {
+	categories, err := tags.NewManager(v.client.REST).GetCategories(context.Background())
+	v.check(err)
+	names := []string{}
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v *vCenter) uninstaller(logs *bytes.Buffer) *ClusterUninstaller {
+	logger := logrus.New()
+	logger.Out = logs
+	return &ClusterUninstaller{
+		VCenter:    v.server.URL.String(),
+		Username:   "admin",
+		Password:   "secret",
+		InfraID:    "mycluster-abcde",
+		Datacenter: "DC0",
+		Folder:     "mycluster",
+		Logger:     logger,
+	}
+}
+
+var objects = []string{
+	"mycluster-abcde",
+	"mycluster-abcde/DC0_H0_VM0",
+	"mycluster-abcde/DC0_H0_VM1",
+	"mycluster",
+	"shared",
+	"shared/DC0_C0_RP0_VM0",
+}
+
+func TestRun(t *testing.T) {
+	vCenter := newVCenter(t)
+	defer vCenter.close()
+	vCenter.setup()
+
+	var logs bytes.Buffer
+	if !assert.NoError(t, vCenter.uninstaller(&logs).Run(), logs.String()) {
+		return
+	}
+
+	assert.Equal(t, []string{"shared", "shared/DC0_C0_RP0_VM0"}, vCenter.exists(objects...))
+	_, err := vCenter.finder.ResourcePool(context.Background(), "/DC0/host/DC0_C0/Resources")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"openshift-other-fghij"}, vCenter.categories())
+}
+
+func TestRunSharedFolder(t *testing.T) {
+	vCenter := newVCenter(t)
+	defer vCenter.close()
+	vCenter.setup()
+
+	var logs bytes.Buffer
+	uninstaller := vCenter.uninstaller(&logs)
+	uninstaller.Folder = "shared"
+	if !assert.NoError(t, uninstaller.Run(), logs.String()) {
+		return
+	}
+
+	assert.Equal(t, []string{"mycluster", "shared", "shared/DC0_C0_RP0_VM0"}, vCenter.exists(objects...))
+	assert.Contains(t, logs.String(), "Leaving the folder, which holds resources outside of the cluster")
+}
+
+func TestRunInvalidLogin(t *testing.T) {
+	vCenter := newVCenter(t)
+	defer vCenter.close()
+	vCenter.setup()
+
+	var logs bytes.Buffer
+	uninstaller := vCenter.uninstaller(&logs)
+	uninstaller.Password = "wrong"
+	err := uninstaller.Run()
+	assert.True(t, vsphereasset.IsInvalidLogin(err), "unexpected error %v", err)
+	assert.Equal(t, objects, vCenter.exists(objects...))
+	assert.Equal(t, []string{"openshift-mycluster-abcde", "openshift-other-fghij"}, vCenter.categories())
+}
+
+func TestList(t *testing.T) {
+	vCenter := newVCenter(t)
+	defer vCenter.close()
+	vCenter.setup()
+
+	var logs bytes.Buffer
+	resources, err := vCenter.uninstaller(&logs).List()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, resources.IDs("vsphere:virtual-machine"), 2)
+	assert.Len(t, resources.IDs("vsphere:folder"), 2)
+	assert.Len(t, resources.IDs("vsphere:tag"), 1)
+	assert.Len(t, resources.IDs("vsphere:tag-category"), 1)
+}
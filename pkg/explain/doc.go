@@ -0,0 +1,3 @@
+// Package explain describes the fields of the install-config, in the
+// spirit of 'kubectl explain'.
+package explain
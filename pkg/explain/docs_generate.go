@@ -0,0 +1,147 @@
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const importPathBase = "github.com/openshift/installer/pkg/"
+
+// packages are the directories, relative to pkg/, of the packages whose
+// types are documented by explain.
+var packages = []string{
+	"types",
+	"types/aws",
+	"types/azure",
+	"types/libvirt",
+	"types/none",
+	"types/openstack",
+	"types/vsphere",
+}
+
+type typeDoc struct {
+	name   string
+	doc    string
+	fields map[string]string
+}
+
+func main() {
+	output := flag.String("output", "docs_generated.go", "the path of the generated file")
+	flag.Parse()
+
+	var docs []typeDoc
+	for _, pkg := range packages {
+		pkgDocs, err := packageDocs(filepath.Join("..", filepath.FromSlash(pkg)), path.Join(importPathBase, pkg))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		docs = append(docs, pkgDocs...)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].name < docs[j].name })
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by docs_generate.go. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package explain")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var typeDocs = map[string]typeDoc{")
+	for _, d := range docs {
+		fmt.Fprintf(buf, "%q: {\n", d.name)
+		fmt.Fprintf(buf, "doc: %q,\n", d.doc)
+		fmt.Fprintln(buf, "fields: map[string]string{")
+		fieldNames := make([]string, 0, len(d.fields))
+		for name := range d.fields {
+			fieldNames = append(fieldNames, name)
+		}
+		sort.Strings(fieldNames)
+		for _, name := range fieldNames {
+			fmt.Fprintf(buf, "%q: %q,\n", name, d.fields[name])
+		}
+		fmt.Fprintln(buf, "},")
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := ioutil.WriteFile(*output, data, 0644); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// packageDocs returns the documentation of the struct types in the package
+// in the given directory.
+func packageDocs(dir string, importPath string) ([]typeDoc, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []typeDoc
+	for _, astPkg := range pkgs {
+		pkg := doc.New(astPkg, importPath, doc.AllDecls)
+		for _, t := range pkg.Types {
+			for _, spec := range t.Decl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				d := typeDoc{
+					name:   importPath + "." + typeSpec.Name.Name,
+					doc:    strings.TrimSpace(t.Doc),
+					fields: map[string]string{},
+				}
+				for _, field := range structType.Fields.List {
+					text := strings.TrimSpace(field.Doc.Text())
+					if len(field.Names) == 0 {
+						d.fields[embeddedName(field.Type)] = text
+					}
+					for _, name := range field.Names {
+						d.fields[name.Name] = text
+					}
+				}
+				docs = append(docs, d)
+			}
+		}
+	}
+	return docs, nil
+}
+
+// embeddedName returns the field name of an embedded field with the given
+// type.
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
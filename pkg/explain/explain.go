@@ -0,0 +1,353 @@
+//go:generate go run docs_generate.go
+
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/defaults"
+)
+
+// typeDoc is the documentation of a struct type.
+type typeDoc struct {
+	// doc is the doc comment of the type.
+	doc string
+	// fields maps the names of the fields of the type to their doc
+	// comments.
+	fields map[string]string
+}
+
+// resource is a type that can be at the root of an explained path.
+type resource struct {
+	kind    string
+	version string
+	typ     reflect.Type
+	// defaults returns an instance of the resource with defaults set for
+	// the given path. It is nil if the resource has no defaults.
+	defaults func(path []string) interface{}
+}
+
+var resources = map[string]resource{
+	"installconfig": {
+		kind:     "InstallConfig",
+		version:  types.InstallConfigVersion,
+		typ:      reflect.TypeOf(types.InstallConfig{}),
+		defaults: installConfigDefaults,
+	},
+	"machinepool": {
+		kind: "MachinePool",
+		typ:  reflect.TypeOf(types.MachinePool{}),
+	},
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// field is a field of a struct, as it appears in JSON.
+type field struct {
+	name string
+	typ  reflect.Type
+	// index is the sequence of Go struct fields leading to the field,
+	// which is longer than one for the fields of inlined structs.
+	index    []int
+	doc      string
+	optional bool
+}
+
+// Explain writes the documentation of the resource or field at the given
+// path to w. The path is a dot-separated sequence starting with the name of
+// the resource, followed by the JSON names of the fields, for example
+// "installconfig.platform.aws.defaultMachinePlatform.rootVolume".
+func Explain(w io.Writer, path string) error {
+	elements := strings.Split(path, ".")
+	res, ok := resources[strings.ToLower(elements[0])]
+	if !ok {
+		return errors.Errorf("unknown resource %q", elements[0])
+	}
+
+	var defaulted reflect.Value
+	if res.defaults != nil {
+		defaulted = reflect.ValueOf(res.defaults(elements[1:]))
+	}
+
+	target := field{
+		name: elements[0],
+		typ:  res.typ,
+		doc:  docOf(res.typ),
+	}
+	for i, element := range elements[1:] {
+		f, ok := lookupField(target.typ, element)
+		if !ok {
+			return errors.Errorf("field %q does not exist in %s", element, strings.Join(elements[:i+1], "."))
+		}
+		target = f
+		defaulted = valueOf(defaulted, f.index)
+	}
+
+	fmt.Fprintf(w, "KIND:     %s\n", res.kind)
+	if res.version != "" {
+		fmt.Fprintf(w, "VERSION:  %s\n", res.version)
+	}
+	fmt.Fprintln(w)
+
+	structType := elemType(target.typ)
+	if len(elements) > 1 {
+		header := "RESOURCE"
+		if structType.Kind() != reflect.Struct || isLeaf(structType) {
+			header = "FIELD"
+		}
+		fmt.Fprintf(w, "%s:    %s <%s>\n\n", header, target.name, typeName(target.typ))
+	}
+
+	fmt.Fprintln(w, "DESCRIPTION:")
+	description := describe(target.doc)
+	if description == "" && structType.Kind() == reflect.Struct && !isLeaf(structType) {
+		description = describe(docOf(structType))
+	}
+	if description == "" {
+		description = "<empty>"
+	}
+	writeIndented(w, "    ", description)
+
+	if def := defaultOf(defaulted); def != "" {
+		fmt.Fprintf(w, "\nDEFAULT:  %s\n", def)
+	}
+
+	if structType.Kind() != reflect.Struct || isLeaf(structType) {
+		return nil
+	}
+
+	fields := fieldsOf(structType)
+	if len(fields) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "\nFIELDS:")
+	for _, f := range fields {
+		required := ""
+		if !f.optional {
+			required = " -required-"
+		}
+		fmt.Fprintf(w, "   %s\t<%s>%s\n", f.name, typeName(f.typ), required)
+		if def := defaultOf(valueOf(defaulted, f.index)); def != "" {
+			fmt.Fprintf(w, "     Default: %s\n", def)
+		}
+		if description := describe(f.doc); description != "" {
+			writeIndented(w, "     ", description)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// installConfigDefaults returns an install config with defaults set. If the
+// path selects a platform, the defaults are set for that platform.
+func installConfigDefaults(path []string) interface{} {
+	config := &types.InstallConfig{}
+	for i, element := range path {
+		if i == 0 || !strings.EqualFold(path[i-1], "platform") {
+			continue
+		}
+		platform := reflect.ValueOf(&config.Platform).Elem()
+		if f, ok := lookupField(platform.Type(), element); ok {
+			p := platform.FieldByIndex(f.index)
+			p.Set(reflect.New(p.Type().Elem()))
+		}
+	}
+	defaults.SetInstallConfigDefaults(config)
+	return config
+}
+
+// lookupField returns the field of the given type with the given JSON name.
+func lookupField(t reflect.Type, name string) (field, bool) {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct || isLeaf(t) {
+		return field{}, false
+	}
+	for _, f := range fieldsOf(t) {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldsOf returns the fields of the given struct type, as they appear in
+// JSON, with the fields of inlined structs flattened.
+func fieldsOf(t reflect.Type) []field {
+	var fields []field
+	typeDoc := typeDocs[typeKey(t)]
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(sf.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" && sf.Anonymous {
+			for _, f := range fieldsOf(elemType(sf.Type)) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		doc := typeDoc.fields[sf.Name]
+		optional := false
+		for _, line := range strings.Split(doc, "\n") {
+			if strings.TrimSpace(line) == "+optional" {
+				optional = true
+			}
+		}
+		for _, option := range tag[1:] {
+			if option == "omitempty" {
+				optional = true
+			}
+		}
+		fields = append(fields, field{
+			name:     name,
+			typ:      sf.Type,
+			index:    []int{i},
+			doc:      doc,
+			optional: optional,
+		})
+	}
+	return fields
+}
+
+// valueOf returns the field of the given struct value at the given index.
+// It returns the zero Value if the struct value is not valid or if any of
+// the pointers leading to the field are nil. The first element of slices is
+// used as a stand-in for all of the elements.
+func valueOf(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = elemValue(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+func elemValue(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		switch v.Kind() {
+		case reflect.Ptr:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		case reflect.Slice:
+			if v.Len() == 0 {
+				return reflect.Value{}
+			}
+			v = v.Index(0)
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// defaultOf returns the JSON representation of the given defaulted value,
+// or an empty string if the value was not defaulted. Structs are not
+// considered defaulted, since their fields have defaults of their own.
+func defaultOf(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface()) {
+		return ""
+	}
+	if t := elemType(v.Type()); t.Kind() == reflect.Struct && !isLeaf(t) && v.Kind() != reflect.Slice {
+		return ""
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// elemType returns the type of the elements of pointers, slices and maps.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// isLeaf returns true if the given type has its own JSON representation,
+// in which case its fields are not explained.
+func isLeaf(t reflect.Type) bool {
+	return t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)
+}
+
+// typeName returns the name of the given type as it appears in JSON.
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeName(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", typeName(t.Key()), typeName(t.Elem()))
+	case reflect.Struct:
+		if isLeaf(t) {
+			// All of the types in pkg/types with their own JSON
+			// representation are serialized as strings.
+			return "string"
+		}
+		return "object"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return t.Kind().String()
+	}
+}
+
+func typeKey(t reflect.Type) string {
+	return t.PkgPath() + "." + t.Name()
+}
+
+func docOf(t reflect.Type) string {
+	return typeDocs[typeKey(elemType(t))].doc
+}
+
+// describe strips the markers, such as "+optional", from a doc comment.
+func describe(doc string) string {
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "+") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func writeIndented(w io.Writer, indent string, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(w, "%s%s\n", indent, line)
+	}
+}
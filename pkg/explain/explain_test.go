@@ -0,0 +1,167 @@
+package explain
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	cases := []struct {
+		name        string
+		path        string
+		contains    []string
+		notContains []string
+		err         string
+	}{
+		{
+			name: "install config",
+			path: "installconfig",
+			contains: []string{
+				"KIND:     InstallConfig\nVERSION:  v1beta4\n",
+				"InstallConfig is the configuration for an OpenShift install.",
+				"   baseDomain\t<string> -required-\n     BaseDomain is the base domain to which the cluster should belong.\n",
+				"   controlPlane\t<object>\n",
+				"   compute\t<[]object>\n",
+				"   apiVersion\t<string>\n",
+				"   networking\t<object>\n",
+			},
+			notContains: []string{
+				"RESOURCE:",
+				"+optional",
+				"TypeMeta",
+			},
+		},
+		{
+			name: "case-insensitive path",
+			path: "InstallConfig.Platform.AWS",
+			contains: []string{
+				"RESOURCE:    aws <object>\n",
+				"AWS is the configuration used when installing on AWS.",
+				"   region\t<string> -required-\n",
+				"   userTags\t<map[string]string>\n",
+			},
+		},
+		{
+			name: "nested field",
+			path: "installconfig.platform.aws.defaultMachinePlatform.rootVolume",
+			contains: []string{
+				"RESOURCE:    rootVolume <object>\n",
+				"EC2RootVolume defines the storage for ec2 instance.",
+				"   iops\t<integer> -required-\n     IOPS defines the iops for the storage.\n",
+			},
+		},
+		{
+			name: "leaf field",
+			path: "installconfig.networking.machineCIDR",
+			contains: []string{
+				"FIELD:    machineCIDR <string>\n",
+				"MachineCIDR is the IP address space from which to assign machine IPs.",
+				"DEFAULT:  \"10.0.0.0/16\"\n",
+			},
+			notContains: []string{
+				"FIELDS:",
+			},
+		},
+		{
+			name: "platform defaults",
+			path: "installconfig.platform.libvirt.network",
+			contains: []string{
+				"   if\t<string>\n     Default: \"tt0\"\n",
+			},
+		},
+		{
+			name: "platform defaults of parent",
+			path: "installconfig.platform.libvirt",
+			contains: []string{
+				"   URI\t<string>\n     Default: \"qemu+tcp://192.168.122.1/system\"\n",
+			},
+		},
+		{
+			name: "defaults of list elements",
+			path: "installconfig.compute",
+			contains: []string{
+				"   name\t<string> -required-\n     Default: \"worker\"\n",
+				"   replicas\t<integer>\n     Default: 3\n",
+			},
+		},
+		{
+			name: "machine pool",
+			path: "machinepool.platform.aws",
+			contains: []string{
+				"KIND:     MachinePool\n\n",
+				"   zones\t<[]string>\n",
+				"   type\t<string> -required-\n",
+			},
+		},
+		{
+			name: "unknown resource",
+			path: "machineset",
+			err:  `unknown resource "machineset"`,
+		},
+		{
+			name: "unknown field",
+			path: "installconfig.platform.gcp",
+			err:  `field "gcp" does not exist in installconfig.platform`,
+		},
+		{
+			name: "field of a leaf",
+			path: "installconfig.baseDomain.name",
+			err:  `field "name" does not exist in installconfig.baseDomain`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := Explain(buf, tc.path)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, s := range tc.contains {
+				assert.Contains(t, buf.String(), s)
+			}
+			for _, s := range tc.notContains {
+				assert.NotContains(t, buf.String(), s)
+			}
+		})
+	}
+}
+
+// TestDocsGenerated checks that docs_generated.go is up to date with the
+// doc comments of the types.
+func TestDocsGenerated(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "openshift-install-explain-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "docs_generated.go")
+	if output, err := exec.Command("go", "run", "docs_generate.go", "-output", path).CombinedOutput(); err != nil {
+		t.Fatalf("failed to generate the docs: %v\n%s", err, output)
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := ioutil.ReadFile("docs_generated.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatal("docs_generated.go is stale, run 'go generate ./pkg/explain'")
+	}
+}
@@ -0,0 +1,480 @@
+// Package bootstrap gathers diagnostics from the bootstrap node.
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vincent-petithory/dataurl"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+const (
+	// journalGatewayPort is the port on which systemd-journal-gatewayd
+	// listens on the bootstrap node.
+	journalGatewayPort = 19531
+
+	// manifestsDir is the directory on the bootstrap node holding the
+	// manifests it was provisioned with.
+	manifestsDir = "/opt/openshift/"
+
+	// podLogsDir is the directory on the bootstrap node holding the logs
+	// of the containers of the pods run by the kubelet, including the
+	// static pods.
+	podLogsDir = "/var/log/pods"
+
+	// sshUser is the user as which the installer connects to the
+	// bootstrap node over SSH.
+	sshUser = "core"
+)
+
+// units are the systemd units whose journals are gathered. The output of
+// the containers run by bootkube.service with podman is part of its
+// journal, but the output of the containers of the pods run by the
+// kubelet is not, and is gathered over SSH.
+var units = []string{
+	"bootkube.service",
+	"openshift.service",
+	"progress.service",
+	"kubelet.service",
+	"crio.service",
+}
+
+// bootstrapInstance describes where the address of the bootstrap node is
+// found in the Terraform state of a platform.
+type bootstrapInstance struct {
+	address string
+	// attributes are the attributes holding the address, in order of
+	// preference.
+	attributes []string
+}
+
+// bootstrapAddresses are the resources holding the address of the
+// bootstrap node, in order of preference. On Azure, the addresses are not
+// attributes of the virtual machine, but of its public IP and network
+// interface.
+type bootstrapAddresses []bootstrapInstance
+
+var bootstrapInstances = map[string]bootstrapAddresses{
+	aws.Name: {{
+		address:    "aws_instance.bootstrap",
+		attributes: []string{"public_ip", "private_ip"},
+	}},
+	azure.Name: {
+		{
+			address:    "azurerm_public_ip.bootstrap_public_ip",
+			attributes: []string{"ip_address"},
+		},
+		{
+			address:    "azurerm_network_interface.bootstrap",
+			attributes: []string{"private_ip_address"},
+		},
+	},
+	libvirt.Name: {{
+		address:    "libvirt_domain.bootstrap",
+		attributes: []string{"network_interface.0.addresses.0"},
+	}},
+	openstack.Name: {{
+		address:    "openstack_compute_instance_v2.bootstrap",
+		attributes: []string{"access_ip_v4", "network.0.fixed_ip_v4"},
+	}},
+	vsphere.Name: {{
+		address:    "vsphere_virtual_machine.bootstrap",
+		attributes: []string{"default_ip_address", "guest_ip_addresses.0"},
+	}},
+}
+
+// Gather pulls the journals and the pod logs of the bootstrap node, and the
+// manifests it was provisioned with, into a tarball in the given directory. It returns
+// the path of the tarball.
+func Gather(directory string, ignitionConfig *igntypes.Config, journalCertKey *tls.JournalCertKey, rootCA *tls.RootCA) (string, error) {
+	ip, err := BootstrapIP(directory)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := journalClient(journalCertKey, rootCA)
+	if err != nil {
+		return "", err
+	}
+
+	tarball := filepath.Join(directory, fmt.Sprintf("log-bundle-%s.tar.gz", time.Now().UTC().Format("20060102150405")))
+	err = writeTarball(tarball, func(tw *tar.Writer) error {
+		return gather(tw, client, ip, ignitionConfig)
+	})
+	if err != nil {
+		os.Remove(tarball)
+		return "", err
+	}
+	return tarball, nil
+}
+
+func gather(tw *tar.Writer, client *http.Client, ip string, ignitionConfig *igntypes.Config) error {
+	type journal struct {
+		name  string
+		query url.Values
+	}
+	journals := []journal{{name: "journal.log", query: url.Values{}}}
+	for _, unit := range units {
+		journals = append(journals, journal{
+			name:  strings.TrimSuffix(unit, ".service") + ".log",
+			query: url.Values{"_SYSTEMD_UNIT": {unit}},
+		})
+	}
+
+	var lastErr error
+	fetched := 0
+	for _, j := range journals {
+		data, err := fetchJournal(client, ip, j.query)
+		if err != nil {
+			logrus.Warnf("Failed to gather %s from the bootstrap node: %v", j.name, err)
+			lastErr = err
+			continue
+		}
+		if err := writeFile(tw, path.Join("bootstrap", "journals", j.name), data); err != nil {
+			return err
+		}
+		fetched++
+	}
+	if fetched == 0 {
+		return errors.Wrapf(lastErr, "failed to gather any journal from the bootstrap node at %s", ip)
+	}
+
+	// The pod logs are only readable over SSH, which may not be
+	// available, so they are gathered on a best-effort basis.
+	if err := gatherPodLogs(tw, ip); err != nil {
+		logrus.Warnf("Failed to gather the pod logs from the bootstrap node: %v", err)
+	}
+
+	if ignitionConfig == nil {
+		return nil
+	}
+	for _, file := range ignitionConfig.Storage.Files {
+		if !strings.HasPrefix(file.Path, manifestsDir) || !isManifest(file.Path) {
+			continue
+		}
+		u, err := dataurl.DecodeString(file.Contents.Source)
+		if err != nil {
+			logrus.Debugf("Skipping %s: %v", file.Path, err)
+			continue
+		}
+		if isSecret(u.Data) {
+			continue
+		}
+		if err := writeFile(tw, path.Join("bootstrap", "manifests", strings.TrimPrefix(file.Path, manifestsDir)), u.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gatherPodLogs pulls the list of the containers of the bootstrap node, and
+// the logs of the containers of its pods, over SSH.
+func gatherPodLogs(tw *tar.Writer, ip string) error {
+	client, err := sshClient(ip)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	containers, err := runCommand(client, "sudo crictl ps --all")
+	if err != nil {
+		return errors.Wrap(err, "failed to list the containers")
+	}
+	if err := writeFile(tw, path.Join("bootstrap", "containers.log"), containers); err != nil {
+		return err
+	}
+
+	// The log files may be symlinks, which are dereferenced so their
+	// contents are included.
+	logs, err := runCommand(client, fmt.Sprintf("sudo tar --create --gzip --dereference --directory %s .", podLogsDir))
+	if err != nil {
+		return errors.Wrap(err, "failed to archive the pod logs")
+	}
+	return copyTarball(tw, bytes.NewReader(logs), path.Join("bootstrap", "pods"))
+}
+
+// sshClient connects to the bootstrap node as the core user,
+// authenticating with the keys of the SSH agent. The bootstrap node is
+// short-lived, so its host key cannot be known beforehand and is not
+// verified.
+func sshClient(ip string) (*ssh.Client, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.New("SSH_AUTH_SOCK is not set, there is no SSH agent to authenticate with")
+	}
+	agentConn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the SSH agent")
+	}
+	defer agentConn.Close()
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(ip, "22"), &ssh.ClientConfig{
+		User:            sshUser,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	})
+	return client, errors.Wrapf(err, "failed to connect to %s over SSH", ip)
+}
+
+// runCommand runs the command on the host of the client and returns its
+// standard output.
+func runCommand(client *ssh.Client, command string) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if err := session.Run(command); err != nil {
+		return nil, errors.Wrap(err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// copyTarball copies the regular files of the gzipped tarball read from r
+// into tw, under the given directory.
+func copyTarball(tw *tar.Writer, r io.Reader, directory string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(tw, path.Join(directory, path.Clean(header.Name)), data); err != nil {
+			return err
+		}
+	}
+}
+
+// BootstrapIP returns the IP address of the bootstrap node, as recorded in
+// the Terraform state in the given directory.
+func BootstrapIP(directory string) (string, error) {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return "", err
+	}
+
+	platform := metadata.Platform()
+	instance, ok := bootstrapInstances[platform]
+	if !ok {
+		return "", errors.Errorf("gathering bootstrap diagnostics is not supported on platform %q", platform)
+	}
+
+	state, err := terraform.ReadState(filepath.Join(directory, terraform.StateFileName))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the Terraform state")
+	}
+	return instance.ip(state)
+}
+
+// ip returns the first address found, or the error of the preferred
+// resource if there is none.
+func (a bootstrapAddresses) ip(state *terraform.State) (string, error) {
+	var err error
+	for _, instance := range a {
+		ip, instanceErr := instance.ip(state)
+		if instanceErr == nil {
+			return ip, nil
+		}
+		if err == nil {
+			err = instanceErr
+		}
+	}
+	return "", err
+}
+
+func (i bootstrapInstance) ip(state *terraform.State) (string, error) {
+	resource, ok := state.Resource([]string{"bootstrap"}, i.address)
+	if !ok {
+		return "", errors.Errorf("%s not found in the Terraform state, the bootstrap node may have been destroyed", i.address)
+	}
+	for _, attribute := range i.attributes {
+		if ip := resource.Primary.Attributes[attribute]; ip != "" {
+			return ip, nil
+		}
+	}
+	return "", errors.Errorf("%s has no IP address in the Terraform state", i.address)
+}
+
+// journalClient returns a client which authenticates with the journal
+// certificate. The certificate of journal-gatewayd is verified against
+// the root CA, but has no IP SANs, so its host name is not verified.
+func journalClient(journalCertKey *tls.JournalCertKey, rootCA *tls.RootCA) (*http.Client, error) {
+	cert, err := cryptotls.X509KeyPair(journalCertKey.Cert(), journalCertKey.Key())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the journal certificate")
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootCA.Cert()) {
+		return nil, errors.New("failed to load the root CA")
+	}
+
+	tlsConfig := &cryptotls.Config{
+		Certificates:       []cryptotls.Certificate{cert},
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no certificate presented")
+			}
+			certs := make([]*x509.Certificate, 0, len(rawCerts))
+			for _, raw := range rawCerts {
+				c, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, c)
+			}
+			intermediates := x509.NewCertPool()
+			for _, c := range certs[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}
+	return &http.Client{
+		Timeout:   5 * time.Minute,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// fetchJournal returns the journal entries of the current boot matching
+// the given query.
+func fetchJournal(client *http.Client, ip string, query url.Values) ([]byte, error) {
+	query.Set("boot", "")
+	u := url.URL{
+		Scheme:   "https",
+		Host:     net.JoinHostPort(ip, strconv.Itoa(journalGatewayPort)),
+		Path:     "/entries",
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+func isManifest(filename string) bool {
+	switch path.Ext(filename) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// isSecret returns true if the manifest is a Secret, which is left out of
+// the tarball so it can be shared.
+func isSecret(data []byte) bool {
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return false
+	}
+	return meta.Kind == "Secret"
+}
+
+func writeTarball(filename string, write func(*tar.Writer) error) (err error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err2 := f.Close(); err == nil {
+			err = err2
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	if err := write(tw); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	_, err = tw.Write(data)
+	return errors.Wrapf(err, "failed to write %s", name)
+}
@@ -0,0 +1,178 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func stateWithBootstrap(address string, attributes map[string]string) *terraform.State {
+	return &terraform.State{
+		Modules: []terraform.StateModule{{
+			Path: []string{"root", "bootstrap"},
+			Resources: map[string]terraform.StateResource{
+				address: {Primary: terraform.StateResourceInstance{Attributes: attributes}},
+			},
+		}},
+	}
+}
+
+func TestBootstrapInstanceIP(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform string
+		state    *terraform.State
+		expected string
+		err      string
+	}{
+		{
+			name:     "aws public",
+			platform: aws.Name,
+			state: stateWithBootstrap("aws_instance.bootstrap", map[string]string{
+				"private_ip": "10.0.1.5",
+				"public_ip":  "203.0.113.5",
+			}),
+			expected: "203.0.113.5",
+		},
+		{
+			name:     "aws private",
+			platform: aws.Name,
+			state: stateWithBootstrap("aws_instance.bootstrap", map[string]string{
+				"private_ip": "10.0.1.5",
+				"public_ip":  "",
+			}),
+			expected: "10.0.1.5",
+		},
+		{
+			name:     "libvirt",
+			platform: libvirt.Name,
+			state: stateWithBootstrap("libvirt_domain.bootstrap", map[string]string{
+				"network_interface.0.addresses.0": "192.168.126.10",
+			}),
+			expected: "192.168.126.10",
+		},
+		{
+			name:     "azure public",
+			platform: azure.Name,
+			state: stateWithBootstrap("azurerm_public_ip.bootstrap_public_ip", map[string]string{
+				"ip_address": "203.0.113.5",
+			}),
+			expected: "203.0.113.5",
+		},
+		{
+			name:     "azure private",
+			platform: azure.Name,
+			state: stateWithBootstrap("azurerm_network_interface.bootstrap", map[string]string{
+				"private_ip_address": "10.0.0.5",
+			}),
+			expected: "10.0.0.5",
+		},
+		{
+			name:     "vsphere",
+			platform: vsphere.Name,
+			state: stateWithBootstrap("vsphere_virtual_machine.bootstrap", map[string]string{
+				"default_ip_address": "10.0.0.10",
+			}),
+			expected: "10.0.0.10",
+		},
+		{
+			name:     "azure destroyed",
+			platform: azure.Name,
+			state:    &terraform.State{},
+			err:      "azurerm_public_ip.bootstrap_public_ip not found in the Terraform state, the bootstrap node may have been destroyed",
+		},
+		{
+			name:     "destroyed",
+			platform: aws.Name,
+			state:    &terraform.State{},
+			err:      "aws_instance.bootstrap not found in the Terraform state, the bootstrap node may have been destroyed",
+		},
+		{
+			name:     "no address",
+			platform: libvirt.Name,
+			state:    stateWithBootstrap("libvirt_domain.bootstrap", map[string]string{}),
+			err:      "libvirt_domain.bootstrap has no IP address in the Terraform state",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ip, err := bootstrapInstances[tc.platform].ip(tc.state)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ip)
+		})
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	assert.True(t, isSecret([]byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: pull-secret\n")))
+	assert.False(t, isSecret([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cluster-config-v1\n")))
+	assert.False(t, isSecret([]byte("{not yaml")))
+}
+
+func TestCopyTarball(t *testing.T) {
+	src := &bytes.Buffer{}
+	gz := gzip.NewWriter(src)
+	tw := tar.NewWriter(gz)
+	for _, header := range []*tar.Header{
+		{Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "./openshift-etcd_etcd-member_1234/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "./openshift-etcd_etcd-member_1234/etcd-member/0.log", Typeflag: tar.TypeReg, Mode: 0644, Size: 5},
+		{Name: "./kube-system_bootstrap-kube-apiserver_5678/kube-apiserver/0.log", Typeflag: tar.TypeReg, Mode: 0644, Size: 6},
+	} {
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write(bytes.Repeat([]byte("x"), int(header.Size))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &bytes.Buffer{}
+	tw = tar.NewWriter(dst)
+	if !assert.NoError(t, copyTarball(tw, src, "bootstrap/pods")) {
+		return
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	tr := tar.NewReader(dst)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+	assert.Equal(t, map[string]string{
+		"bootstrap/pods/openshift-etcd_etcd-member_1234/etcd-member/0.log":              "xxxxx",
+		"bootstrap/pods/kube-system_bootstrap-kube-apiserver_5678/kube-apiserver/0.log": "xxxxxx",
+	}, files)
+}
@@ -0,0 +1,177 @@
+// Package progress reports machine-readable progress events while the
+// installer waits for a cluster to come up. The schema of the events is
+// documented in docs/user/progress-events.md.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SchemaVersion is the version of the event schema. It is bumped whenever
+// a field is removed or changes meaning; new fields may be added without
+// bumping it.
+const SchemaVersion = "v1"
+
+// EventType is the type of an event.
+type EventType string
+
+const (
+	// PhaseStarted is reported when the installer starts waiting on a
+	// phase.
+	PhaseStarted EventType = "PhaseStarted"
+	// PhaseFinished is reported when a phase completes, successfully or
+	// not.
+	PhaseFinished EventType = "PhaseFinished"
+	// ClusterOperatorStatus is reported when the conditions of a
+	// ClusterOperator change.
+	ClusterOperatorStatus EventType = "ClusterOperatorStatus"
+	// BootstrapComplete is reported when the bootstrap-complete event is
+	// observed in the cluster.
+	BootstrapComplete EventType = "BootstrapComplete"
+	// ConsoleAvailable is reported when the URL of the web-console is
+	// known.
+	ConsoleAvailable EventType = "ConsoleAvailable"
+	// InstallComplete is reported when the installation has completed.
+	InstallComplete EventType = "InstallComplete"
+)
+
+// Phase is a phase of the installation.
+type Phase string
+
+const (
+	// PhaseAPI waits for the Kubernetes API to come up.
+	PhaseAPI Phase = "api"
+	// PhaseBootstrap waits for the bootstrap-complete event.
+	PhaseBootstrap Phase = "bootstrap"
+	// PhaseBootstrapDestroy removes the bootstrap resources.
+	PhaseBootstrapDestroy Phase = "bootstrap-destroy"
+	// PhaseInitialization waits for the cluster to be initialized.
+	PhaseInitialization Phase = "initialization"
+	// PhaseConsole waits for the web-console route.
+	PhaseConsole Phase = "console"
+	// PhaseDestroy removes the cluster resources.
+	PhaseDestroy Phase = "destroy"
+)
+
+// Event is a single progress event.
+type Event struct {
+	// Version is the SchemaVersion of the event.
+	Version string `json:"version"`
+
+	// Time is when the event was reported.
+	Time time.Time `json:"time"`
+
+	// Type is the type of the event.
+	Type EventType `json:"type"`
+
+	// Phase is the phase that started or finished, for PhaseStarted and
+	// PhaseFinished events.
+	Phase Phase `json:"phase,omitempty"`
+
+	// Timeout is the maximum duration of the phase, for PhaseStarted
+	// events.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Error is the reason the phase failed, for PhaseFinished events. It
+	// is empty if the phase succeeded.
+	Error string `json:"error,omitempty"`
+
+	// ClusterOperator is the status of the ClusterOperator, for
+	// ClusterOperatorStatus events.
+	ClusterOperator *ClusterOperator `json:"clusterOperator,omitempty"`
+
+	// ConsoleURL is the URL of the web-console, for ConsoleAvailable and
+	// InstallComplete events.
+	ConsoleURL string `json:"consoleURL,omitempty"`
+
+	// Kubeconfig is the path to the admin kubeconfig, for InstallComplete
+	// events.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+
+	// KubeadminPasswordFile is the path to the file holding the password
+	// of the kubeadmin user, for InstallComplete events.
+	KubeadminPasswordFile string `json:"kubeadminPasswordFile,omitempty"`
+}
+
+// ClusterOperator is the status of a ClusterOperator.
+type ClusterOperator struct {
+	// Name is the name of the ClusterOperator.
+	Name string `json:"name"`
+
+	// Conditions are the status conditions of the ClusterOperator.
+	Conditions []Condition `json:"conditions"`
+}
+
+// Condition is a status condition of a ClusterOperator.
+type Condition struct {
+	// Type is the type of the condition, e.g. Available.
+	Type string `json:"type"`
+
+	// Status is one of True, False or Unknown.
+	Status string `json:"status"`
+
+	// Reason is the CamelCase reason for the last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human-readable details of the condition.
+	Message string `json:"message,omitempty"`
+}
+
+// Reporter reports progress events. A nil Reporter discards all of the
+// events, so callers do not need to check whether reporting is enabled.
+type Reporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewJSONReporter returns a Reporter that writes each event to w as a
+// single line of JSON.
+func NewJSONReporter(w io.Writer) *Reporter {
+	return &Reporter{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Report reports the given event, filling in its version and time.
+// Failures to write the event are logged, but do not interrupt the
+// installation.
+func (r *Reporter) Report(e Event) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.Version = SchemaVersion
+	e.Time = r.now().UTC()
+	if err := r.enc.Encode(e); err != nil {
+		logrus.Warnf("Failed to report %s progress event: %v", e.Type, err)
+	}
+}
+
+// PhaseStarted reports that the given phase started.
+func (r *Reporter) PhaseStarted(phase Phase, timeout time.Duration) {
+	e := Event{Type: PhaseStarted, Phase: phase}
+	if timeout > 0 {
+		e.Timeout = timeout.String()
+	}
+	r.Report(e)
+}
+
+// PhaseFinished reports that the given phase finished, with the given
+// error if it failed.
+func (r *Reporter) PhaseFinished(phase Phase, err error) {
+	e := Event{Type: PhaseFinished, Phase: phase}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.Report(e)
+}
@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewJSONReporter(buf)
+	r.now = func() time.Time {
+		return time.Date(2019, 3, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	}
+
+	r.PhaseStarted(PhaseAPI, 30*time.Minute)
+	r.PhaseFinished(PhaseAPI, nil)
+	r.PhaseFinished(PhaseBootstrap, errors.New("timed out"))
+	r.Report(Event{
+		Type: ClusterOperatorStatus,
+		ClusterOperator: &ClusterOperator{
+			Name: "console",
+			Conditions: []Condition{
+				{Type: "Available", Status: "False", Reason: "Deploying"},
+			},
+		},
+	})
+	r.Report(Event{
+		Type:                  InstallComplete,
+		ConsoleURL:            "https://console.example.com",
+		Kubeconfig:            "/dir/auth/kubeconfig",
+		KubeadminPasswordFile: "/dir/auth/kubeadmin-password",
+	})
+
+	expected := `{"version":"v1","time":"2019-03-01T17:00:00Z","type":"PhaseStarted","phase":"api","timeout":"30m0s"}
+{"version":"v1","time":"2019-03-01T17:00:00Z","type":"PhaseFinished","phase":"api"}
+{"version":"v1","time":"2019-03-01T17:00:00Z","type":"PhaseFinished","phase":"bootstrap","error":"timed out"}
+{"version":"v1","time":"2019-03-01T17:00:00Z","type":"ClusterOperatorStatus","clusterOperator":{"name":"console","conditions":[{"type":"Available","status":"False","reason":"Deploying"}]}}
+{"version":"v1","time":"2019-03-01T17:00:00Z","type":"InstallComplete","consoleURL":"https://console.example.com","kubeconfig":"/dir/auth/kubeconfig","kubeadminPasswordFile":"/dir/auth/kubeadmin-password"}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.PhaseStarted(PhaseConsole, time.Minute)
+	r.PhaseFinished(PhaseConsole, nil)
+	r.Report(Event{Type: BootstrapComplete})
+}
@@ -15,10 +15,16 @@ type metadata struct {
 	} `json:"amis"`
 	BaseURI string `json:"baseURI"`
 	Images  struct {
+		Azure struct {
+			Path string `json:"path"`
+		} `json:"azure"`
 		QEMU struct {
 			Path   string `json:"path"`
 			SHA256 string `json:"sha256"`
 		} `json:"qemu"`
+		VMware struct {
+			Path string `json:"path"`
+		} `json:"vmware"`
 	} `json:"images"`
 	OSTreeVersion string `json:"ostree-version"`
 }
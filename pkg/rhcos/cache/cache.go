@@ -0,0 +1,420 @@
+// Package cache manages the local cache of the OS images used by the
+// libvirt platform, at $XDG_CACHE_HOME/openshift-install/libvirt [1].
+//
+// Images with a known SHA-256 checksum, like the RHCOS image of the release,
+// are stored under image/<sha256> once their checksum is verified. Because
+// they are found by checksum alone, a cache populated by Fetch or Import can
+// be used by installs without network access. Other images are keyed on the
+// ETag of their URI, and revalidated through an HTTP disk cache under http/.
+//
+// [1]: https://standards.freedesktop.org/basedir-spec/basedir-spec-0.7.html
+package cache
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gregjones/httpcache"
+	"github.com/gregjones/httpcache/diskcache"
+	"github.com/peterbourgon/diskv"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+var sha256Regexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Cache is a directory of cached images.
+type Cache struct {
+	dir string
+}
+
+// Entry is an image of the cache.
+type Entry struct {
+	// Path is the path of the image file.
+	Path string
+
+	// SHA256 is the checksum the image was verified against, empty for the
+	// images keyed on an ETag.
+	SHA256 string
+
+	// Size is the size of the image in bytes.
+	Size int64
+
+	// LastUsed is the last time the image was added or used by an install.
+	LastUsed time.Time
+}
+
+// Name returns the name of the image in the cache.
+func (e *Entry) Name() string {
+	return filepath.Base(e.Path)
+}
+
+// New returns the cache stored in the given directory.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Default returns the cache stored in the user's cache directory.
+func Default() *Cache {
+	// FIXME: Use os.UserCacheDir() once we bump to Go 1.11
+	baseCacheDir := os.Getenv("XDG_CACHE_HOME")
+	if baseCacheDir == "" {
+		baseCacheDir = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	return New(filepath.Join(baseCacheDir, "openshift-install", "libvirt"))
+}
+
+// Dir returns the directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) imageDir() string {
+	return filepath.Join(c.dir, "image")
+}
+
+func (c *Cache) httpDir() string {
+	return filepath.Join(c.dir, "http")
+}
+
+// Fetch returns the path of the cached copy of the image at uri, downloading
+// it if needed. When sum is set, the image is looked up by its checksum
+// without network access, and a downloaded image must match it. Otherwise
+// the image is keyed on the ETag of uri.
+func (c *Cache) Fetch(uri string, sum string) (string, error) {
+	if sum == "" {
+		return c.fetchByETag(uri)
+	}
+	if !sha256Regexp.MatchString(sum) {
+		return "", errors.Errorf("invalid SHA-256 checksum %q", sum)
+	}
+
+	imagePath, err := c.lookup(sum)
+	if err != nil || imagePath != "" {
+		return imagePath, err
+	}
+
+	resp, err := http.Get(uri)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("%s while getting %s", resp.Status, uri)
+	}
+
+	return c.add(resp.Body, sum)
+}
+
+// Import adds the image file at path to the cache, after checking that it
+// matches sum, and returns the path of the cached copy.
+func (c *Cache) Import(path string, sum string) (string, error) {
+	if !sha256Regexp.MatchString(sum) {
+		return "", errors.Errorf("invalid SHA-256 checksum %q", sum)
+	}
+
+	imagePath, err := c.lookup(sum)
+	if err != nil || imagePath != "" {
+		return imagePath, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return c.add(file, sum)
+}
+
+// lookup returns the path of the image with the given name, or an empty path
+// if it is not cached, and marks the image as used.
+func (c *Cache) lookup(name string) (string, error) {
+	imagePath := filepath.Join(c.imageDir(), name)
+	_, err := os.Stat(imagePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	logrus.Debugf("Using cached OS image %q", imagePath)
+	now := time.Now()
+	if err := os.Chtimes(imagePath, now, now); err != nil {
+		logrus.Debugf("Failed to mark %q as used: %v", imagePath, err)
+	}
+	return imagePath, nil
+}
+
+func (c *Cache) add(reader io.Reader, sum string) (string, error) {
+	err := os.MkdirAll(c.imageDir(), 0777)
+	if err != nil {
+		return "", err
+	}
+
+	imagePath := filepath.Join(c.imageDir(), sum)
+	err = cacheImage(reader, imagePath, sum)
+	if err != nil {
+		return "", err
+	}
+	return imagePath, nil
+}
+
+func (c *Cache) fetchByETag(uri string) (string, error) {
+	err := os.MkdirAll(c.httpDir(), 0777)
+	if err != nil {
+		return "", err
+	}
+
+	cache := diskcache.NewWithDiskv(diskv.New(diskv.Options{
+		BasePath:     c.httpDir(),
+		CacheSizeMax: 0, // This stops the diskcache from caching the resp in memory.
+	}))
+	transport := httpcache.NewTransport(cache)
+	resp, err := transport.Client().Get(uri)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("%s while getting %s", resp.Status, uri)
+	}
+
+	key, err := cacheKey(resp.Header.Get("ETag"))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid ETag for %s", uri)
+	}
+
+	err = os.MkdirAll(c.imageDir(), 0777)
+	if err != nil {
+		return "", err
+	}
+
+	imagePath, err := c.lookup(key)
+	if err != nil || imagePath != "" {
+		return imagePath, err
+	}
+
+	imagePath = filepath.Join(c.imageDir(), key)
+	err = cacheImage(resp.Body, imagePath, "")
+	if err != nil {
+		return "", err
+	}
+	return imagePath, nil
+}
+
+// List returns the images of the cache, the most recently used first.
+func (c *Cache) List() ([]Entry, error) {
+	files, err := ioutil.ReadDir(c.imageDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != "" {
+			continue
+		}
+		entry := Entry{
+			Path:     filepath.Join(c.imageDir(), file.Name()),
+			Size:     file.Size(),
+			LastUsed: file.ModTime(),
+		}
+		if sha256Regexp.MatchString(file.Name()) {
+			entry.SHA256 = file.Name()
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// Verify checks that the image still matches the checksum it was verified
+// against when it was added. The images keyed on an ETag are not checked.
+func Verify(entry Entry) error {
+	if entry.SHA256 == "" {
+		return nil
+	}
+
+	file, err := os.Open(entry.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+	return checkSum(hasher, entry.SHA256)
+}
+
+// Prune removes the images last used before the given time, except the
+// images with the checksums to keep, along with the HTTP disk cache and the
+// files left by interrupted downloads. It returns the removed images.
+func (c *Cache) Prune(before time.Time, keep ...string) ([]Entry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := map[string]bool{}
+	for _, sum := range keep {
+		kept[sum] = true
+	}
+
+	var removed []Entry
+	for _, entry := range entries {
+		if kept[entry.Name()] || !entry.LastUsed.Before(before) {
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil {
+			return removed, err
+		}
+		logrus.Debugf("Removed cached OS image %q", entry.Path)
+		removed = append(removed, entry)
+	}
+
+	temps, err := filepath.Glob(filepath.Join(c.imageDir(), "*.tmp"))
+	if err != nil {
+		return removed, err
+	}
+	for _, temp := range temps {
+		imagePath := strings.TrimSuffix(temp, ".tmp")
+		if _, err := os.Stat(imagePath + ".lock"); err == nil {
+			continue // the download may still be running
+		}
+		if err := os.Remove(temp); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, os.RemoveAll(c.httpDir())
+}
+
+func cacheKey(etag string) (key string, err error) {
+	if etag == "" {
+		return "", fmt.Errorf("caching is not supported when ETag is unset")
+	}
+	etagSections := strings.SplitN(etag, "\"", 3)
+	if len(etagSections) != 3 {
+		return "", fmt.Errorf("broken quoting: %s", etag)
+	}
+	if etagSections[0] == "W/" {
+		return "", fmt.Errorf("caching is not supported for weak ETags: %s", etag)
+	}
+	opaque := etagSections[1]
+	if opaque == "" {
+		return "", fmt.Errorf("caching is not supported when the opaque tag is unset: %s", etag)
+	}
+	hashed := md5.Sum([]byte(opaque))
+	return hex.EncodeToString(hashed[:]), nil
+}
+
+// cacheImage writes the image read from reader to imagePath, checking that
+// it matches sum if it is set.
+func cacheImage(reader io.Reader, imagePath string, sum string) (err error) {
+	logrus.Debugf("Unpacking OS image into %q...", imagePath)
+
+	flockPath := fmt.Sprintf("%s.lock", imagePath)
+	flock, err := os.Create(flockPath)
+	if err != nil {
+		return err
+	}
+	defer flock.Close()
+	defer func() {
+		err2 := os.Remove(flockPath)
+		if err == nil {
+			err = err2
+		}
+	}()
+
+	err = unix.Flock(int(flock.Fd()), unix.LOCK_EX)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err2 := unix.Flock(int(flock.Fd()), unix.LOCK_UN)
+		if err == nil {
+			err = err2
+		}
+	}()
+
+	_, err = os.Stat(imagePath)
+	if err == nil {
+		return nil // another cacheImage beat us to it
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	// With the lock held, a temporary file can only be left by an
+	// interrupted download.
+	tempPath := fmt.Sprintf("%s.tmp", imagePath)
+	err = os.Remove(tempPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return err
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			file.Close()
+		}
+		if err != nil {
+			os.Remove(tempPath)
+		}
+	}()
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), reader)
+	if err != nil {
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	closed = true
+
+	if sum != "" {
+		err = checkSum(hasher, sum)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tempPath, imagePath)
+}
+
+func checkSum(hasher hash.Hash, expected string) error {
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if actual != expected {
+		return errors.Errorf("SHA-256 checksum mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	image    = []byte("qcow2 image")
+	imageSum = checksum(image)
+	otherSum = checksum([]byte("other image"))
+)
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newCache(t *testing.T) (*Cache, func()) {
+	dir, err := ioutil.TempDir("", "openshift-install-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestFetch(t *testing.T) {
+	cases := []struct {
+		name          string
+		sum           string
+		etag          string
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:         "checksum",
+			sum:          imageSum,
+			expectedName: imageSum,
+		},
+		{
+			name:          "checksum mismatch",
+			sum:           otherSum,
+			expectedError: "SHA-256 checksum mismatch: expected " + otherSum + ", got " + imageSum,
+		},
+		{
+			name:          "invalid checksum",
+			sum:           "abc",
+			expectedError: `invalid SHA-256 checksum "abc"`,
+		},
+		{
+			name:         "etag",
+			etag:         `"abc"`,
+			expectedName: "900150983cd24fb0d6963f7d28e17f72",
+		},
+		{
+			name:          "weak etag",
+			etag:          `W/"abc"`,
+			expectedError: `invalid ETag for .*: caching is not supported for weak ETags: W/"abc"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, cleanup := newCache(t)
+			defer cleanup()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.etag != "" {
+					w.Header().Set("ETag", tc.etag)
+				}
+				w.Write(image)
+			}))
+			defer server.Close()
+
+			imagePath, err := c.Fetch(server.URL+"/rhcos-qemu.qcow2", tc.sum)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				entries, err := c.List()
+				assert.NoError(t, err)
+				assert.Empty(t, entries)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, filepath.Join(c.Dir(), "image", tc.expectedName), imagePath)
+			data, err := ioutil.ReadFile(imagePath)
+			if assert.NoError(t, err) {
+				assert.Equal(t, image, data)
+			}
+		})
+	}
+}
+
+func TestFetchOffline(t *testing.T) {
+	c, cleanup := newCache(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(image)
+	}))
+	uri := server.URL + "/rhcos-qemu.qcow2"
+	expected, err := c.Fetch(uri, imageSum)
+	server.Close()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	imagePath, err := c.Fetch(uri, imageSum)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, imagePath)
+	}
+}
+
+func TestImport(t *testing.T) {
+	c, cleanup := newCache(t)
+	defer cleanup()
+
+	source := filepath.Join(c.Dir(), "rhcos-qemu.qcow2")
+	if err := ioutil.WriteFile(source, image, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Import(source, otherSum)
+	assert.EqualError(t, err, "SHA-256 checksum mismatch: expected "+otherSum+", got "+imageSum)
+
+	imagePath, err := c.Import(source, imageSum)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, filepath.Join(c.Dir(), "image", imageSum), imagePath)
+
+	entries, err := c.List()
+	if assert.NoError(t, err) && assert.Len(t, entries, 1) {
+		assert.Equal(t, imageSum, entries[0].SHA256)
+		assert.Equal(t, int64(len(image)), entries[0].Size)
+		assert.NoError(t, Verify(entries[0]))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c, cleanup := newCache(t)
+	defer cleanup()
+
+	imageDir := filepath.Join(c.Dir(), "image")
+	if err := os.MkdirAll(imageDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	files := map[string]time.Time{
+		imageSum:                           now.Add(-48 * time.Hour),
+		otherSum:                           now.Add(-48 * time.Hour),
+		"900150983cd24fb0d6963f7d28e17f72": now,
+		otherSum + ".tmp":                  now,
+	}
+	for name, lastUsed := range files {
+		path := filepath.Join(imageDir, name)
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, lastUsed, lastUsed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := c.Prune(now.Add(-24*time.Hour), imageSum)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, removed, 1) {
+		assert.Equal(t, otherSum, removed[0].Name())
+	}
+
+	entries, err := c.List()
+	if assert.NoError(t, err) {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		assert.Equal(t, []string{"900150983cd24fb0d6963f7d28e17f72", imageSum}, names)
+	}
+	_, err = os.Stat(filepath.Join(imageDir, otherSum+".tmp"))
+	assert.True(t, os.IsNotExist(err), "the temporary file was not removed")
+}
@@ -0,0 +1,33 @@
+package rhcos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// OVA fetches the URL of the VMware OVA of the Red Hat Enterprise Linux
+// CoreOS release.
+func OVA(ctx context.Context) (string, error) {
+	meta, err := fetchRHCOSBuild(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch RHCOS metadata")
+	}
+
+	if meta.Images.VMware.Path == "" {
+		return "", errors.New("no RHCOS OVA found for vSphere, set OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE to the URL of one")
+	}
+
+	base, err := url.Parse(meta.BaseURI)
+	if err != nil {
+		return "", err
+	}
+
+	relOVA, err := url.Parse(meta.Images.VMware.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(relOVA).String(), nil
+}
@@ -26,3 +26,18 @@ func QEMU(ctx context.Context) (string, error) {
 
 	return base.ResolveReference(relQEMU).String(), nil
 }
+
+// QEMUSHA256 fetches the SHA-256 checksum of the QEMU image of the Red Hat
+// Enterprise Linux CoreOS release.
+func QEMUSHA256(ctx context.Context) (string, error) {
+	meta, err := fetchRHCOSBuild(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch RHCOS metadata")
+	}
+
+	if meta.Images.QEMU.SHA256 == "" {
+		return "", errors.New("no checksum found for the RHCOS QEMU image")
+	}
+
+	return meta.Images.QEMU.SHA256, nil
+}
@@ -0,0 +1,33 @@
+package rhcos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// VHD fetches the URL of the Azure VHD of the Red Hat Enterprise Linux
+// CoreOS release.
+func VHD(ctx context.Context) (string, error) {
+	meta, err := fetchRHCOSBuild(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch RHCOS metadata")
+	}
+
+	if meta.Images.Azure.Path == "" {
+		return "", errors.New("no RHCOS VHD found for Azure, set OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE to the URL of one")
+	}
+
+	base, err := url.Parse(meta.BaseURI)
+	if err != nil {
+		return "", err
+	}
+
+	relVHD, err := url.Parse(meta.Images.Azure.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(relVHD).String(), nil
+}
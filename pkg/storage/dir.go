@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Dir is a backend storing the files in a local directory.
+type Dir struct {
+	directory string
+}
+
+var _ Backend = (*Dir)(nil)
+
+// NewDir returns a backend storing the files in the given directory.
+func NewDir(directory string) *Dir {
+	return &Dir{directory: directory}
+}
+
+func (d *Dir) path(name string) string {
+	return filepath.Join(d.directory, filepath.FromSlash(name))
+}
+
+// Read returns the contents of the file with the given name.
+func (d *Dir) Read(name string) ([]byte, error) {
+	return ioutil.ReadFile(d.path(name))
+}
+
+// Write creates or replaces the file with the given name, creating its
+// parent directories if needed. The backends do not know which files hold
+// secrets, like the state file, the kubeconfig or the TLS keys, so the
+// files are only readable by their owner.
+func (d *Dir) Write(name string, data []byte) error {
+	path := d.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Delete removes the file with the given name, and the parent directories
+// left empty, up to the directory of the backend.
+func (d *Dir) Delete(name string) error {
+	path := d.path(name)
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	root := filepath.Clean(d.directory)
+	for dir := filepath.Dir(path); dir != root && dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the sorted names of all of the files in the directory and
+// its subdirectories.
+func (d *Dir) List() ([]string, error) {
+	var names []string
+	err := filepath.Walk(d.directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == d.directory {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(d.directory, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(name))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,4 @@
+// Package storage stores the files of asset directories, like the state
+// file, metadata.json and the Terraform state, in backends such as the local
+// filesystem or an S3-compatible object store.
+package storage
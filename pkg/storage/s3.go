@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// S3 is a backend storing the files as the objects of an S3 bucket, under
+// a key prefix.
+type S3 struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+var _ Backend = (*S3)(nil)
+
+// NewS3 returns a backend storing the files in the given bucket, under the
+// given key prefix. The credentials are loaded like those of the AWS CLI.
+// When endpoint is not empty, it is used instead of the AWS endpoint, for
+// S3-compatible object stores like MinIO.
+func NewS3(bucket, prefix, region, endpoint string) (*S3, error) {
+	config := aws.NewConfig()
+	if region != "" {
+		config = config.WithRegion(region)
+	}
+	if endpoint != "" {
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	ssn, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the AWS session")
+	}
+	return &S3{
+		client: s3.New(ssn),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (b *S3) key(name string) string {
+	return path.Join(b.prefix, name)
+}
+
+// Read returns the contents of the file with the given name.
+func (b *S3) Read(name string) ([]byte, error) {
+	output, err := b.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(name)),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, notExist("read", name)
+		}
+		return nil, errors.Wrapf(err, "failed to get s3://%s/%s", b.bucket, b.key(name))
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+// Write creates or replaces the file with the given name.
+func (b *S3) Write(name string, data []byte) error {
+	_, err := b.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(name)),
+		Body:   bytes.NewReader(data),
+	})
+	return errors.Wrapf(err, "failed to put s3://%s/%s", b.bucket, b.key(name))
+}
+
+// Delete removes the file with the given name.
+func (b *S3) Delete(name string) error {
+	_, err := b.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(name)),
+	})
+	return errors.Wrapf(err, "failed to delete s3://%s/%s", b.bucket, b.key(name))
+}
+
+// List returns the sorted names of all of the files under the prefix.
+func (b *S3) List() ([]string, error) {
+	input := &s3.ListObjectsV2Input{Bucket: aws.String(b.bucket)}
+	prefix := ""
+	if b.prefix != "" {
+		prefix = b.prefix + "/"
+		input.Prefix = aws.String(prefix)
+	}
+
+	var names []string
+	err := b.client.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(object.Key), prefix)
+			if name == "" || strings.HasSuffix(name, "/") {
+				continue
+			}
+			names = append(names, name)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list s3://%s/%s", b.bucket, prefix)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Backend stores files by name. Names are slash-separated paths relative
+// to the root of the backend, like "auth/kubeconfig".
+type Backend interface {
+	// Read returns the contents of the file with the given name. If there
+	// is no such file, the error satisfies os.IsNotExist.
+	Read(name string) ([]byte, error)
+
+	// Write creates or replaces the file with the given name.
+	Write(name string, data []byte) error
+
+	// Delete removes the file with the given name. It is not an error if
+	// there is no such file.
+	Delete(name string) error
+
+	// List returns the sorted names of all of the files.
+	List() ([]string, error)
+}
+
+// New returns the backend for the given URL. The scheme of the URL selects
+// the backend:
+//
+//   - file:///path/to/dir, or a path without a scheme, for a directory.
+//   - memory://name for an in-memory backend, shared by all of the calls
+//     with the same name in the process.
+//   - s3://bucket/prefix for an S3 bucket. The "region" query parameter
+//     sets the region of the bucket, and the "endpoint" query parameter
+//     sets the endpoint of an S3-compatible object store, like MinIO.
+func New(rawURL string) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storage URL")
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		if u.Path == "" {
+			return nil, errors.Errorf("no directory in storage URL %q", rawURL)
+		}
+		return NewDir(u.Path), nil
+	case "memory":
+		return namedMemory(u.Host), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, errors.Errorf("no bucket in storage URL %q", rawURL)
+		}
+		query := u.Query()
+		return NewS3(u.Host, strings.Trim(u.Path, "/"), query.Get("region"), query.Get("endpoint"))
+	default:
+		return nil, errors.Errorf("unsupported storage URL scheme %q", u.Scheme)
+	}
+}
+
+func notExist(op string, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+}
+
+// Memory is a backend holding the files in memory, mostly for tests.
+type Memory struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+var _ Backend = (*Memory)(nil)
+
+// NewMemory returns an empty in-memory backend.
+func NewMemory() *Memory {
+	return &Memory{files: map[string][]byte{}}
+}
+
+var (
+	memoriesLock sync.Mutex
+	memories     = map[string]*Memory{}
+)
+
+func namedMemory(name string) *Memory {
+	memoriesLock.Lock()
+	defer memoriesLock.Unlock()
+	m, ok := memories[name]
+	if !ok {
+		m = NewMemory()
+		memories[name] = m
+	}
+	return m
+}
+
+// Read returns the contents of the file with the given name.
+func (m *Memory) Read(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, notExist("read", name)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Write creates or replaces the file with the given name.
+func (m *Memory) Write(name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// Delete removes the file with the given name.
+func (m *Memory) Delete(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, name)
+	return nil
+}
+
+// List returns the sorted names of all of the files.
+func (m *Memory) List() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	backends := map[string]Backend{
+		"dir":    NewDir(dir),
+		"memory": NewMemory(),
+	}
+	for name, b := range backends {
+		t.Run(name, func(t *testing.T) {
+			names, err := b.List()
+			assert.NoError(t, err)
+			assert.Empty(t, names)
+
+			_, err = b.Read("metadata.json")
+			assert.True(t, os.IsNotExist(err), "unexpected error: %v", err)
+
+			assert.NoError(t, b.Write("metadata.json", []byte("{}")))
+			assert.NoError(t, b.Write("auth/kubeconfig", []byte("kubeconfig")))
+			assert.NoError(t, b.Write(".openshift_install_state.json", []byte("state")))
+
+			data, err := b.Read("auth/kubeconfig")
+			assert.NoError(t, err)
+			assert.Equal(t, "kubeconfig", string(data))
+
+			names, err = b.List()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{".openshift_install_state.json", "auth/kubeconfig", "metadata.json"}, names)
+
+			assert.NoError(t, b.Delete("metadata.json"))
+			assert.NoError(t, b.Delete("metadata.json"))
+			_, err = b.Read("metadata.json")
+			assert.True(t, os.IsNotExist(err), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestDirWriteMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewDir(dir)
+	assert.NoError(t, b.Write("auth/kubeconfig", []byte("kubeconfig")))
+	info, err := os.Stat(filepath.Join(dir, "auth", "kubeconfig"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestDirDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewDir(dir)
+	assert.NoError(t, b.Write("openshift/99_openshift-cluster-api_master-machines-0.yaml", []byte("machine")))
+	assert.NoError(t, b.Write("openshift/99_openshift-cluster-api_master-machines-1.yaml", []byte("machine")))
+
+	assert.NoError(t, b.Delete("openshift/99_openshift-cluster-api_master-machines-0.yaml"))
+	_, err = os.Stat(filepath.Join(dir, "openshift"))
+	assert.NoError(t, err, "non-empty directories should be kept")
+
+	assert.NoError(t, b.Delete("openshift/99_openshift-cluster-api_master-machines-1.yaml"))
+	_, err = os.Stat(filepath.Join(dir, "openshift"))
+	assert.True(t, os.IsNotExist(err), "empty directories should be removed: %v", err)
+	_, err = os.Stat(dir)
+	assert.NoError(t, err, "the directory of the backend should be kept")
+}
+
+func TestCopyAndMirror(t *testing.T) {
+	src := NewMemory()
+	src.Write("metadata.json", []byte("metadata"))
+	src.Write("terraform.tfstate", []byte("tfstate"))
+
+	dst := NewMemory()
+	dst.Write("install-config.yaml", []byte("install-config"))
+	dst.Write("metadata.json", []byte("old"))
+
+	assert.NoError(t, Copy(dst, src))
+	names, _ := dst.List()
+	assert.Equal(t, []string{"install-config.yaml", "metadata.json", "terraform.tfstate"}, names)
+	data, _ := dst.Read("metadata.json")
+	assert.Equal(t, "metadata", string(data))
+
+	assert.NoError(t, Mirror(dst, src))
+	names, _ = dst.List()
+	assert.Equal(t, []string{"metadata.json", "terraform.tfstate"}, names)
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected Backend
+		err      string
+	}{
+		{url: "/tmp/cluster", expected: NewDir("/tmp/cluster")},
+		{url: "file:///tmp/cluster", expected: NewDir("/tmp/cluster")},
+		{url: "file://", err: `no directory in storage URL "file://"`},
+		{url: "s3:///prefix", err: `no bucket in storage URL "s3:///prefix"`},
+		{url: "gs://bucket/prefix", err: `unsupported storage URL scheme "gs"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			b, err := New(tc.url)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, b)
+		})
+	}
+
+	a, err := New("memory://ci")
+	assert.NoError(t, err)
+	a.Write("metadata.json", []byte("{}"))
+	b, err := New("memory://ci")
+	assert.NoError(t, err)
+	assert.True(t, a == b, "expected the same in-memory backend for the same name")
+	c, err := New("memory://other")
+	assert.NoError(t, err)
+	assert.False(t, a == c, "expected different in-memory backends for different names")
+}
+
+func TestNewS3(t *testing.T) {
+	b, err := New("s3://bucket/clusters/ci?region=us-east-1&endpoint=http://localhost:9000")
+	assert.NoError(t, err)
+	s3, ok := b.(*S3)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "bucket", s3.bucket)
+	assert.Equal(t, "clusters/ci", s3.prefix)
+	assert.Equal(t, "clusters/ci/metadata.json", s3.key("metadata.json"))
+	assert.Equal(t, "http://localhost:9000", *s3.client.Config.Endpoint)
+	assert.True(t, *s3.client.Config.S3ForcePathStyle)
+}
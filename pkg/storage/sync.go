@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Copy copies all of the files of the source backend to the destination
+// backend. The other files of the destination backend are left as they are.
+func Copy(dst, src Backend) error {
+	_, err := copyFiles(dst, src)
+	return err
+}
+
+// Mirror copies all of the files of the source backend to the destination
+// backend, and deletes the files of the destination backend which are not
+// in the source backend, so that both hold the same files.
+func Mirror(dst, src Backend) error {
+	copied, err := copyFiles(dst, src)
+	if err != nil {
+		return err
+	}
+
+	existing, err := dst.List()
+	if err != nil {
+		return err
+	}
+	for _, name := range existing {
+		if copied[name] {
+			continue
+		}
+		if err := dst.Delete(name); err != nil {
+			return errors.Wrapf(err, "failed to delete %s", name)
+		}
+	}
+	return nil
+}
+
+func copyFiles(dst, src Backend) (map[string]bool, error) {
+	names, err := src.List()
+	if err != nil {
+		return nil, err
+	}
+
+	copied := make(map[string]bool, len(names))
+	for _, name := range names {
+		data, err := src.Read(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", name)
+		}
+		if err := dst.Write(name, data); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", name)
+		}
+		copied[name] = true
+	}
+	return copied, nil
+}
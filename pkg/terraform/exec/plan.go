@@ -0,0 +1,92 @@
+package exec
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Action is the action planned for a resource.
+type Action string
+
+const (
+	// Create means the resource will be created.
+	Create Action = "create"
+	// Update means the resource will be updated in place.
+	Update Action = "update"
+	// Replace means the resource will be destroyed and re-created.
+	Replace Action = "replace"
+	// Delete means the resource will be destroyed.
+	Delete Action = "delete"
+)
+
+// ResourceChange is a change to a single resource in a plan.
+type ResourceChange struct {
+	// Module is the path of the module containing the resource, for
+	// example "vpc". It is empty for resources in the root module.
+	Module string
+	// Address is the address of the resource in its module, for example
+	// "aws_vpc.new_vpc".
+	Address string
+	// Action is the action planned for the resource.
+	Action Action
+}
+
+// ReadPlan reads the plan file written by `terraform plan -out` and returns
+// the planned changes to managed resources, sorted by module and address.
+func ReadPlan(path string) ([]ResourceChange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	plan, err := terraform.ReadPlan(f)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Diff == nil {
+		return nil, nil
+	}
+
+	var changes []ResourceChange
+	for _, module := range plan.Diff.Modules {
+		for address, diff := range module.Resources {
+			// Data sources are read, not changed.
+			if strings.HasPrefix(address, "data.") {
+				continue
+			}
+			var action Action
+			switch diff.ChangeType() {
+			case terraform.DiffCreate:
+				action = Create
+			case terraform.DiffUpdate:
+				action = Update
+			case terraform.DiffDestroyCreate:
+				action = Replace
+			case terraform.DiffDestroy:
+				action = Delete
+			default:
+				continue
+			}
+			modulePath := module.Path
+			if len(modulePath) > 0 && modulePath[0] == "root" {
+				modulePath = modulePath[1:]
+			}
+			changes = append(changes, ResourceChange{
+				Module:  strings.Join(modulePath, "."),
+				Address: address,
+				Action:  action,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Module != changes[j].Module {
+			return changes[i].Module < changes[j].Module
+		}
+		return changes[i].Address < changes[j].Address
+	})
+	return changes, nil
+}
@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	createDiff = &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"id": {NewComputed: true, RequiresNew: true},
+		},
+	}
+	updateDiff = &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"tags.Name": {Old: "old", New: "new"},
+		},
+	}
+	replaceDiff = &terraform.InstanceDiff{
+		Destroy: true,
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"ami": {Old: "ami-old", New: "ami-new", RequiresNew: true},
+		},
+	}
+	deleteDiff = &terraform.InstanceDiff{Destroy: true}
+	noOpDiff   = &terraform.InstanceDiff{}
+)
+
+func TestReadPlan(t *testing.T) {
+	cases := []struct {
+		name     string
+		diff     *terraform.Diff
+		expected []ResourceChange
+	}{
+		{
+			name: "no diff",
+		},
+		{
+			name: "no changes",
+			diff: &terraform.Diff{
+				Modules: []*terraform.ModuleDiff{{
+					Path: []string{"root"},
+					Resources: map[string]*terraform.InstanceDiff{
+						"aws_vpc.new_vpc": noOpDiff,
+					},
+				}},
+			},
+		},
+		{
+			name: "actions",
+			diff: &terraform.Diff{
+				Modules: []*terraform.ModuleDiff{{
+					Path: []string{"root"},
+					Resources: map[string]*terraform.InstanceDiff{
+						"aws_instance.bootstrap": createDiff,
+						"aws_instance.master.0":  replaceDiff,
+						"aws_s3_bucket.ignition": deleteDiff,
+						"aws_vpc.new_vpc":        updateDiff,
+						"aws_subnet.private":     noOpDiff,
+					},
+				}},
+			},
+			expected: []ResourceChange{
+				{Address: "aws_instance.bootstrap", Action: Create},
+				{Address: "aws_instance.master.0", Action: Replace},
+				{Address: "aws_s3_bucket.ignition", Action: Delete},
+				{Address: "aws_vpc.new_vpc", Action: Update},
+			},
+		},
+		{
+			name: "data sources are skipped",
+			diff: &terraform.Diff{
+				Modules: []*terraform.ModuleDiff{{
+					Path: []string{"root"},
+					Resources: map[string]*terraform.InstanceDiff{
+						"data.aws_ami.rhcos":     createDiff,
+						"aws_instance.bootstrap": createDiff,
+					},
+				}},
+			},
+			expected: []ResourceChange{
+				{Address: "aws_instance.bootstrap", Action: Create},
+			},
+		},
+		{
+			name: "modules are sorted",
+			diff: &terraform.Diff{
+				Modules: []*terraform.ModuleDiff{
+					{
+						Path: []string{"root", "vpc"},
+						Resources: map[string]*terraform.InstanceDiff{
+							"aws_vpc.new_vpc": createDiff,
+						},
+					},
+					{
+						Path: []string{"root", "masters"},
+						Resources: map[string]*terraform.InstanceDiff{
+							"aws_instance.master.1": createDiff,
+							"aws_instance.master.0": createDiff,
+						},
+					},
+					{
+						Path: []string{"root"},
+						Resources: map[string]*terraform.InstanceDiff{
+							"aws_route53_zone.int": deleteDiff,
+						},
+					},
+				},
+			},
+			expected: []ResourceChange{
+				{Address: "aws_route53_zone.int", Action: Delete},
+				{Module: "masters", Address: "aws_instance.master.0", Action: Create},
+				{Module: "masters", Address: "aws_instance.master.1", Action: Create},
+				{Module: "vpc", Address: "aws_vpc.new_vpc", Action: Create},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "openshift-install-plan-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "terraform.tfplan")
+			f, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = terraform.WritePlan(&terraform.Plan{Diff: tc.diff}, f)
+			f.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changes, err := ReadPlan(path)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, changes)
+			}
+		})
+	}
+}
+
+func TestReadPlanInvalid(t *testing.T) {
+	f, err := ioutil.TempFile("", "openshift-install-plan-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"format_version": "0.1"}`)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadPlan(f.Name())
+	assert.EqualError(t, err, "not a valid plan file")
+}
@@ -0,0 +1,70 @@
+package terraform
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// State is the subset of a Terraform state file that is used by the
+// installer.
+type State struct {
+	Modules []StateModule `json:"modules"`
+}
+
+// StateModule is a module in a Terraform state file.
+type StateModule struct {
+	// Path is the path of the module, starting with "root".
+	Path []string `json:"path"`
+
+	// Resources maps the addresses of the resources of the module, like
+	// "aws_instance.bootstrap", to their state.
+	Resources map[string]StateResource `json:"resources"`
+}
+
+// StateResource is a resource in a Terraform state file.
+type StateResource struct {
+	Type    string                `json:"type"`
+	Primary StateResourceInstance `json:"primary"`
+}
+
+// StateResourceInstance is an instance of a resource in a Terraform state
+// file.
+type StateResourceInstance struct {
+	ID string `json:"id"`
+
+	// Attributes are the flattened attributes of the instance, like
+	// "network_interface.0.addresses.0".
+	Attributes map[string]string `json:"attributes"`
+}
+
+// ReadState reads the Terraform state file at the given path.
+func ReadState(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", path)
+	}
+	return state, nil
+}
+
+// Resource returns the resource with the given address in the module with
+// the given path, excluding "root". It returns false if there is no such
+// resource.
+func (s *State) Resource(module []string, address string) (*StateResource, bool) {
+	path := append([]string{"root"}, module...)
+	for _, m := range s.Modules {
+		if !reflect.DeepEqual(m.Path, path) {
+			continue
+		}
+		r, ok := m.Resources[address]
+		return &r, ok
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testState = `{
+    "version": 3,
+    "terraform_version": "0.11.10",
+    "modules": [
+        {
+            "path": ["root"],
+            "resources": {}
+        },
+        {
+            "path": ["root", "bootstrap"],
+            "resources": {
+                "aws_instance.bootstrap": {
+                    "type": "aws_instance",
+                    "primary": {
+                        "id": "i-0123456789abcdef0",
+                        "attributes": {
+                            "private_ip": "10.0.1.5",
+                            "public_ip": "203.0.113.5"
+                        }
+                    }
+                }
+            }
+        }
+    ]
+}`
+
+func TestReadState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, StateFileName)
+	if !assert.NoError(t, ioutil.WriteFile(path, []byte(testState), 0600)) {
+		return
+	}
+
+	state, err := ReadState(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r, ok := state.Resource([]string{"bootstrap"}, "aws_instance.bootstrap")
+	if assert.True(t, ok) {
+		assert.Equal(t, "aws_instance", r.Type)
+		assert.Equal(t, "i-0123456789abcdef0", r.Primary.ID)
+		assert.Equal(t, "203.0.113.5", r.Primary.Attributes["public_ip"])
+	}
+
+	_, ok = state.Resource(nil, "aws_instance.bootstrap")
+	assert.False(t, ok)
+
+	_, ok = state.Resource([]string{"masters"}, "aws_instance.bootstrap")
+	assert.False(t, ok)
+}
@@ -21,6 +21,9 @@ const (
 
 	// VarFileName is the default name for Terraform var file.
 	VarFileName string = "terraform.tfvars"
+
+	// PlanFileName is the default name for Terraform plan files.
+	PlanFileName string = "terraform.tfplan"
 )
 
 // Apply unpacks the platform-specific Terraform modules into the
@@ -56,6 +59,40 @@ func Apply(dir string, platform string, extraArgs ...string) (path string, err e
 	return sf, nil
 }
 
+// Plan unpacks the platform-specific Terraform modules into the
+// given directory and then runs 'terraform init' and 'terraform
+// plan'.  It returns the changes Terraform would make to create the
+// cluster, without making any of them.
+func Plan(dir string, platform string, extraArgs ...string) ([]texec.ResourceChange, error) {
+	err := unpackAndInit(dir, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := filepath.Join(dir, PlanFileName)
+	defaultArgs := []string{
+		"-input=false",
+		fmt.Sprintf("-state=%s", filepath.Join(dir, StateFileName)),
+		fmt.Sprintf("-out=%s", pf),
+	}
+	args := append(defaultArgs, extraArgs...)
+	args = append(args, dir)
+
+	tDebug := &lineprinter.Trimmer{WrappedPrint: logrus.Debug}
+	tError := &lineprinter.Trimmer{WrappedPrint: logrus.Error}
+	lpDebug := &lineprinter.LinePrinter{Print: tDebug.Print}
+	lpError := &lineprinter.LinePrinter{Print: tError.Print}
+	defer lpDebug.Close()
+	defer lpError.Close()
+
+	if exitCode := texec.Plan(dir, args, lpDebug, lpError); exitCode != 0 {
+		return nil, errors.New("failed to plan using Terraform")
+	}
+
+	changes, err := texec.ReadPlan(pf)
+	return changes, errors.Wrap(err, "failed to read Terraform plan")
+}
+
 // Destroy unpacks the platform-specific Terraform modules into the
 // given directory and then runs 'terraform init' and 'terraform
 // destroy'.
@@ -0,0 +1,51 @@
+// Package azure contains Azure-specific Terraform-variable logic.
+package azure
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	azureprovider "github.com/openshift/installer/pkg/asset/machines/azure"
+	"github.com/openshift/installer/pkg/types/azure/defaults"
+)
+
+type config struct {
+	Region                      string            `json:"azure_region,omitempty"`
+	ExtraTags                   map[string]string `json:"azure_extra_tags,omitempty"`
+	BootstrapInstanceType       string            `json:"azure_bootstrap_vm_type,omitempty"`
+	MasterInstanceType          string            `json:"azure_master_vm_type,omitempty"`
+	MasterAvailabilityZones     []string          `json:"azure_master_availability_zones"`
+	VolumeSize                  int32             `json:"azure_master_root_volume_size,omitempty"`
+	ImageURL                    string            `json:"azure_image_url,omitempty"`
+	BaseDomainResourceGroupName string            `json:"azure_base_domain_resource_group_name,omitempty"`
+}
+
+// TFVars generates Azure-specific Terraform variables launching the cluster.
+func TFVars(masterConfigs []*azureprovider.AzureMachineProviderSpec, baseDomainResourceGroupName string, imageURL string, userTags map[string]string) ([]byte, error) {
+	if len(masterConfigs) == 0 {
+		return nil, errors.New("master slice cannot be empty")
+	}
+	masterConfig := masterConfigs[0]
+
+	// an empty zone places the master outside of the availability zones
+	masterAvailabilityZones := make([]string, len(masterConfigs))
+	for i, c := range masterConfigs {
+		if c.Zone != nil {
+			masterAvailabilityZones[i] = *c.Zone
+		}
+	}
+
+	cfg := &config{
+		Region:                      masterConfig.Location,
+		ExtraTags:                   userTags,
+		BootstrapInstanceType:       defaults.InstanceClass(masterConfig.Location),
+		MasterInstanceType:          masterConfig.VMSize,
+		MasterAvailabilityZones:     masterAvailabilityZones,
+		VolumeSize:                  masterConfig.OSDisk.DiskSizeGB,
+		ImageURL:                    imageURL,
+		BaseDomainResourceGroupName: baseDomainResourceGroupName,
+	}
+
+	return json.MarshalIndent(cfg, "", "  ")
+}
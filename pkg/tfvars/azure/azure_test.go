@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	azureprovider "github.com/openshift/installer/pkg/asset/machines/azure"
+)
+
+func TestTFVars(t *testing.T) {
+	master := func(zone *string) *azureprovider.AzureMachineProviderSpec {
+		return &azureprovider.AzureMachineProviderSpec{
+			Location: "centralus",
+			VMSize:   "Standard_DS4_v2",
+			OSDisk:   azureprovider.OSDisk{DiskSizeGB: 128},
+			Zone:     zone,
+		}
+	}
+
+	data, err := TFVars(
+		[]*azureprovider.AzureMachineProviderSpec{master(pointer.StringPtr("1")), master(nil), master(pointer.StringPtr("3"))},
+		"dns-rg",
+		"https://example.com/rhcos.vhd",
+		map[string]string{"team": "installer"},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.JSONEq(t, `{
+  "azure_region": "centralus",
+  "azure_extra_tags": {"team": "installer"},
+  "azure_bootstrap_vm_type": "Standard_DS4_v2",
+  "azure_master_vm_type": "Standard_DS4_v2",
+  "azure_master_availability_zones": ["1", "", "3"],
+  "azure_master_root_volume_size": 128,
+  "azure_image_url": "https://example.com/rhcos.vhd",
+  "azure_base_domain_resource_group_name": "dns-rg"
+}`, string(data))
+}
+
+func TestTFVarsNoMasters(t *testing.T) {
+	_, err := TFVars(nil, "dns-rg", "https://example.com/rhcos.vhd", nil)
+	assert.EqualError(t, err, "master slice cannot be empty")
+}
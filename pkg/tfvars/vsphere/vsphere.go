@@ -0,0 +1,63 @@
+// Package vsphere contains vSphere-specific Terraform-variable logic.
+package vsphere
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	clustervsphere "github.com/openshift/installer/pkg/asset/cluster/vsphere"
+	vsphereprovider "github.com/openshift/installer/pkg/asset/machines/vsphere"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+type config struct {
+	Datacenter                    string   `json:"vsphere_datacenter"`
+	Cluster                       string   `json:"vsphere_cluster"`
+	ResourcePool                  string   `json:"vsphere_resource_pool"`
+	Datastore                     string   `json:"vsphere_datastore"`
+	Folder                        string   `json:"vsphere_folder"`
+	Network                       string   `json:"vsphere_network"`
+	Template                      string   `json:"vsphere_template"`
+	TagCategory                   string   `json:"vsphere_tag_category"`
+	Tag                           string   `json:"vsphere_tag"`
+	BootstrapIP                   string   `json:"vsphere_bootstrap_ip"`
+	ControlPlaneIPs               []string `json:"vsphere_control_plane_ips"`
+	DNSServers                    []string `json:"vsphere_dns_servers"`
+	ControlPlaneNumCPUs           int32    `json:"vsphere_control_plane_num_cpus,omitempty"`
+	ControlPlaneNumCoresPerSocket int32    `json:"vsphere_control_plane_cores_per_socket,omitempty"`
+	ControlPlaneMemoryMiB         int64    `json:"vsphere_control_plane_memory_mib,omitempty"`
+	ControlPlaneDiskGiB           int32    `json:"vsphere_control_plane_disk_gib,omitempty"`
+}
+
+// TFVars generates vSphere-specific Terraform variables launching the cluster.
+func TFVars(masterConfigs []*vsphereprovider.VSphereMachineProviderSpec, platform *vsphere.Platform, infraID string) ([]byte, error) {
+	if len(masterConfigs) == 0 {
+		return nil, errors.New("master slice cannot be empty")
+	}
+	if len(masterConfigs) != len(platform.ControlPlaneIPs) {
+		return nil, errors.Errorf("%d control-plane IPs for %d masters", len(platform.ControlPlaneIPs), len(masterConfigs))
+	}
+	masterConfig := masterConfigs[0]
+
+	cfg := &config{
+		Datacenter:                    platform.Workspace.Datacenter,
+		Cluster:                       platform.Cluster,
+		ResourcePool:                  clustervsphere.ResourcePoolPath(platform),
+		Datastore:                     platform.Workspace.DefaultDatastore,
+		Folder:                        platform.Workspace.Folder,
+		Network:                       platform.PublicNetwork,
+		Template:                      masterConfig.Template,
+		TagCategory:                   clustervsphere.TagCategoryName(infraID),
+		Tag:                           infraID,
+		BootstrapIP:                   platform.BootstrapIP,
+		ControlPlaneIPs:               platform.ControlPlaneIPs,
+		DNSServers:                    platform.DNSServers,
+		ControlPlaneNumCPUs:           masterConfig.NumCPUs,
+		ControlPlaneNumCoresPerSocket: masterConfig.NumCoresPerSocket,
+		ControlPlaneMemoryMiB:         masterConfig.MemoryMiB,
+		ControlPlaneDiskGiB:           masterConfig.DiskGiB,
+	}
+
+	return json.MarshalIndent(cfg, "", "  ")
+}
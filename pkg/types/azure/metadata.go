@@ -3,4 +3,13 @@ package azure
 // Metadata contains Azure metadata (e.g. for uninstalling the cluster).
 type Metadata struct {
 	Region string `json:"region"`
+	// ResourceGroupName is the name of the resource group holding the
+	// cluster resources.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+	// BaseDomainResourceGroupName is the name of the resource group
+	// holding the DNS zone of the base domain.
+	BaseDomainResourceGroupName string `json:"baseDomainResourceGroupName,omitempty"`
+	// ClusterDomain is the domain of the cluster, whose records are
+	// removed from the DNS zone of the base domain.
+	ClusterDomain string `json:"clusterDomain,omitempty"`
 }
@@ -1,15 +1,65 @@
 package validation
 
 import (
+	"sort"
+
 	"github.com/openshift/installer/pkg/types/azure"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+var (
+	// Regions is a map of the known Azure regions. The key of the map is
+	// the short name of the region. The value of the map is the long
+	// name of the region.
+	Regions = map[string]string{
+		"australiaeast":      "Australia East",
+		"australiasoutheast": "Australia Southeast",
+		"brazilsouth":        "Brazil South",
+		"canadacentral":      "Canada Central",
+		"canadaeast":         "Canada East",
+		"centralindia":       "Central India",
+		"centralus":          "Central US",
+		"eastasia":           "East Asia",
+		"eastus":             "East US",
+		"eastus2":            "East US 2",
+		"francecentral":      "France Central",
+		"japaneast":          "Japan East",
+		"japanwest":          "Japan West",
+		"koreacentral":       "Korea Central",
+		"koreasouth":         "Korea South",
+		"northcentralus":     "North Central US",
+		"northeurope":        "North Europe",
+		"southcentralus":     "South Central US",
+		"southeastasia":      "Southeast Asia",
+		"southindia":         "South India",
+		"uksouth":            "UK South",
+		"ukwest":             "UK West",
+		"westcentralus":      "West Central US",
+		"westeurope":         "West Europe",
+		"westindia":          "West India",
+		"westus":             "West US",
+		"westus2":            "West US 2",
+	}
+
+	validRegionValues = func() []string {
+		validValues := make([]string, len(Regions))
+		i := 0
+		for r := range Regions {
+			validValues[i] = r
+			i++
+		}
+		sort.Strings(validValues)
+		return validValues
+	}()
+)
+
 // ValidatePlatform checks that the specified platform is valid.
 func ValidatePlatform(p *azure.Platform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if p.Region == "" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("region"), "region should be set to one of the supported Azure regions"))
+	} else if _, ok := Regions[p.Region]; !ok {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("region"), p.Region, validRegionValues))
 	}
 	if p.BaseDomainResourceGroupName == "" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("baseDomainResourceGroupName"), "baseDomainResourceGroupName is the resource group name where the azure dns zone is deployed"))
@@ -17,7 +17,15 @@ func TestValidatePlatform(t *testing.T) {
 		{
 			name: "invalid region",
 			platform: &azure.Platform{
-				Region: "",
+				Region:                      "",
+				BaseDomainResourceGroupName: "group",
+			},
+			valid: false,
+		},
+		{
+			name: "unknown region",
+			platform: &azure.Platform{
+				Region:                      "eastmars",
 				BaseDomainResourceGroupName: "group",
 			},
 			valid: false,
@@ -25,7 +33,7 @@ func TestValidatePlatform(t *testing.T) {
 		{
 			name: "invalid baseDomainResourceGroupName",
 			platform: &azure.Platform{
-				Region: "eastus",
+				Region:                      "eastus",
 				BaseDomainResourceGroupName: "",
 			},
 			valid: false,
@@ -33,7 +41,7 @@ func TestValidatePlatform(t *testing.T) {
 		{
 			name: "minimal",
 			platform: &azure.Platform{
-				Region: "eastus",
+				Region:                      "eastus",
 				BaseDomainResourceGroupName: "group",
 			},
 			valid: true,
@@ -41,7 +49,7 @@ func TestValidatePlatform(t *testing.T) {
 		{
 			name: "valid machine pool",
 			platform: &azure.Platform{
-				Region: "eastus",
+				Region:                      "eastus",
 				BaseDomainResourceGroupName: "group",
 				DefaultMachinePlatform:      &azure.MachinePool{},
 			},
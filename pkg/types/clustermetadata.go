@@ -5,6 +5,7 @@ import (
 	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	"github.com/openshift/installer/pkg/types/openstack"
+	"github.com/openshift/installer/pkg/types/vsphere"
 )
 
 // ClusterMetadata contains information
@@ -25,6 +26,7 @@ type ClusterPlatformMetadata struct {
 	OpenStack *openstack.Metadata `json:"openstack,omitempty"`
 	Libvirt   *libvirt.Metadata   `json:"libvirt,omitempty"`
 	Azure     *azure.Metadata     `json:"azure,omitempty"`
+	VSphere   *vsphere.Metadata   `json:"vsphere,omitempty"`
 }
 
 // Platform returns a string representation of the platform
@@ -46,5 +48,8 @@ func (cpm *ClusterPlatformMetadata) Platform() string {
 	if cpm.Azure != nil {
 		return "azure"
 	}
+	if cpm.VSphere != nil {
+		return "vsphere"
+	}
 	return ""
 }
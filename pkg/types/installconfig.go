@@ -69,7 +69,23 @@ type InstallConfig struct {
 	Platform `json:"platform"`
 
 	// PullSecret is the secret to use when pulling images.
-	PullSecret string `json:"pullSecret"`
+	PullSecret string `json:"pullSecret" secret:"true"`
+
+	// AdditionalTrustBundle is a PEM-encoded X.509 certificate bundle
+	// that is added to the trusted certificate store of the nodes, e.g.
+	// for the certificate authority of a local mirror registry.
+	// +optional
+	AdditionalTrustBundle string `json:"additionalTrustBundle,omitempty"`
+
+	// Proxy is the proxy through which the cluster reaches external
+	// networks.  If unset, the cluster does not use a proxy.
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// ImageContentSources lists the mirrors from which the release-image
+	// content can be pulled instead of its source repositories.
+	// +optional
+	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
@@ -77,6 +93,38 @@ func (c *InstallConfig) ClusterDomain() string {
 	return fmt.Sprintf("%s.%s", c.ObjectMeta.Name, c.BaseDomain)
 }
 
+// Proxy defines the proxy settings of the cluster.  At least one of
+// HTTPProxy and HTTPSProxy must be set.
+type Proxy struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of domains, IPs and CIDRs reached
+	// without the proxy, or "*" to bypass the proxy for all destinations.
+	// The networks and the internal domains of the cluster are always
+	// added to the list.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// ImageContentSource defines a list of mirrors of a source repository.
+// Images pulled by digest from the source repository can be pulled from
+// any of the mirrors instead.
+type ImageContentSource struct {
+	// Source is the repository that users refer to, e.g. in image pull
+	// specifications.
+	Source string `json:"source"`
+
+	// Mirrors is one or more repositories that may also contain the same
+	// images, tried in order before the source.
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
 // Platform is the configuration for the specific platform upon which to perform
 // the installation. Only one of the platform configuration should be set.
 type Platform struct {
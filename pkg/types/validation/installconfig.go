@@ -2,6 +2,8 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -17,6 +19,7 @@ import (
 	libvirtvalidation "github.com/openshift/installer/pkg/types/libvirt/validation"
 	"github.com/openshift/installer/pkg/types/openstack"
 	openstackvalidation "github.com/openshift/installer/pkg/types/openstack/validation"
+	vspherevalidation "github.com/openshift/installer/pkg/types/vsphere/validation"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -73,9 +76,90 @@ func ValidateInstallConfig(c *types.InstallConfig, openStackValidValuesFetcher o
 		allErrs = append(allErrs, field.Required(field.NewPath("controlPlane"), "controlPlane is required"))
 	}
 	allErrs = append(allErrs, validateCompute(&c.Platform, c.Compute, field.NewPath("compute"))...)
+	if c.Platform.VSphere != nil && c.Networking != nil && c.ControlPlane != nil && c.ControlPlane.Replicas != nil {
+		var machineCIDR *net.IPNet
+		if c.Networking.MachineCIDR != nil {
+			machineCIDR = &c.Networking.MachineCIDR.IPNet
+		}
+		allErrs = append(allErrs, vspherevalidation.ValidateProvisioning(c.Platform.VSphere, machineCIDR, *c.ControlPlane.Replicas, field.NewPath("platform", "vsphere"))...)
+	}
 	if err := validate.ImagePullSecret(c.PullSecret); err != nil {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("pullSecret"), c.PullSecret, err.Error()))
 	}
+	if c.Proxy != nil {
+		allErrs = append(allErrs, validateProxy(c.Proxy, field.NewPath("proxy"))...)
+	}
+	if c.AdditionalTrustBundle != "" {
+		if err := validate.CABundle(c.AdditionalTrustBundle); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("additionalTrustBundle"), c.AdditionalTrustBundle, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, validateImageContentSources(c.ImageContentSources, field.NewPath("imageContentSources"))...)
+	return allErrs
+}
+
+func validateProxy(p *types.Proxy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.HTTPProxy == "" && p.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "must include httpProxy or httpsProxy"))
+	}
+	if p.HTTPProxy != "" {
+		allErrs = append(allErrs, validateProxyURL(p.HTTPProxy, []string{"http"}, fldPath.Child("httpProxy"))...)
+	}
+	if p.HTTPSProxy != "" {
+		allErrs = append(allErrs, validateProxyURL(p.HTTPSProxy, []string{"http", "https"}, fldPath.Child("httpsProxy"))...)
+	}
+	if p.NoProxy != "" && p.NoProxy != "*" {
+		for _, v := range strings.Split(p.NoProxy, ",") {
+			v = strings.TrimSpace(v)
+			if net.ParseIP(v) != nil {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(v); err == nil {
+				continue
+			}
+			if err := validate.DomainName(strings.TrimPrefix(v, "."), true); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy"), v, "must be a domain, an IP or a CIDR"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateProxyURL(v string, schemes []string, fldPath *field.Path) field.ErrorList {
+	if err := validate.URI(v); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, v, err.Error())}
+	}
+	parsed, _ := url.Parse(v)
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, parsed.Scheme, schemes)}
+}
+
+func validateImageContentSources(groups []types.ImageContentSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sources := map[string]bool{}
+	for i, group := range groups {
+		groupPath := fldPath.Index(i)
+		if err := validate.ImageRepository(group.Source); err != nil {
+			allErrs = append(allErrs, field.Invalid(groupPath.Child("source"), group.Source, err.Error()))
+		} else if sources[group.Source] {
+			allErrs = append(allErrs, field.Duplicate(groupPath.Child("source"), group.Source))
+		}
+		sources[group.Source] = true
+
+		if len(group.Mirrors) == 0 {
+			allErrs = append(allErrs, field.Required(groupPath.Child("mirrors"), "at least one mirror is required"))
+		}
+		for j, mirror := range group.Mirrors {
+			if err := validate.ImageRepository(mirror); err != nil {
+				allErrs = append(allErrs, field.Invalid(groupPath.Child("mirrors").Index(j), mirror, err.Error()))
+			}
+		}
+	}
 	return allErrs
 }
 
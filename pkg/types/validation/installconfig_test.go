@@ -443,6 +443,150 @@ func TestValidateInstallConfig(t *testing.T) {
 				return c
 			}(),
 		},
+		{
+			name: "invalid installer-provisioned vsphere platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform = types.Platform{
+					VSphere: &vsphere.Platform{
+						Cluster:         "test-cluster",
+						BootstrapIP:     "10.0.0.10",
+						ControlPlaneIPs: []string{"10.0.0.11", "10.0.0.12", "10.1.0.13"},
+						DNSServers:      []string{"10.0.0.2"},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^platform\.vsphere\.controlPlaneIPs\[2]: Invalid value: "10\.1\.0\.13": must be in the machine CIDR 10\.0\.0\.0/16$`,
+		},
+		{
+			name: "valid proxy",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Proxy = &types.Proxy{
+					HTTPProxy:  "http://proxy.example.com:3128",
+					HTTPSProxy: "https://proxy.example.com:3129",
+					NoProxy:    ".example.com, 192.168.0.1,10.10.0.0/16",
+				}
+				return c
+			}(),
+		},
+		{
+			name: "proxy without URLs",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Proxy = &types.Proxy{
+					NoProxy: "*",
+				}
+				return c
+			}(),
+			expectedError: `^proxy: Required value: must include httpProxy or httpsProxy$`,
+		},
+		{
+			name: "invalid proxy URL",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Proxy = &types.Proxy{
+					HTTPProxy: "proxy.example.com",
+				}
+				return c
+			}(),
+			expectedError: `^proxy\.httpProxy: Invalid value: "proxy\.example\.com": invalid URI "proxy\.example\.com" \(no scheme\)$`,
+		},
+		{
+			name: "unsupported proxy scheme",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Proxy = &types.Proxy{
+					HTTPProxy: "https://proxy.example.com:3128",
+				}
+				return c
+			}(),
+			expectedError: `^proxy\.httpProxy: Unsupported value: "https": supported values: "http"$`,
+		},
+		{
+			name: "invalid no proxy",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Proxy = &types.Proxy{
+					HTTPProxy: "http://proxy.example.com:3128",
+					NoProxy:   "example.com,bad_domain",
+				}
+				return c
+			}(),
+			expectedError: `^proxy\.noProxy: Invalid value: "bad_domain": must be a domain, an IP or a CIDR$`,
+		},
+		{
+			name: "valid image content sources",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ImageContentSources = []types.ImageContentSource{
+					{
+						Source:  "quay.io/openshift-release-dev/ocp-release",
+						Mirrors: []string{"mirror.example.com:5000/ocp/release"},
+					},
+					{
+						Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+						Mirrors: []string{"mirror.example.com:5000/ocp/release", "backup.example.com/ocp/release"},
+					},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "invalid image content source",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ImageContentSources = []types.ImageContentSource{
+					{
+						Source:  "quay.io/openshift-release-dev/ocp-release:4.1.0",
+						Mirrors: []string{"mirror.example.com:5000/ocp/release"},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^imageContentSources\[0]\.source: Invalid value: "quay\.io/openshift-release-dev/ocp-release:4\.1\.0": must be a repository without tag or digest, like example\.com/namespace/name$`,
+		},
+		{
+			name: "duplicate image content source",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ImageContentSources = []types.ImageContentSource{
+					{
+						Source:  "quay.io/openshift-release-dev/ocp-release",
+						Mirrors: []string{"mirror.example.com:5000/ocp/release"},
+					},
+					{
+						Source:  "quay.io/openshift-release-dev/ocp-release",
+						Mirrors: []string{"backup.example.com/ocp/release"},
+					},
+				}
+				return c
+			}(),
+			expectedError: `^imageContentSources\[1]\.source: Duplicate value: "quay\.io/openshift-release-dev/ocp-release"$`,
+		},
+		{
+			name: "image content source without mirrors",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.ImageContentSources = []types.ImageContentSource{
+					{
+						Source: "quay.io/openshift-release-dev/ocp-release",
+					},
+				}
+				return c
+			}(),
+			expectedError: `^imageContentSources\[0]\.mirrors: Required value: at least one mirror is required$`,
+		},
+		{
+			name: "invalid additional trust bundle",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.AdditionalTrustBundle = "not a certificate"
+				return c
+			}(),
+			expectedError: `^additionalTrustBundle: Invalid value: "not a certificate": must contain at least one PEM-encoded certificate$`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -3,6 +3,29 @@ package vsphere
 // MachinePool stores the configuration for a machine pool installed
 // on vSphere.
 type MachinePool struct {
+	// NumCPUs is the total number of virtual processor cores to assign a vm.
+	// +optional
+	NumCPUs int32 `json:"cpus,omitempty"`
+
+	// NumCoresPerSocket is the number of cores per socket in a vm. The number
+	// of vCPUs on the vm will be NumCPUs/NumCoresPerSocket.
+	// +optional
+	NumCoresPerSocket int32 `json:"coresPerSocket,omitempty"`
+
+	// MemoryMiB is the size of a VM's memory in MiB.
+	// +optional
+	MemoryMiB int64 `json:"memoryMB,omitempty"`
+
+	// OSDisk defines the storage for instance.
+	// +optional
+	OSDisk `json:"osDisk,omitempty"`
+}
+
+// OSDisk defines the disk for a virtual machine.
+type OSDisk struct {
+	// DiskSizeGB defines the size of disk in GB.
+	// +optional
+	DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
 }
 
 // Set sets the values from `required` to `p`.
@@ -10,4 +33,20 @@ func (p *MachinePool) Set(required *MachinePool) {
 	if required == nil || p == nil {
 		return
 	}
+
+	if required.NumCPUs != 0 {
+		p.NumCPUs = required.NumCPUs
+	}
+
+	if required.NumCoresPerSocket != 0 {
+		p.NumCoresPerSocket = required.NumCoresPerSocket
+	}
+
+	if required.MemoryMiB != 0 {
+		p.MemoryMiB = required.MemoryMiB
+	}
+
+	if required.OSDisk.DiskSizeGB != 0 {
+		p.OSDisk.DiskSizeGB = required.OSDisk.DiskSizeGB
+	}
 }
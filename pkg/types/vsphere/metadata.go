@@ -0,0 +1,14 @@
+package vsphere
+
+// Metadata contains vSphere metadata (e.g. for uninstalling the cluster).
+type Metadata struct {
+	// VCenter is the domain name or the IP address of the vCenter.
+	VCenter string `json:"vCenter"`
+	// Username is the name of the user to use to connect to the vCenter.
+	// Its password is not kept in the metadata, see VSPHERE_PASSWORD.
+	Username string `json:"username"`
+	// Datacenter is the datacenter of the cluster.
+	Datacenter string `json:"datacenter"`
+	// Folder is the vCenter VM folder path of the cluster in the datacenter.
+	Folder string `json:"folder"`
+}
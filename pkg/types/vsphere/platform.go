@@ -12,6 +12,34 @@ type Platform struct {
 	SCSIControllerType string `json:"scsiControllerType"`
 	// PublicNetwork is the name of the VM network to use.
 	PublicNetwork string `json:"publicNetwork"`
+
+	// Cluster is the name of the vSphere cluster in which the installer
+	// provisions the machines.  The installer only provisions the
+	// infrastructure when it is set; otherwise the user provisions it.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// BootstrapIP is the static IP address of the bootstrap machine, in the
+	// machine CIDR.  It is required when Cluster is set.
+	// +optional
+	BootstrapIP string `json:"bootstrapIP,omitempty"`
+
+	// ControlPlaneIPs are the static IP addresses of the control-plane
+	// machines, in the machine CIDR, one per control-plane replica.  They
+	// are required when Cluster is set.
+	// +optional
+	ControlPlaneIPs []string `json:"controlPlaneIPs,omitempty"`
+
+	// DNSServers are the IP addresses of the DNS servers of the bootstrap
+	// and control-plane machines.  They are required when Cluster is set.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// DefaultMachinePlatform is the default configuration used when
+	// installing on vSphere for machine pools which do not define their own
+	// platform configuration.
+	// +optional
+	DefaultMachinePlatform *MachinePool `json:"defaultMachinePlatform,omitempty"`
 }
 
 // VirtualCenter is the configuration of a vCenter.
@@ -21,7 +49,7 @@ type VirtualCenter struct {
 	// Username is the name of the user to use to connect to the vCenter.
 	Username string `json:"username"`
 	// Password is the password for the user to use to connect to the vCenter.
-	Password string `json:"password"`
+	Password string `json:"password" secret:"true"`
 	// Datacenters are the names of the datacenters to use in the vCenter.
 	Datacenters []string `json:"datacenters"`
 }
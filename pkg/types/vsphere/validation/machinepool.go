@@ -8,5 +8,20 @@ import (
 
 // ValidateMachinePool checks that the specified machine pool is valid.
 func ValidateMachinePool(p *vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
-	return field.ErrorList{}
+	allErrs := field.ErrorList{}
+	if p.NumCPUs < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpus"), p.NumCPUs, "number of CPUs must not be negative"))
+	}
+	if p.NumCoresPerSocket < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coresPerSocket"), p.NumCoresPerSocket, "cores per socket must not be negative"))
+	} else if p.NumCPUs > 0 && p.NumCoresPerSocket > 0 && p.NumCPUs%p.NumCoresPerSocket != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coresPerSocket"), p.NumCoresPerSocket, "the number of CPUs must be a multiple of the cores per socket"))
+	}
+	if p.MemoryMiB < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryMB"), p.MemoryMiB, "memory size must not be negative"))
+	}
+	if p.OSDisk.DiskSizeGB < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("osDisk").Child("diskSizeGB"), p.OSDisk.DiskSizeGB, "disk size must not be negative"))
+	}
+	return allErrs
 }
@@ -20,6 +20,41 @@ func TestValidateMachinePool(t *testing.T) {
 			pool:  &vsphere.MachinePool{},
 			valid: true,
 		},
+		{
+			name: "valid",
+			pool: &vsphere.MachinePool{
+				NumCPUs:           4,
+				NumCoresPerSocket: 2,
+				MemoryMiB:         16384,
+				OSDisk:            vsphere.OSDisk{DiskSizeGB: 120},
+			},
+			valid: true,
+		},
+		{
+			name:  "negative CPUs",
+			pool:  &vsphere.MachinePool{NumCPUs: -1},
+			valid: false,
+		},
+		{
+			name:  "negative cores per socket",
+			pool:  &vsphere.MachinePool{NumCoresPerSocket: -1},
+			valid: false,
+		},
+		{
+			name:  "CPUs not a multiple of cores per socket",
+			pool:  &vsphere.MachinePool{NumCPUs: 3, NumCoresPerSocket: 2},
+			valid: false,
+		},
+		{
+			name:  "negative memory",
+			pool:  &vsphere.MachinePool{MemoryMiB: -1},
+			valid: false,
+		},
+		{
+			name:  "negative disk size",
+			pool:  &vsphere.MachinePool{OSDisk: vsphere.OSDisk{DiskSizeGB: -1}},
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1,6 +1,9 @@
 package validation
 
 import (
+	"fmt"
+	"net"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/openshift/installer/pkg/types/vsphere"
@@ -80,3 +83,51 @@ func validateVirtualCenter(vc *vsphere.VirtualCenter, fldPath *field.Path) field
 	}
 	return allErrs
 }
+
+// ValidateProvisioning checks the settings of an installer-provisioned
+// cluster, which are only used when the Cluster of the platform is set: the
+// machine IPs must be in the machine CIDR, with one for each control-plane
+// replica, and the machines need DNS servers.
+func ValidateProvisioning(p *vsphere.Platform, machineCIDR *net.IPNet, controlPlaneReplicas int64, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.Cluster == "" {
+		return allErrs
+	}
+
+	seen := map[string]bool{}
+	validateIP := func(ip string, fldPath *field.Path) {
+		parsed := net.ParseIP(ip)
+		switch {
+		case parsed == nil:
+			allErrs = append(allErrs, field.Invalid(fldPath, ip, "must be an IP address"))
+		case machineCIDR != nil && !machineCIDR.Contains(parsed):
+			allErrs = append(allErrs, field.Invalid(fldPath, ip, fmt.Sprintf("must be in the machine CIDR %s", machineCIDR)))
+		case seen[parsed.String()]:
+			allErrs = append(allErrs, field.Duplicate(fldPath, ip))
+		}
+		if parsed != nil {
+			seen[parsed.String()] = true
+		}
+	}
+
+	if p.BootstrapIP == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("bootstrapIP"), "must specify the IP of the bootstrap machine"))
+	} else {
+		validateIP(p.BootstrapIP, fldPath.Child("bootstrapIP"))
+	}
+	if int64(len(p.ControlPlaneIPs)) != controlPlaneReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("controlPlaneIPs"), p.ControlPlaneIPs, fmt.Sprintf("must specify one IP for each of the %d control-plane replicas", controlPlaneReplicas)))
+	}
+	for i, ip := range p.ControlPlaneIPs {
+		validateIP(ip, fldPath.Child("controlPlaneIPs").Index(i))
+	}
+	if len(p.DNSServers) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("dnsServers"), "must specify at least one DNS server"))
+	}
+	for i, ip := range p.DNSServers {
+		if net.ParseIP(ip) == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsServers").Index(i), ip, "must be an IP address"))
+		}
+	}
+	return allErrs
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types/vsphere"
 )
 
@@ -187,3 +188,101 @@ func TestValidatePlatform(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProvisioning(t *testing.T) {
+	provisioned := func() *vsphere.Platform {
+		p := validPlatform()
+		p.Cluster = "test-cluster"
+		p.BootstrapIP = "10.0.0.10"
+		p.ControlPlaneIPs = []string{"10.0.0.11", "10.0.0.12", "10.0.0.13"}
+		p.DNSServers = []string{"10.0.0.2"}
+		return p
+	}
+	cases := []struct {
+		name          string
+		platform      *vsphere.Platform
+		expectedError string
+	}{
+		{
+			name:     "user-provisioned",
+			platform: validPlatform(),
+		},
+		{
+			name:     "installer-provisioned",
+			platform: provisioned(),
+		},
+		{
+			name: "missing bootstrap IP",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.BootstrapIP = ""
+				return p
+			}(),
+			expectedError: `^test-path\.bootstrapIP: Required value: must specify the IP of the bootstrap machine$`,
+		},
+		{
+			name: "invalid bootstrap IP",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.BootstrapIP = "bad-ip"
+				return p
+			}(),
+			expectedError: `^test-path\.bootstrapIP: Invalid value: "bad-ip": must be an IP address$`,
+		},
+		{
+			name: "IP outside of the machine CIDR",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.ControlPlaneIPs[1] = "10.1.0.12"
+				return p
+			}(),
+			expectedError: `^test-path\.controlPlaneIPs\[1]: Invalid value: "10\.1\.0\.12": must be in the machine CIDR 10\.0\.0\.0/16$`,
+		},
+		{
+			name: "duplicate IP",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.ControlPlaneIPs[2] = "10.0.0.10"
+				return p
+			}(),
+			expectedError: `^test-path\.controlPlaneIPs\[2]: Duplicate value: "10\.0\.0\.10"$`,
+		},
+		{
+			name: "missing control-plane IP",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.ControlPlaneIPs = p.ControlPlaneIPs[:2]
+				return p
+			}(),
+			expectedError: `^test-path\.controlPlaneIPs: Invalid value: \[\]string{"10\.0\.0\.11", "10\.0\.0\.12"}: must specify one IP for each of the 3 control-plane replicas$`,
+		},
+		{
+			name: "missing DNS servers",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.DNSServers = nil
+				return p
+			}(),
+			expectedError: `^test-path\.dnsServers: Required value: must specify at least one DNS server$`,
+		},
+		{
+			name: "invalid DNS server",
+			platform: func() *vsphere.Platform {
+				p := provisioned()
+				p.DNSServers = []string{"dns.example.com"}
+				return p
+			}(),
+			expectedError: `^test-path\.dnsServers\[0]: Invalid value: "dns\.example\.com": must be an IP address$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateProvisioning(tc.platform, &ipnet.MustParseCIDR("10.0.0.0/16").IPNet, 3, field.NewPath("test-path")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@
 package validate
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -19,6 +22,15 @@ var (
 		_, cidr, _ := net.ParseCIDR("172.17.0.0/16")
 		return cidr
 	}()
+
+	// imageRepositoryRegexp matches a repository without tag or digest,
+	// following the grammar of github.com/docker/distribution/reference.
+	imageRepositoryRegexp = func() *regexp.Regexp {
+		domainComponent := `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+		domain := domainComponent + `(?:\.` + domainComponent + `)*(?::[0-9]+)?`
+		pathComponent := `[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*`
+		return regexp.MustCompile(`^(?:` + domain + `/)?` + pathComponent + `(?:/` + pathComponent + `)*$`)
+	}()
 )
 
 func validateSubdomain(v string) error {
@@ -113,3 +125,40 @@ func URI(uri string) error {
 	}
 	return nil
 }
+
+// ImageRepository checks if the given string is a valid image repository,
+// like quay.io/openshift-release-dev/ocp-release, without tag or digest.
+func ImageRepository(v string) error {
+	if !imageRepositoryRegexp.MatchString(v) {
+		return errors.New("must be a repository without tag or digest, like example.com/namespace/name")
+	}
+	return nil
+}
+
+// CABundle checks if the given string is a bundle of one or more
+// PEM-encoded X.509 certificates and returns an error if not.
+func CABundle(v string) error {
+	rest := []byte(v)
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return err
+		}
+		count++
+	}
+	if count == 0 {
+		return errors.New("must contain at least one PEM-encoded certificate")
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return errors.New("unexpected data after the last PEM-encoded certificate")
+	}
+	return nil
+}